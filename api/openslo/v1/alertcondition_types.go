@@ -23,12 +23,14 @@ type AlertConditionSpec struct {
 
 // AlertConditionStatus defines the observed state of AlertCondition
 type AlertConditionStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the AlertCondition is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the AlertCondition resource was created"
 
 // AlertCondition is the Schema for the alertconditions API
 type AlertCondition struct {
@@ -12,12 +12,14 @@ type AlertNotificationTargetSpec struct {
 
 // AlertNotificationTargetStatus defines the observed state of AlertNotificationTarget
 type AlertNotificationTargetStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the AlertNotificationTarget is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the AlertNotificationTarget resource was created"
 
 // AlertNotificationTarget is the Schema for the alertnotificationtargets API
 type AlertNotificationTarget struct {
@@ -0,0 +1,15 @@
+package v1
+
+// v1 is the storage version and the hub the other versions of the OpenSLO API convert through
+
+// Hub marks this type as a conversion hub.
+func (*SLO) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*SLI) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Datasource) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*AlertPolicy) Hub() {}
@@ -20,15 +20,33 @@ type DatasourceSpec struct {
 	ConnectionDetails osko.ConnectionDetails `json:"connectionDetails,omitempty"`
 }
 
+// RulerQueueStatus reports the rule group writes queued for the ruler behind a Datasource
+type RulerQueueStatus struct {
+	// Depth is the number of rule groups waiting to be written to the ruler
+	Depth int `json:"depth"`
+	// Failing is the number of rule groups whose last write was rejected by the ruler
+	Failing      int          `json:"failing,omitempty"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	LastError    string       `json:"lastError,omitempty"`
+}
+
 // DatasourceStatus defines the observed state of Datasource
 type DatasourceStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	RulerQueue         *RulerQueueStatus  `json:"rulerQueue,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// LastProbeTime is when the connection and the ruler configuration were last checked
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:resource:scope=Namespaced
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the Datasource is ready"
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=.spec.type,description="The type of the Datasource"
+//+kubebuilder:printcolumn:name="Queue",type=integer,JSONPath=.status.rulerQueue.depth,description="Rule groups waiting to be written to the ruler"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the Datasource resource was created"
 
 // Datasource is the Schema for the datasources API
 type Datasource struct {
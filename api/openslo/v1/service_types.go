@@ -9,10 +9,35 @@ type ServiceSpec struct {
 	Description Description `json:"description,omitempty"`
 }
 
-type ServiceStatus struct{}
+// ServiceStatus defines the observed state of Service
+type ServiceStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// SLOs are the SLOs in the namespace of the Service whose spec.service is its name
+	SLOs []ServiceSLOStatus `json:"slos,omitempty"`
+	// ReadySLOs is how many of the SLOs are ready, as ready/total
+	ReadySLOs string `json:"readySLOs,omitempty"`
+	// ErrorBudgetRemaining is the lowest share of the error budget left out of the SLOs, the worst case of the Service
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining,omitempty"`
+}
+
+// ServiceSLOStatus is the state of one SLO of a Service
+type ServiceSLOStatus struct {
+	Name string `json:"name"`
+	// Ready is the status of the Ready condition of the SLO
+	Ready metav1.ConditionStatus `json:"ready"`
+	// ErrorBudgetRemaining is the share of the error budget left of the SLO
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining,omitempty"`
+	// BudgetExhausted is true while the BudgetExhausted condition of the SLO is True
+	BudgetExhausted bool `json:"budgetExhausted,omitempty"`
+}
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="SLOs",type=string,JSONPath=.status.readySLOs,description="How many of the SLOs of the Service are ready"
+//+kubebuilder:printcolumn:name="Budget",type=string,JSONPath=.status.errorBudgetRemaining,description="The lowest error budget left out of the SLOs of the Service"
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the Service is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the Service resource was created"
 
 // Service is the Schema for the services API
 type Service struct {
@@ -29,14 +29,34 @@ type SLISpec struct {
 	RatioMetric     RatioMetricSpec     `json:"ratioMetric,omitempty"`
 }
 
+// SLIQueryStatus is the result of running one of the queries of an SLI against its Datasource
+type SLIQueryStatus struct {
+	// Name of the query, one of good, bad, total, raw or threshold
+	Name string `json:"name"`
+	// ReturnsSeries is true when the query returned at least one series
+	ReturnsSeries bool `json:"returnsSeries"`
+	// Series is the number of series the query returned
+	Series int `json:"series"`
+	// Error is the error the Datasource returned for the query
+	Error string `json:"error,omitempty"`
+}
+
 // SLIStatus defines the observed state of SLI
 type SLIStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// SLOs are the names of the SLOs in the namespace that reference or own the SLI
+	SLOs []string `json:"slos,omitempty"`
+	// Queries are the results of the last validation of the SLI queries against the Datasource
+	Queries            []SLIQueryStatus `json:"queries,omitempty"`
+	LastValidationTime metav1.Time      `json:"lastValidationTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the SLI is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLI resource was created"
 
 // SLI is the Schema for the slis API
 type SLI struct {
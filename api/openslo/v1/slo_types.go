@@ -72,12 +72,23 @@ type SLOStatus struct {
 	CurrentSLO         string             `json:"currentSLO,omitempty"`
 	LastEvaluationTime metav1.Time        `json:"lastEvaluationTime,omitempty"`
 	Ready              string             `json:"ready,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// ErrorBudgetRemaining is the share of the error budget left in the SLO window, negative once it is overspent
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining,omitempty"`
+	// FastestBurnRate is the highest error budget burn rate across the recorded windows
+	FastestBurnRate string `json:"fastestBurnRate,omitempty"`
+	// FastestBurnRateWindow is the window the fastest burn rate was recorded over
+	FastestBurnRateWindow string `json:"fastestBurnRateWindow,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=.status.ready,description="The reason for the current status of the SLO resource"
 //+kubebuilder:printcolumn:name="Window",type=string,JSONPath=.spec.timeWindow[0].duration,description="The time window for the SLO resource"
+//+kubebuilder:printcolumn:name="SLI",type=string,JSONPath=.status.currentSLO,description="The SLI measured over the time window"
+//+kubebuilder:printcolumn:name="Budget",type=string,JSONPath=.status.errorBudgetRemaining,description="The share of the error budget left in the time window"
+//+kubebuilder:printcolumn:name="Burn",type=string,JSONPath=.status.fastestBurnRate,description="The fastest error budget burn rate across the recorded windows"
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLO resource was created"
 
 // SLO is the Schema for the slos API
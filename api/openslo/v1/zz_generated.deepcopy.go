@@ -16,7 +16,7 @@ func (in *AlertCondition) DeepCopyInto(out *AlertCondition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMetaOpenSLO.DeepCopyInto(&out.ObjectMetaOpenSLO)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertCondition.
@@ -88,6 +88,13 @@ func (in *AlertConditionSpec) DeepCopy() *AlertConditionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertConditionStatus) DeepCopyInto(out *AlertConditionStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConditionStatus.
@@ -106,7 +113,7 @@ func (in *AlertNotificationTarget) DeepCopyInto(out *AlertNotificationTarget) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMetaOpenSLO.DeepCopyInto(&out.ObjectMetaOpenSLO)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertNotificationTarget.
@@ -177,6 +184,13 @@ func (in *AlertNotificationTargetSpec) DeepCopy() *AlertNotificationTargetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertNotificationTargetStatus) DeepCopyInto(out *AlertNotificationTargetStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertNotificationTargetStatus.
@@ -195,7 +209,7 @@ func (in *AlertPolicy) DeepCopyInto(out *AlertPolicy) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicy.
@@ -332,6 +346,13 @@ func (in *AlertPolicySpec) DeepCopy() *AlertPolicySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertPolicyStatus) DeepCopyInto(out *AlertPolicyStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyStatus.
@@ -405,7 +426,7 @@ func (in *Datasource) DeepCopyInto(out *Datasource) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Datasource.
@@ -477,6 +498,19 @@ func (in *DatasourceSpec) DeepCopy() *DatasourceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasourceStatus) DeepCopyInto(out *DatasourceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RulerQueue != nil {
+		in, out := &in.RulerQueue, &out.RulerQueue
+		*out = new(RulerQueueStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceStatus.
@@ -614,13 +648,32 @@ func (in *RatioMetricSpec) DeepCopy() *RatioMetricSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulerQueueStatus) DeepCopyInto(out *RulerQueueStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulerQueueStatus.
+func (in *RulerQueueStatus) DeepCopy() *RulerQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RulerQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLI) DeepCopyInto(out *SLI) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLI.
@@ -673,6 +726,21 @@ func (in *SLIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLIQueryStatus) DeepCopyInto(out *SLIQueryStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLIQueryStatus.
+func (in *SLIQueryStatus) DeepCopy() *SLIQueryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLIQueryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLISpec) DeepCopyInto(out *SLISpec) {
 	*out = *in
@@ -693,6 +761,24 @@ func (in *SLISpec) DeepCopy() *SLISpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLIStatus) DeepCopyInto(out *SLIStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLOs != nil {
+		in, out := &in.SLOs, &out.SLOs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]SLIQueryStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.LastValidationTime.DeepCopyInto(&out.LastValidationTime)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLIStatus.
@@ -863,7 +949,7 @@ func (in *Service) DeepCopyInto(out *Service) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
@@ -916,6 +1002,21 @@ func (in *ServiceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSLOStatus) DeepCopyInto(out *ServiceSLOStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSLOStatus.
+func (in *ServiceSLOStatus) DeepCopy() *ServiceSLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -934,6 +1035,18 @@ func (in *ServiceSpec) DeepCopy() *ServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLOs != nil {
+		in, out := &in.SLOs, &out.SLOs
+		*out = make([]ServiceSLOStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceStatus.
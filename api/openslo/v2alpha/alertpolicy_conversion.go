@@ -0,0 +1,76 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this AlertPolicy to the hub version (v1)
+func (src *AlertPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*openslov1.AlertPolicy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertAlertPolicySpecTo(src.Spec)
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version
+func (dst *AlertPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*openslov1.AlertPolicy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertAlertPolicySpecFrom(src.Spec)
+	dst.Status = src.Status
+	return nil
+}
+
+func convertAlertPolicySpecTo(src AlertPolicySpec) openslov1.AlertPolicySpec {
+	dst := openslov1.AlertPolicySpec{
+		Description:        src.Description,
+		AlertWhenNoData:    src.AlertWhenNoData,
+		AlertWhenResolved:  src.AlertWhenResolved,
+		AlertWhenBreaching: src.AlertWhenBreaching,
+	}
+	for _, condition := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, openslov1.AlertPolicyCondition{
+			Kind:         condition.Kind,
+			Metadata:     condition.Metadata,
+			Spec:         condition.Spec,
+			ConditionRef: condition.AlertConditionRef,
+		})
+	}
+	for _, target := range src.NotificationTargets {
+		dst.NotificationTargets = append(dst.NotificationTargets, openslov1.AlertPolicyNotificationTarget{
+			Kind:      target.Kind,
+			Metadata:  target.Metadata,
+			Spec:      target.Spec,
+			TargetRef: target.AlertNotificationTargetRef,
+		})
+	}
+	return dst
+}
+
+func convertAlertPolicySpecFrom(src openslov1.AlertPolicySpec) AlertPolicySpec {
+	dst := AlertPolicySpec{
+		Description:        src.Description,
+		AlertWhenNoData:    src.AlertWhenNoData,
+		AlertWhenResolved:  src.AlertWhenResolved,
+		AlertWhenBreaching: src.AlertWhenBreaching,
+	}
+	for _, condition := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, AlertPolicyCondition{
+			Kind:              condition.Kind,
+			Metadata:          condition.Metadata,
+			Spec:              condition.Spec,
+			AlertConditionRef: condition.ConditionRef,
+		})
+	}
+	for _, target := range src.NotificationTargets {
+		dst.NotificationTargets = append(dst.NotificationTargets, AlertPolicyNotificationTarget{
+			Kind:                       target.Kind,
+			Metadata:                   target.Metadata,
+			Spec:                       target.Spec,
+			AlertNotificationTargetRef: target.TargetRef,
+		})
+	}
+	return dst
+}
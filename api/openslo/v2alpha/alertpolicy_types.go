@@ -0,0 +1,63 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AlertPolicyNotificationTarget struct {
+	// +kubebuilder:validation:Enum=AlertNotificationTarget
+	Kind     string                                 `json:"kind,omitempty"`
+	Metadata metav1.ObjectMeta                      `json:"metadata,omitempty"`
+	Spec     *openslov1.AlertNotificationTargetSpec `json:"spec,omitempty"`
+	// AlertNotificationTargetRef is the name of the AlertNotificationTarget, targetRef in v1
+	AlertNotificationTargetRef *string `json:"alertNotificationTargetRef,omitempty"`
+}
+
+type AlertPolicyCondition struct {
+	// +kubebuilder:validation:Enum=AlertCondition
+	Kind     string                        `json:"kind,omitempty"`
+	Metadata openslov1.ObjectMetaOpenSLO   `json:"metadata,omitempty"`
+	Spec     *openslov1.AlertConditionSpec `json:"spec,omitempty"`
+	// AlertConditionRef is the name of the AlertCondition, conditionRef in v1
+	AlertConditionRef *string `json:"alertConditionRef,omitempty"`
+}
+
+// AlertPolicySpec defines the desired state of AlertPolicy
+type AlertPolicySpec struct {
+	Description        openslov1.Description `json:"description,omitempty"`
+	AlertWhenNoData    bool                  `json:"alertWhenNoData,omitempty"`
+	AlertWhenResolved  bool                  `json:"alertWhenResolved,omitempty"`
+	AlertWhenBreaching bool                  `json:"alertWhenBreaching,omitempty"`
+	// +kubebuilder:validation:MaxItems=1
+	Conditions          []AlertPolicyCondition          `json:"conditions,omitempty"`
+	NotificationTargets []AlertPolicyNotificationTarget `json:"notificationTargets,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the AlertPolicy is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the AlertPolicy resource was created"
+
+// AlertPolicy is the Schema for the alertpolicies API
+type AlertPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertPolicySpec             `json:"spec,omitempty"`
+	Status openslov1.AlertPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AlertPolicyList contains a list of AlertPolicy
+type AlertPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AlertPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AlertPolicy{}, &AlertPolicyList{})
+}
@@ -0,0 +1,241 @@
+package v2alpha
+
+import (
+	"reflect"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	osko "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func v1MetricSource(query string) openslov1.MetricSpec {
+	return openslov1.MetricSpec{MetricSource: openslov1.MetricSource{
+		MetricSourceRef: "mimir",
+		Type:            "Mimir",
+		Spec:            openslov1.MetricSourceSpec{Query: query},
+	}}
+}
+
+func v1SLISpec() openslov1.SLISpec {
+	return openslov1.SLISpec{
+		Description: "Successful requests",
+		RatioMetric: openslov1.RatioMetricSpec{
+			Counter: true,
+			Good:    v1MetricSource(`http_requests_total{code!~"5.."}`),
+			Total:   v1MetricSource(`http_requests_total`),
+		},
+	}
+}
+
+func v1SLO() *openslov1.SLO {
+	return &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "checkout",
+			Namespace:   "shop",
+			Labels:      map[string]string{"label.osko.dev/team": "shop"},
+			Annotations: map[string]string{"osko.dev/datasourceRef": "mimir"},
+		},
+		Spec: openslov1.SLOSpec{
+			Description:     "Checkout availability",
+			Service:         "checkout",
+			TimeWindow:      []openslov1.TimeWindowSpec{{Duration: "28d", IsRolling: true}},
+			BudgetingMethod: "Occurrences",
+			Objectives: []openslov1.ObjectivesSpec{{
+				DisplayName:     "availability",
+				Target:          "0.99",
+				CompositeWeight: resource.MustParse("1"),
+			}},
+			AlertPolicies: []openslov1.SLOAlertPolicy{
+				{AlertPolicyRef: stringPtr("page-on-burn")},
+				{
+					Kind:     "AlertPolicy",
+					Metadata: metav1.ObjectMeta{Name: "ticket-on-burn"},
+					Spec: &openslov1.AlertPolicySpec{
+						AlertWhenBreaching: true,
+						Conditions:         []openslov1.AlertPolicyCondition{{ConditionRef: stringPtr("slow-burn")}},
+					},
+				},
+			},
+		},
+		Status: openslov1.SLOStatus{
+			Conditions:           []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Reconciled"}},
+			CurrentSLO:           "0.995",
+			ErrorBudgetRemaining: "0.5",
+		},
+	}
+}
+
+func TestSLORoundTripFromHub(t *testing.T) {
+	inline := v1SLO()
+	inline.Spec.Indicator = &openslov1.Indicator{Metadata: metav1.ObjectMeta{Name: "checkout-sli"}, Spec: v1SLISpec()}
+
+	referenced := v1SLO()
+	referenced.Spec.IndicatorRef = stringPtr("checkout-sli")
+
+	objective := v1SLO()
+	objective.Spec.Objectives[0].Indicator = &openslov1.Indicator{Metadata: metav1.ObjectMeta{Name: "checkout-sli"}, Spec: v1SLISpec()}
+	objective.Spec.Objectives = append(objective.Spec.Objectives, openslov1.ObjectivesSpec{Target: "0.9", IndicatorRef: stringPtr("latency-sli")})
+
+	tests := map[string]*openslov1.SLO{
+		"inline indicator":     inline,
+		"referenced indicator": referenced,
+		"objective indicators": objective,
+	}
+	for name, hub := range tests {
+		t.Run(name, func(t *testing.T) {
+			spoke := &SLO{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			got := &openslov1.SLO{}
+			if err := spoke.ConvertTo(got); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if !reflect.DeepEqual(hub, got) {
+				t.Errorf("round trip changed the SLO\nwant %+v\ngot  %+v", hub, got)
+			}
+		})
+	}
+}
+
+func TestSLORoundTripFromSpoke(t *testing.T) {
+	sliSpec := SLISpec{
+		Description: "Successful requests",
+		RatioMetric: &RatioMetricSpec{
+			Counter: true,
+			Good:    &MetricSource{DataSourceRef: "mimir", Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_requests_total{code!~"5.."}`}},
+			Total:   &MetricSource{DataSourceRef: "mimir", Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: "http_requests_total"}},
+		},
+	}
+	tests := map[string]SLOSpec{
+		"inline sli": {
+			Service:    "checkout",
+			SLI:        &InlineSLI{Metadata: metav1.ObjectMeta{Name: "checkout-sli"}, Spec: sliSpec},
+			Objectives: []ObjectivesSpec{{Target: "0.99"}},
+		},
+		"referenced sli": {
+			Service:       "checkout",
+			SLIRef:        stringPtr("checkout-sli"),
+			Objectives:    []ObjectivesSpec{{Target: "0.99", SLIRef: stringPtr("latency-sli")}},
+			AlertPolicies: []SLOAlertPolicy{{AlertPolicyRef: stringPtr("page-on-burn")}},
+		},
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			spoke := &SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}, Spec: spec}
+			hub := &openslov1.SLO{}
+			if err := spoke.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			got := &SLO{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if !reflect.DeepEqual(spoke, got) {
+				t.Errorf("round trip changed the SLO\nwant %+v\ngot  %+v", spoke, got)
+			}
+		})
+	}
+}
+
+func TestSLOConvertToRenamesReferences(t *testing.T) {
+	spoke := &SLO{Spec: SLOSpec{
+		SLI:        &InlineSLI{Spec: SLISpec{ThresholdMetric: &MetricSource{DataSourceRef: "mimir", Spec: openslov1.MetricSourceSpec{Query: "up"}}}},
+		Objectives: []ObjectivesSpec{{SLIRef: stringPtr("latency-sli")}},
+	}}
+	hub := &openslov1.SLO{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if got := hub.Spec.Indicator.Spec.ThresholdMetric.MetricSource.MetricSourceRef; got != "mimir" {
+		t.Errorf("metricSourceRef = %q, want mimir", got)
+	}
+	if got := hub.Spec.Objectives[0].IndicatorRef; got == nil || *got != "latency-sli" {
+		t.Errorf("objective indicatorRef = %v, want latency-sli", got)
+	}
+	if hub.Spec.Indicator.Spec.RatioMetric != (openslov1.RatioMetricSpec{}) {
+		t.Errorf("ratioMetric = %+v, want it unset", hub.Spec.Indicator.Spec.RatioMetric)
+	}
+}
+
+func TestSLIRoundTripFromHub(t *testing.T) {
+	hub := &openslov1.SLI{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-sli", Namespace: "shop"},
+		Spec:       v1SLISpec(),
+		Status:     openslov1.SLIStatus{SLOs: []string{"checkout"}},
+	}
+	threshold := hub.DeepCopy()
+	threshold.Spec = openslov1.SLISpec{ThresholdMetric: openslov1.ThresholdMetricSpec{MetricSource: v1MetricSource("up").MetricSource}}
+
+	for name, hub := range map[string]*openslov1.SLI{"ratio": hub, "threshold": threshold} {
+		t.Run(name, func(t *testing.T) {
+			spoke := &SLI{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			got := &openslov1.SLI{}
+			if err := spoke.ConvertTo(got); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if !reflect.DeepEqual(hub, got) {
+				t.Errorf("round trip changed the SLI\nwant %+v\ngot  %+v", hub, got)
+			}
+		})
+	}
+}
+
+func TestAlertPolicyRoundTripFromHub(t *testing.T) {
+	hub := &openslov1.AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "page-on-burn", Namespace: "shop"},
+		Spec: openslov1.AlertPolicySpec{
+			AlertWhenBreaching: true,
+			Conditions: []openslov1.AlertPolicyCondition{{
+				Kind:     "AlertCondition",
+				Metadata: openslov1.ObjectMetaOpenSLO{ObjectMeta: metav1.ObjectMeta{Name: "fast-burn"}},
+				Spec:     &openslov1.AlertConditionSpec{Severity: "page"},
+			}},
+			NotificationTargets: []openslov1.AlertPolicyNotificationTarget{{TargetRef: stringPtr("oncall")}},
+		},
+	}
+	spoke := &AlertPolicy{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if got := spoke.Spec.NotificationTargets[0].AlertNotificationTargetRef; got == nil || *got != "oncall" {
+		t.Errorf("alertNotificationTargetRef = %v, want oncall", got)
+	}
+	got := &openslov1.AlertPolicy{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !reflect.DeepEqual(hub, got) {
+		t.Errorf("round trip changed the AlertPolicy\nwant %+v\ngot  %+v", hub, got)
+	}
+}
+
+func TestDatasourceRoundTripFromHub(t *testing.T) {
+	hub := &openslov1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "shop"},
+		Spec: openslov1.DatasourceSpec{
+			Type:              "mimir",
+			ConnectionDetails: osko.ConnectionDetails{Address: "http://mimir/", TargetTenant: "shop"},
+		},
+	}
+	spoke := &Datasource{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	got := &openslov1.Datasource{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !reflect.DeepEqual(hub, got) {
+		t.Errorf("round trip changed the Datasource\nwant %+v\ngot  %+v", hub, got)
+	}
+}
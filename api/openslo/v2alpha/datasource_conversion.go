@@ -0,0 +1,24 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Datasource to the hub version (v1)
+func (src *Datasource) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*openslov1.Datasource)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = src.Spec
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version
+func (dst *Datasource) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*openslov1.Datasource)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = src.Spec
+	dst.Status = src.Status
+	return nil
+}
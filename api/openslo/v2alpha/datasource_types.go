@@ -0,0 +1,37 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:resource:scope=Namespaced
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the Datasource is ready"
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=.spec.type,description="The type of the Datasource"
+//+kubebuilder:printcolumn:name="Queue",type=integer,JSONPath=.status.rulerQueue.depth,description="Rule groups waiting to be written to the ruler"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the Datasource resource was created"
+
+// Datasource is the Schema for the datasources API. Its spec is the same as in v1, SLIs reference it as dataSourceRef.
+type Datasource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   openslov1.DatasourceSpec   `json:"spec,omitempty"`
+	Status openslov1.DatasourceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatasourceList contains a list of Datasource
+type DatasourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Datasource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Datasource{}, &DatasourceList{})
+}
@@ -0,0 +1,84 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this SLI to the hub version (v1)
+func (src *SLI) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*openslov1.SLI)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertSLISpecTo(src.Spec)
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version
+func (dst *SLI) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*openslov1.SLI)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertSLISpecFrom(src.Spec)
+	dst.Status = src.Status
+	return nil
+}
+
+func convertSLISpecTo(src SLISpec) openslov1.SLISpec {
+	dst := openslov1.SLISpec{Description: src.Description}
+	if src.ThresholdMetric != nil {
+		dst.ThresholdMetric.MetricSource = convertMetricSourceTo(src.ThresholdMetric)
+	}
+	if src.RatioMetric != nil {
+		dst.RatioMetric = openslov1.RatioMetricSpec{
+			Counter: src.RatioMetric.Counter,
+			Good:    openslov1.MetricSpec{MetricSource: convertMetricSourceTo(src.RatioMetric.Good)},
+			Bad:     openslov1.MetricSpec{MetricSource: convertMetricSourceTo(src.RatioMetric.Bad)},
+			Total:   openslov1.MetricSpec{MetricSource: convertMetricSourceTo(src.RatioMetric.Total)},
+			Raw:     openslov1.MetricSpec{MetricSource: convertMetricSourceTo(src.RatioMetric.Raw)},
+			RawType: src.RatioMetric.RawType,
+		}
+	}
+	return dst
+}
+
+func convertSLISpecFrom(src openslov1.SLISpec) SLISpec {
+	dst := SLISpec{Description: src.Description}
+	if src.ThresholdMetric != (openslov1.ThresholdMetricSpec{}) {
+		dst.ThresholdMetric = convertMetricSourceFrom(src.ThresholdMetric.MetricSource)
+	}
+	if src.RatioMetric != (openslov1.RatioMetricSpec{}) {
+		dst.RatioMetric = &RatioMetricSpec{
+			Counter: src.RatioMetric.Counter,
+			Good:    convertMetricSourceFrom(src.RatioMetric.Good.MetricSource),
+			Bad:     convertMetricSourceFrom(src.RatioMetric.Bad.MetricSource),
+			Total:   convertMetricSourceFrom(src.RatioMetric.Total.MetricSource),
+			Raw:     convertMetricSourceFrom(src.RatioMetric.Raw.MetricSource),
+			RawType: src.RatioMetric.RawType,
+		}
+	}
+	return dst
+}
+
+// convertMetricSourceTo returns the v1 metric source, empty for a metric that is not set
+func convertMetricSourceTo(src *MetricSource) openslov1.MetricSource {
+	if src == nil {
+		return openslov1.MetricSource{}
+	}
+	return openslov1.MetricSource{
+		MetricSourceRef: src.DataSourceRef,
+		Type:            src.Type,
+		Spec:            src.Spec,
+	}
+}
+
+// convertMetricSourceFrom returns the v2alpha metric source, nil for an empty v1 metric source
+func convertMetricSourceFrom(src openslov1.MetricSource) *MetricSource {
+	if src == (openslov1.MetricSource{}) {
+		return nil
+	}
+	return &MetricSource{
+		DataSourceRef: src.MetricSourceRef,
+		Type:          src.Type,
+		Spec:          src.Spec,
+	}
+}
@@ -0,0 +1,59 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MetricSource is a query against a Datasource. It replaces the metricSource wrapper of v1.
+type MetricSource struct {
+	// DataSourceRef is the name of the Datasource the query runs against, metricSourceRef in v1
+	DataSourceRef string                     `json:"dataSourceRef,omitempty"`
+	Type          string                     `json:"type,omitempty"`
+	Spec          openslov1.MetricSourceSpec `json:"spec,omitempty"`
+}
+
+type RatioMetricSpec struct {
+	Counter bool          `json:"counter,omitempty"`
+	Good    *MetricSource `json:"good,omitempty"`
+	Bad     *MetricSource `json:"bad,omitempty"`
+	Total   *MetricSource `json:"total,omitempty"`
+	Raw     *MetricSource `json:"raw,omitempty"`
+	// +kubebuilder:validation:Enum=success;failure
+	RawType string `json:"rawType,omitempty"`
+}
+
+// SLISpec defines the desired state of SLI
+type SLISpec struct {
+	Description     openslov1.Description `json:"description,omitempty"`
+	ThresholdMetric *MetricSource         `json:"thresholdMetric,omitempty"`
+	RatioMetric     *RatioMetricSpec      `json:"ratioMetric,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the SLI is ready"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLI resource was created"
+
+// SLI is the Schema for the slis API
+type SLI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SLISpec             `json:"spec,omitempty"`
+	Status openslov1.SLIStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SLIList contains a list of SLI
+type SLIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLI{}, &SLIList{})
+}
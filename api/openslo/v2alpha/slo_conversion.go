@@ -0,0 +1,110 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this SLO to the hub version (v1)
+func (src *SLO) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*openslov1.SLO)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = openslov1.SLOSpec{
+		Description:     src.Spec.Description,
+		Service:         src.Spec.Service,
+		Indicator:       convertInlineSLITo(src.Spec.SLI),
+		IndicatorRef:    src.Spec.SLIRef,
+		TimeWindow:      src.Spec.TimeWindow,
+		BudgetingMethod: src.Spec.BudgetingMethod,
+	}
+	for _, objective := range src.Spec.Objectives {
+		dst.Spec.Objectives = append(dst.Spec.Objectives, openslov1.ObjectivesSpec{
+			DisplayName:     objective.DisplayName,
+			Op:              objective.Op,
+			Value:           objective.Value,
+			Target:          objective.Target,
+			TargetPercent:   objective.TargetPercent,
+			TimeSliceTarget: objective.TimeSliceTarget,
+			TimeSliceWindow: objective.TimeSliceWindow,
+			Indicator:       convertInlineSLITo(objective.SLI),
+			IndicatorRef:    objective.SLIRef,
+			CompositeWeight: objective.CompositeWeight,
+		})
+	}
+	for _, policy := range src.Spec.AlertPolicies {
+		converted := openslov1.SLOAlertPolicy{
+			Kind:           policy.Kind,
+			Metadata:       policy.Metadata,
+			AlertPolicyRef: policy.AlertPolicyRef,
+		}
+		if policy.Spec != nil {
+			spec := convertAlertPolicySpecTo(*policy.Spec)
+			converted.Spec = &spec
+		}
+		dst.Spec.AlertPolicies = append(dst.Spec.AlertPolicies, converted)
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version
+func (dst *SLO) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*openslov1.SLO)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = SLOSpec{
+		Description:     src.Spec.Description,
+		Service:         src.Spec.Service,
+		SLI:             convertInlineSLIFrom(src.Spec.Indicator),
+		SLIRef:          src.Spec.IndicatorRef,
+		TimeWindow:      src.Spec.TimeWindow,
+		BudgetingMethod: src.Spec.BudgetingMethod,
+	}
+	for _, objective := range src.Spec.Objectives {
+		dst.Spec.Objectives = append(dst.Spec.Objectives, ObjectivesSpec{
+			DisplayName:     objective.DisplayName,
+			Op:              objective.Op,
+			Value:           objective.Value,
+			Target:          objective.Target,
+			TargetPercent:   objective.TargetPercent,
+			TimeSliceTarget: objective.TimeSliceTarget,
+			TimeSliceWindow: objective.TimeSliceWindow,
+			SLI:             convertInlineSLIFrom(objective.Indicator),
+			SLIRef:          objective.IndicatorRef,
+			CompositeWeight: objective.CompositeWeight,
+		})
+	}
+	for _, policy := range src.Spec.AlertPolicies {
+		converted := SLOAlertPolicy{
+			Kind:           policy.Kind,
+			Metadata:       policy.Metadata,
+			AlertPolicyRef: policy.AlertPolicyRef,
+		}
+		if policy.Spec != nil {
+			spec := convertAlertPolicySpecFrom(*policy.Spec)
+			converted.Spec = &spec
+		}
+		dst.Spec.AlertPolicies = append(dst.Spec.AlertPolicies, converted)
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+func convertInlineSLITo(src *InlineSLI) *openslov1.Indicator {
+	if src == nil {
+		return nil
+	}
+	return &openslov1.Indicator{
+		Metadata: src.Metadata,
+		Spec:     convertSLISpecTo(src.Spec),
+	}
+}
+
+func convertInlineSLIFrom(src *openslov1.Indicator) *InlineSLI {
+	if src == nil {
+		return nil
+	}
+	return &InlineSLI{
+		Metadata: src.Metadata,
+		Spec:     convertSLISpecFrom(src.Spec),
+	}
+}
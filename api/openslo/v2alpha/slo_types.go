@@ -0,0 +1,84 @@
+package v2alpha
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SLOAlertPolicy struct {
+	// +kubebuilder:validation:Enum=AlertPolicy
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:crd:generateEmbeddedObjectMeta=true
+	Metadata       metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec           *AlertPolicySpec  `json:"spec,omitempty"`
+	AlertPolicyRef *string           `json:"alertPolicyRef,omitempty"`
+}
+
+// InlineSLI is an SLI defined in the SLO, indicator in v1
+type InlineSLI struct {
+	Metadata metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec     SLISpec           `json:"spec,omitempty"`
+}
+
+type ObjectivesSpec struct {
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// +kubebuilder:validation:Enum=lte;gte;lt;gt
+	Op              string             `json:"op,omitempty"`
+	Value           string             `json:"value,omitempty"`
+	Target          string             `json:"target,omitempty"`
+	TargetPercent   string             `json:"targetPercent,omitempty"`
+	TimeSliceTarget string             `json:"timeSliceTarget,omitempty"`
+	TimeSliceWindow openslov1.Duration `json:"timeSliceWindow,omitempty"`
+	SLI             *InlineSLI         `json:"sli,omitempty"`
+	SLIRef          *string            `json:"sliRef,omitempty"`
+	CompositeWeight resource.Quantity  `json:"compositeWeight,omitempty"`
+}
+
+// SLOSpec defines the desired state of SLO
+type SLOSpec struct {
+	Description openslov1.Description `json:"description,omitempty"`
+	Service     string                `json:"service,omitempty"`
+	// SLI is the inline SLI of the SLO, indicator in v1
+	SLI *InlineSLI `json:"sli,omitempty"`
+	// SLIRef is the name of the SLI of the SLO, indicatorRef in v1
+	SLIRef *string `json:"sliRef,omitempty"`
+	// +kubebuilder:validation:MaxItems=1
+	TimeWindow []openslov1.TimeWindowSpec `json:"timeWindow,omitempty"`
+	// +kubebuilder:validation:Enum=Occurrences;Timeslices;RatioTimeslices
+	BudgetingMethod string           `json:"budgetingMethod,omitempty"`
+	Objectives      []ObjectivesSpec `json:"objectives,omitempty"`
+	AlertPolicies   []SLOAlertPolicy `json:"alertPolicies,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=.status.ready,description="The reason for the current status of the SLO resource"
+//+kubebuilder:printcolumn:name="Window",type=string,JSONPath=.spec.timeWindow[0].duration,description="The time window for the SLO resource"
+//+kubebuilder:printcolumn:name="SLI",type=string,JSONPath=.status.currentSLO,description="The SLI measured over the time window"
+//+kubebuilder:printcolumn:name="Budget",type=string,JSONPath=.status.errorBudgetRemaining,description="The share of the error budget left in the time window"
+//+kubebuilder:printcolumn:name="Burn",type=string,JSONPath=.status.fastestBurnRate,description="The fastest error budget burn rate across the recorded windows"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLO resource was created"
+
+// SLO is the Schema for the slos API
+type SLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SLOSpec             `json:"spec,omitempty"`
+	Status            openslov1.SLOStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SLOList contains a list of SLO
+type SLOList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLO `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLO{}, &SLOList{})
+}
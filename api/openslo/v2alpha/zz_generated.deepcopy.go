@@ -0,0 +1,516 @@
+//go:build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2alpha
+
+import (
+	"github.com/oskoperator/osko/api/openslo/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicy) DeepCopyInto(out *AlertPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicy.
+func (in *AlertPolicy) DeepCopy() *AlertPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicyCondition) DeepCopyInto(out *AlertPolicyCondition) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(v1.AlertConditionSpec)
+		**out = **in
+	}
+	if in.AlertConditionRef != nil {
+		in, out := &in.AlertConditionRef, &out.AlertConditionRef
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyCondition.
+func (in *AlertPolicyCondition) DeepCopy() *AlertPolicyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicyList) DeepCopyInto(out *AlertPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AlertPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyList.
+func (in *AlertPolicyList) DeepCopy() *AlertPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicyNotificationTarget) DeepCopyInto(out *AlertPolicyNotificationTarget) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(v1.AlertNotificationTargetSpec)
+		**out = **in
+	}
+	if in.AlertNotificationTargetRef != nil {
+		in, out := &in.AlertNotificationTargetRef, &out.AlertNotificationTargetRef
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyNotificationTarget.
+func (in *AlertPolicyNotificationTarget) DeepCopy() *AlertPolicyNotificationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicyNotificationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicySpec) DeepCopyInto(out *AlertPolicySpec) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]AlertPolicyCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NotificationTargets != nil {
+		in, out := &in.NotificationTargets, &out.NotificationTargets
+		*out = make([]AlertPolicyNotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicySpec.
+func (in *AlertPolicySpec) DeepCopy() *AlertPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Datasource) DeepCopyInto(out *Datasource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Datasource.
+func (in *Datasource) DeepCopy() *Datasource {
+	if in == nil {
+		return nil
+	}
+	out := new(Datasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Datasource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasourceList) DeepCopyInto(out *DatasourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Datasource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasourceList.
+func (in *DatasourceList) DeepCopy() *DatasourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatasourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineSLI) DeepCopyInto(out *InlineSLI) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineSLI.
+func (in *InlineSLI) DeepCopy() *InlineSLI {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineSLI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricSource) DeepCopyInto(out *MetricSource) {
+	*out = *in
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSource.
+func (in *MetricSource) DeepCopy() *MetricSource {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectivesSpec) DeepCopyInto(out *ObjectivesSpec) {
+	*out = *in
+	if in.SLI != nil {
+		in, out := &in.SLI, &out.SLI
+		*out = new(InlineSLI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLIRef != nil {
+		in, out := &in.SLIRef, &out.SLIRef
+		*out = new(string)
+		**out = **in
+	}
+	out.CompositeWeight = in.CompositeWeight.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectivesSpec.
+func (in *ObjectivesSpec) DeepCopy() *ObjectivesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectivesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RatioMetricSpec) DeepCopyInto(out *RatioMetricSpec) {
+	*out = *in
+	if in.Good != nil {
+		in, out := &in.Good, &out.Good
+		*out = new(MetricSource)
+		**out = **in
+	}
+	if in.Bad != nil {
+		in, out := &in.Bad, &out.Bad
+		*out = new(MetricSource)
+		**out = **in
+	}
+	if in.Total != nil {
+		in, out := &in.Total, &out.Total
+		*out = new(MetricSource)
+		**out = **in
+	}
+	if in.Raw != nil {
+		in, out := &in.Raw, &out.Raw
+		*out = new(MetricSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RatioMetricSpec.
+func (in *RatioMetricSpec) DeepCopy() *RatioMetricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RatioMetricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLI) DeepCopyInto(out *SLI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLI.
+func (in *SLI) DeepCopy() *SLI {
+	if in == nil {
+		return nil
+	}
+	out := new(SLI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLI) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLIList) DeepCopyInto(out *SLIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SLI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLIList.
+func (in *SLIList) DeepCopy() *SLIList {
+	if in == nil {
+		return nil
+	}
+	out := new(SLIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLIList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLISpec) DeepCopyInto(out *SLISpec) {
+	*out = *in
+	if in.ThresholdMetric != nil {
+		in, out := &in.ThresholdMetric, &out.ThresholdMetric
+		*out = new(MetricSource)
+		**out = **in
+	}
+	if in.RatioMetric != nil {
+		in, out := &in.RatioMetric, &out.RatioMetric
+		*out = new(RatioMetricSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLISpec.
+func (in *SLISpec) DeepCopy() *SLISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLO) DeepCopyInto(out *SLO) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLO.
+func (in *SLO) DeepCopy() *SLO {
+	if in == nil {
+		return nil
+	}
+	out := new(SLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLO) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOAlertPolicy) DeepCopyInto(out *SLOAlertPolicy) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(AlertPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertPolicyRef != nil {
+		in, out := &in.AlertPolicyRef, &out.AlertPolicyRef
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOAlertPolicy.
+func (in *SLOAlertPolicy) DeepCopy() *SLOAlertPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOAlertPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOList) DeepCopyInto(out *SLOList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SLO, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOList.
+func (in *SLOList) DeepCopy() *SLOList {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLOList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOSpec) DeepCopyInto(out *SLOSpec) {
+	*out = *in
+	if in.SLI != nil {
+		in, out := &in.SLI, &out.SLI
+		*out = new(InlineSLI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLIRef != nil {
+		in, out := &in.SLIRef, &out.SLIRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.TimeWindow != nil {
+		in, out := &in.TimeWindow, &out.TimeWindow
+		*out = make([]v1.TimeWindowSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Objectives != nil {
+		in, out := &in.Objectives, &out.Objectives
+		*out = make([]ObjectivesSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertPolicies != nil {
+		in, out := &in.AlertPolicies, &out.AlertPolicies
+		*out = make([]SLOAlertPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOSpec.
+func (in *SLOSpec) DeepCopy() *SLOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
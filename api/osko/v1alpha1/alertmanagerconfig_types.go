@@ -19,6 +19,7 @@ type AlertManagerConfigStatus struct {
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	LastEvaluationTime metav1.Time        `json:"lastEvaluationTime,omitempty"`
 	Ready              string             `json:"ready,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,6 +21,10 @@ type MimirRuleStatus struct {
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	LastEvaluationTime metav1.Time        `json:"lastEvaluationTime,omitempty"`
 	Ready              string             `json:"ready,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// SyncedGroups are the names of the rule groups written to the ruler. Groups that are no longer in the
+	// spec, for example after the windows of the SLO changed, are deleted from the ruler.
+	SyncedGroups []string `json:"syncedGroups,omitempty"`
 }
 
 type RuleGroup struct {
@@ -0,0 +1,136 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigSpec defines the settings of the operator that can change while it is running.
+// Every field is optional, unset fields keep the value from the environment of the operator.
+type OperatorConfigSpec struct {
+	// MimirRuleRequeuePeriod is how often MimirRules are synced to the ruler
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	MimirRuleRequeuePeriod *metav1.Duration `json:"mimirRuleRequeuePeriod,omitempty"`
+	// DefaultBaseWindow is the base window of SLOs without the osko.dev/baseWindow annotation
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	DefaultBaseWindow *metav1.Duration `json:"defaultBaseWindow,omitempty"`
+	// AlertingTool is the alerting tool of SLOs without the osko.dev/alertingTool annotation
+	// +kubebuilder:validation:MinLength=1
+	AlertingTool string `json:"alertingTool,omitempty"`
+	// SeverityMaps are the severity maps of alerting tools by name, selected with alertingTool or the
+	// osko.dev/alertingTool annotation of an SLO. They add to the built-in opsgenie, pagerduty and custom maps
+	// and replace a built-in map of the same name.
+	SeverityMaps map[string]OperatorConfigSeverityMap `json:"severityMaps,omitempty"`
+	// AlertingBurnRates are the burn rate thresholds of the generated alerts
+	AlertingBurnRates *OperatorConfigBurnRates `json:"alertingBurnRates,omitempty"`
+	// AlertKeepFiringFor is the keep_firing_for of the generated alerts
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	AlertKeepFiringFor *metav1.Duration `json:"alertKeepFiringFor,omitempty"`
+	// SLOStatusRefreshPeriod is how often the live status of SLOs is queried, 0s disables it
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	SLOStatusRefreshPeriod *metav1.Duration `json:"sloStatusRefreshPeriod,omitempty"`
+	// SLIValidationPeriod is how often the queries of SLIs are validated against their datasource, 0s disables it
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	SLIValidationPeriod *metav1.Duration `json:"sliValidationPeriod,omitempty"`
+	// BudgetExhaustedThreshold is the share of the error budget left at or below which an SLO counts as exhausted
+	// +kubebuilder:validation:Pattern=`^0(\.[0-9]+)?$`
+	BudgetExhaustedThreshold string `json:"budgetExhaustedThreshold,omitempty"`
+	// RuleGroups are the evaluation settings of generated rule groups per window class
+	RuleGroups *OperatorConfigRuleGroups `json:"ruleGroups,omitempty"`
+}
+
+// OperatorConfigBurnRates are the burn rate thresholds of the page and ticket alerts, as decimal numbers
+type OperatorConfigBurnRates struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	PageShortWindow string `json:"pageShortWindow,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	PageLongWindow string `json:"pageLongWindow,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	TicketShortWindow string `json:"ticketShortWindow,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	TicketLongWindow string `json:"ticketLongWindow,omitempty"`
+}
+
+// OperatorConfigSeverityMap is how an alerting tool labels the alerts of every SRE severity
+type OperatorConfigSeverityMap struct {
+	// PageCritical is the page on the fastest burn rate
+	PageCritical OperatorConfigAlertSeverity `json:"pageCritical"`
+	// PageHigh is the page on the slower burn rate
+	PageHigh OperatorConfigAlertSeverity `json:"pageHigh"`
+	// TicketHigh is the ticket on the faster burn rate
+	TicketHigh OperatorConfigAlertSeverity `json:"ticketHigh"`
+	// TicketMedium is the ticket on the slowest burn rate
+	TicketMedium OperatorConfigAlertSeverity `json:"ticketMedium"`
+}
+
+// OperatorConfigAlertSeverity is the severity label of the alerts of one SRE severity and the extra labels and
+// annotations they get
+type OperatorConfigAlertSeverity struct {
+	// +kubebuilder:validation:MinLength=1
+	Severity    string            `json:"severity"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// OperatorConfigRuleGroups are the evaluation settings of generated rule groups per window class
+type OperatorConfigRuleGroups struct {
+	// ShortWindow applies to rule groups of windows up to 1h and to alerting rules
+	ShortWindow *OperatorConfigRuleGroup `json:"shortWindow,omitempty"`
+	// MediumWindow applies to rule groups of windows up to 24h
+	MediumWindow *OperatorConfigRuleGroup `json:"mediumWindow,omitempty"`
+	// LongWindow applies to rule groups of windows longer than 24h
+	LongWindow *OperatorConfigRuleGroup `json:"longWindow,omitempty"`
+}
+
+// OperatorConfigRuleGroup are the evaluation settings of the rule groups of a window class
+type OperatorConfigRuleGroup struct {
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	EvaluationDelay *metav1.Duration `json:"evaluationDelay,omitempty"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// ActiveGeneration is the generation of the spec the operator is running with
+	ActiveGeneration int64 `json:"activeGeneration,omitempty"`
+	// LastAppliedTime is the time the active spec was applied
+	LastAppliedTime metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Active",type=integer,JSONPath=.status.activeGeneration,description="The generation the operator is running with"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the latest generation is applied"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the OperatorConfig resource was created"
+
+// OperatorConfig is the Schema for the operatorconfigs API
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SLODefaultsName is the name of the SLODefaults the SLOs of a namespace inherit from
+const SLODefaultsName = "default"
+
+// SLODefaultsSpec defines the settings the SLOs of a namespace inherit. Settings an SLO sets itself take precedence.
+type SLODefaultsSpec struct {
+	// DatasourceRef is the Datasource of SLOs without the osko.dev/datasourceRef annotation
+	// +kubebuilder:validation:MinLength=1
+	DatasourceRef string `json:"datasourceRef,omitempty"`
+	// MagicAlerting enables the generated alerts of SLOs without the osko.dev/magicAlerting annotation
+	MagicAlerting *bool `json:"magicAlerting,omitempty"`
+	// AlertingTool is the alerting tool of SLOs without the osko.dev/alertingTool annotation
+	// +kubebuilder:validation:MinLength=1
+	AlertingTool string `json:"alertingTool,omitempty"`
+	// BaseWindow is the base window of SLOs without the osko.dev/baseWindow annotation
+	// +kubebuilder:validation:Pattern=`^[1-9]\d*[s m h d]$`
+	BaseWindow string `json:"baseWindow,omitempty"`
+	// Labels are added to the generated rules of every SLO, like label.osko.dev/<key> labels on the SLO.
+	// A label.osko.dev/<key> label on the SLO takes precedence over the same key.
+	Labels map[string]string `json:"labels,omitempty"`
+	// BudgetingMethod is the budgeting method of SLOs without one
+	// +kubebuilder:validation:Enum=Occurrences
+	BudgetingMethod string `json:"budgetingMethod,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="SLODefaults must be named default, a namespace has a single one"
+// +kubebuilder:printcolumn:name="Datasource",type=string,JSONPath=.spec.datasourceRef,description="The default Datasource of the SLOs"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLODefaults resource was created"
+
+// SLODefaults is the Schema for the slodefaults API
+type SLODefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SLODefaultsSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SLODefaultsList contains a list of SLODefaults
+type SLODefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLODefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLODefaults{}, &SLODefaultsList{})
+}
@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SLOReportSpec defines the desired state of SLOReport
+type SLOReportSpec struct {
+	// Schedule is the cron expression the report runs on, for example "0 6 1 * *" for every first of the month
+	Schedule string `json:"schedule"`
+	// Period is how far back the report looks on every run
+	// +kubebuilder:validation:Pattern=`^[1-9]\d*[smhdw]$`
+	// +kubebuilder:default="30d"
+	Period string `json:"period,omitempty"`
+	// Namespaces the SLOs are selected from, the namespace of the report when empty. Other namespaces need
+	// SLO_REPORT_CROSS_NAMESPACE on the operator.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selector selects the SLOs by label, all SLOs of the namespaces when empty
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Suspend stops scheduling new runs of the report
+	Suspend bool `json:"suspend,omitempty"`
+	// Output renders the results of every run into a ConfigMap
+	Output *SLOReportOutput `json:"output,omitempty"`
+}
+
+// SLOReportOutput configures the ConfigMap the results of a report are rendered into
+type SLOReportOutput struct {
+	// ConfigMapName is the name of the ConfigMap in the namespace of the report, the name of the report when empty
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Formats the results are rendered in, stored under the report.md and report.csv keys
+	// +kubebuilder:default={markdown}
+	Formats []SLOReportFormat `json:"formats,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=markdown;csv
+type SLOReportFormat string
+
+const (
+	SLOReportFormatMarkdown SLOReportFormat = "markdown"
+	SLOReportFormatCSV      SLOReportFormat = "csv"
+)
+
+// SLOReportResult is the outcome of one SLO over the period of a report run
+type SLOReportResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Service   string `json:"service,omitempty"`
+	// Target is the objective of the SLO
+	Target string `json:"target,omitempty"`
+	// Attainment is the SLI over the report period
+	Attainment string `json:"attainment,omitempty"`
+	// ErrorBudgetConsumed is the share of the error budget of the period that was burned, above 1 when it was overspent
+	ErrorBudgetConsumed string `json:"errorBudgetConsumed,omitempty"`
+	// Met is true when the attainment reached the target
+	Met bool `json:"met"`
+	// Error is the reason the SLO could not be reported on
+	Error string `json:"error,omitempty"`
+}
+
+// SLOReportStatus defines the observed state of SLOReport
+type SLOReportStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// LastRunTime is the time of the last run of the report, the end of its period
+	LastRunTime metav1.Time `json:"lastRunTime,omitempty"`
+	// NextRunTime is the time of the next scheduled run
+	NextRunTime metav1.Time `json:"nextRunTime,omitempty"`
+	// Results of the last run
+	Results []SLOReportResult `json:"results,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=.spec.schedule,description="The cron schedule of the report"
+// +kubebuilder:printcolumn:name="Period",type=string,JSONPath=.spec.period,description="How far back the report looks"
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=.status.lastRunTime,description="The time of the last run"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the last run succeeded"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp,description="The time when the SLOReport resource was created"
+
+// SLOReport is the Schema for the sloreports API
+type SLOReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SLOReportSpec   `json:"spec,omitempty"`
+	Status SLOReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SLOReportList contains a list of SLOReport
+type SLOReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLOReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLOReport{}, &SLOReportList{})
+}
@@ -256,6 +256,11 @@ func (in *MimirRuleStatus) DeepCopyInto(out *MimirRuleStatus) {
 		}
 	}
 	in.LastEvaluationTime.DeepCopyInto(&out.LastEvaluationTime)
+	if in.SyncedGroups != nil {
+		in, out := &in.SyncedGroups, &out.SyncedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirRuleStatus.
@@ -288,6 +293,263 @@ func (in *Multitenancy) DeepCopy() *Multitenancy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigAlertSeverity) DeepCopyInto(out *OperatorConfigAlertSeverity) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigAlertSeverity.
+func (in *OperatorConfigAlertSeverity) DeepCopy() *OperatorConfigAlertSeverity {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigAlertSeverity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigBurnRates) DeepCopyInto(out *OperatorConfigBurnRates) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigBurnRates.
+func (in *OperatorConfigBurnRates) DeepCopy() *OperatorConfigBurnRates {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigBurnRates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigRuleGroup) DeepCopyInto(out *OperatorConfigRuleGroup) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EvaluationDelay != nil {
+		in, out := &in.EvaluationDelay, &out.EvaluationDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigRuleGroup.
+func (in *OperatorConfigRuleGroup) DeepCopy() *OperatorConfigRuleGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigRuleGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigRuleGroups) DeepCopyInto(out *OperatorConfigRuleGroups) {
+	*out = *in
+	if in.ShortWindow != nil {
+		in, out := &in.ShortWindow, &out.ShortWindow
+		*out = new(OperatorConfigRuleGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MediumWindow != nil {
+		in, out := &in.MediumWindow, &out.MediumWindow
+		*out = new(OperatorConfigRuleGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LongWindow != nil {
+		in, out := &in.LongWindow, &out.LongWindow
+		*out = new(OperatorConfigRuleGroup)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigRuleGroups.
+func (in *OperatorConfigRuleGroups) DeepCopy() *OperatorConfigRuleGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigRuleGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSeverityMap) DeepCopyInto(out *OperatorConfigSeverityMap) {
+	*out = *in
+	in.PageCritical.DeepCopyInto(&out.PageCritical)
+	in.PageHigh.DeepCopyInto(&out.PageHigh)
+	in.TicketHigh.DeepCopyInto(&out.TicketHigh)
+	in.TicketMedium.DeepCopyInto(&out.TicketMedium)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSeverityMap.
+func (in *OperatorConfigSeverityMap) DeepCopy() *OperatorConfigSeverityMap {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSeverityMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.MimirRuleRequeuePeriod != nil {
+		in, out := &in.MimirRuleRequeuePeriod, &out.MimirRuleRequeuePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DefaultBaseWindow != nil {
+		in, out := &in.DefaultBaseWindow, &out.DefaultBaseWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SeverityMaps != nil {
+		in, out := &in.SeverityMaps, &out.SeverityMaps
+		*out = make(map[string]OperatorConfigSeverityMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.AlertingBurnRates != nil {
+		in, out := &in.AlertingBurnRates, &out.AlertingBurnRates
+		*out = new(OperatorConfigBurnRates)
+		**out = **in
+	}
+	if in.AlertKeepFiringFor != nil {
+		in, out := &in.AlertKeepFiringFor, &out.AlertKeepFiringFor
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SLOStatusRefreshPeriod != nil {
+		in, out := &in.SLOStatusRefreshPeriod, &out.SLOStatusRefreshPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SLIValidationPeriod != nil {
+		in, out := &in.SLIValidationPeriod, &out.SLIValidationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RuleGroups != nil {
+		in, out := &in.RuleGroups, &out.RuleGroups
+		*out = new(OperatorConfigRuleGroups)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastAppliedTime.DeepCopyInto(&out.LastAppliedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -369,6 +631,244 @@ func (in *Ruler) DeepCopy() *Ruler {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLODefaults) DeepCopyInto(out *SLODefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLODefaults.
+func (in *SLODefaults) DeepCopy() *SLODefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(SLODefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLODefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLODefaultsList) DeepCopyInto(out *SLODefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SLODefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLODefaultsList.
+func (in *SLODefaultsList) DeepCopy() *SLODefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(SLODefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLODefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLODefaultsSpec) DeepCopyInto(out *SLODefaultsSpec) {
+	*out = *in
+	if in.MagicAlerting != nil {
+		in, out := &in.MagicAlerting, &out.MagicAlerting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLODefaultsSpec.
+func (in *SLODefaultsSpec) DeepCopy() *SLODefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLODefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReport) DeepCopyInto(out *SLOReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReport.
+func (in *SLOReport) DeepCopy() *SLOReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLOReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReportList) DeepCopyInto(out *SLOReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SLOReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReportList.
+func (in *SLOReportList) DeepCopy() *SLOReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLOReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReportOutput) DeepCopyInto(out *SLOReportOutput) {
+	*out = *in
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]SLOReportFormat, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReportOutput.
+func (in *SLOReportOutput) DeepCopy() *SLOReportOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReportOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReportResult) DeepCopyInto(out *SLOReportResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReportResult.
+func (in *SLOReportResult) DeepCopy() *SLOReportResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReportResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReportSpec) DeepCopyInto(out *SLOReportSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(SLOReportOutput)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReportSpec.
+func (in *SLOReportSpec) DeepCopy() *SLOReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReportStatus) DeepCopyInto(out *SLOReportStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+	in.NextRunTime.DeepCopyInto(&out.NextRunTime)
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]SLOReportResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReportStatus.
+func (in *SLOReportStatus) DeepCopy() *SLOReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
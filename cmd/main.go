@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
@@ -9,24 +10,33 @@ import (
 	monitoringv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 
 	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	oskometrics "github.com/oskoperator/osko/internal/metrics"
+	"github.com/oskoperator/osko/internal/ruler"
+	"github.com/oskoperator/osko/internal/utils"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	openslov2alpha "github.com/oskoperator/osko/api/openslo/v2alpha"
 	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
 
 	openslov1controller "github.com/oskoperator/osko/internal/controller/openslo"
 	oskocontroller "github.com/oskoperator/osko/internal/controller/osko"
+	oskowebhook "github.com/oskoperator/osko/internal/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -38,6 +48,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(openslov1.AddToScheme(scheme))
+	utilruntime.Must(openslov2alpha.AddToScheme(scheme))
 	utilruntime.Must(oskov1alpha1.AddToScheme(scheme))
 	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	utilruntime.Must(monitoringv1alpha1.AddToScheme(scheme))
@@ -48,19 +59,28 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var operatorConfigName string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&operatorConfigName, "operator-config-name", oskocontroller.DefaultOperatorConfigName,
+		"The name of the cluster-scoped OperatorConfig the operator runs with.")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 
 	flag.Parse()
-	config.NewConfig()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	baseConfig, err := config.FromEnv()
+	if err != nil {
+		setupLog.Error(err, "invalid operator configuration in the environment")
+		os.Exit(1)
+	}
+	operatorConfig := config.NewStore(baseConfig)
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -86,10 +106,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	loadOperatorConfig(mgr.GetAPIReader(), operatorConfigName, baseConfig, operatorConfig)
+
+	if err := ctrlmetrics.Registry.Register(oskometrics.NewSLOCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register SLO metrics")
+		os.Exit(1)
+	}
+
+	// Shared by the Service controller and the deployment freeze webhook
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &openslov1.SLO{}, utils.SLOServiceIndex, utils.SLOService); err != nil {
+		setupLog.Error(err, "unable to index SLOs by service")
+		os.Exit(1)
+	}
+	// Shared by the SLO and SLI controllers and the SLI webhook
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &openslov1.SLO{}, utils.SLOIndicatorRefIndex, utils.SLOIndicatorRef); err != nil {
+		setupLog.Error(err, "unable to index SLOs by indicatorRef")
+		os.Exit(1)
+	}
+
+	rulerDispatcher := ruler.NewDispatcher(ruler.OptionsFromConfig(baseConfig.Ruler))
+	if err := mgr.Add(rulerDispatcher); err != nil {
+		setupLog.Error(err, "unable to set up ruler write queue")
+		os.Exit(1)
+	}
+
 	if err = (&openslov1controller.DatasourceReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("datasource-controller"),
+		Ruler:    rulerDispatcher,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Datasource")
 		os.Exit(1)
@@ -98,20 +143,35 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("slo-controller"),
+		Config:   operatorConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SLO")
 		os.Exit(1)
 	}
 	if err = (&openslov1controller.SLIReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("sli-controller"),
+		Config:   operatorConfig,
+		// The SLI webhook rejects deleting SLIs in use, the finalizer only stands in for it
+		BlockDeletionInUse: !baseConfig.EnableWebhooks,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SLI")
 		os.Exit(1)
 	}
+	if err = (&openslov1controller.ServiceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("service-controller"),
+		Config:   operatorConfig,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Service")
+		os.Exit(1)
+	}
 	if err = (&monitoringcoreoscom.PrometheusRuleReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Config: operatorConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PrometheusRule")
 		os.Exit(1)
@@ -138,10 +198,11 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&oskocontroller.MimirRuleReconciler{
-		Client:             mgr.GetClient(),
-		Scheme:             mgr.GetScheme(),
-		Recorder:           mgr.GetEventRecorderFor("mimirrule-controller"),
-		RequeueAfterPeriod: config.Cfg.MimirRuleRequeuePeriod,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("mimirrule-controller"),
+		Ruler:    rulerDispatcher,
+		Config:   operatorConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MimirRule")
 		os.Exit(1)
@@ -154,6 +215,59 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertManagerConfig")
 		os.Exit(1)
 	}
+	if err = (&oskocontroller.SLOReportReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("sloreport-controller"),
+		Config:   operatorConfig,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SLOReport")
+		os.Exit(1)
+	}
+	if err = (&oskocontroller.OperatorConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("operatorconfig-controller"),
+		Name:     operatorConfigName,
+		Base:     baseConfig,
+		Config:   operatorConfig,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+	if len(baseConfig.Export.Namespaces) > 0 {
+		if err = (&openslov1controller.ExportReconciler{
+			Client: mgr.GetClient(),
+			Export: baseConfig.Export,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OpenSLOExport")
+			os.Exit(1)
+		}
+	}
+	if baseConfig.EnableWebhooks {
+		if err = oskowebhook.SetupSLOWebhookWithManager(mgr, operatorConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SLO")
+			os.Exit(1)
+		}
+		if err = oskowebhook.SetupSLIWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SLI")
+			os.Exit(1)
+		}
+		if err = oskowebhook.SetupDatasourceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Datasource")
+			os.Exit(1)
+		}
+		if err = oskowebhook.SetupConversionWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Conversion")
+			os.Exit(1)
+		}
+	}
+	if baseConfig.DeploymentFreezeMode != oskowebhook.FreezeModeDisabled {
+		if err = oskowebhook.SetupDeploymentFreezeWebhookWithManager(mgr, baseConfig.DeploymentFreezeMode); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DeploymentFreeze")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -171,3 +285,22 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadOperatorConfig applies the OperatorConfig before the controllers start, so the first reconciles already run with it.
+// The OperatorConfig controller keeps it current from then on.
+func loadOperatorConfig(reader client.Reader, name string, base config.Config, store *config.Store) {
+	operatorConfig := &oskov1alpha1.OperatorConfig{}
+	if err := reader.Get(context.Background(), client.ObjectKey{Name: name}, operatorConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			setupLog.Error(err, "unable to load OperatorConfig, starting with the configuration from the environment", "name", name)
+		}
+		return
+	}
+	cfg, err := helpers.OperatorConfigFromSpec(base, operatorConfig.Spec)
+	if err != nil {
+		setupLog.Error(err, "invalid OperatorConfig, starting with the configuration from the environment", "name", name)
+		return
+	}
+	store.Set(cfg)
+	setupLog.Info("loaded OperatorConfig", "name", name, "generation", operatorConfig.Generation)
+}
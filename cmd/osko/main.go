@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/oskoperator/osko/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:], cli.Streams{In: os.Stdin, Out: os.Stdout, Err: os.Stderr}))
+}
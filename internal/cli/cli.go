@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// ErrUsage is returned for invalid command lines, the command prints its usage along with it
+var ErrUsage = errors.New("invalid usage")
+
+// Streams are the standard streams a command reads from and writes to
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// Command is a subcommand of the osko CLI
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(args []string, streams Streams) error
+}
+
+// Commands are the subcommands of the osko CLI
+var Commands = []Command{
+	{Name: "generate", Summary: "Print the rules generated for OpenSLO manifests", Run: runGenerate},
+	{Name: "lint", Summary: "Check OpenSLO manifests before they reach the cluster", Run: runLint},
+	{Name: "export", Summary: "Export the OpenSLO objects of a cluster as OpenSLO specification bundles", Run: runExport},
+	{Name: "import", Summary: "Convert Sloth and Pyrra SLO definitions into OpenSLO manifests", Run: runImport},
+	{Name: "test", Summary: "Run rule unit tests against the rules generated for OpenSLO manifests", Run: runTest},
+}
+
+// Main runs the subcommand named by the first argument and returns the exit code of the CLI
+func Main(args []string, streams Streams) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		usage(streams.Err)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range Commands {
+		if cmd.Name != args[0] {
+			continue
+		}
+		err := cmd.Run(args[1:], streams)
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, ErrUsage):
+			fmt.Fprintf(streams.Err, "osko %s: %v\n", cmd.Name, err)
+			return 2
+		default:
+			fmt.Fprintf(streams.Err, "osko %s: %v\n", cmd.Name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(streams.Err, "osko: unknown command %q\n", args[0])
+	usage(streams.Err)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: osko <command> [flags] [files...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range Commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.Name, cmd.Summary)
+	}
+}
+
+// newFlagSet returns the flag set of a command, parse errors are reported as ErrUsage
+func newFlagSet(name, args string, streams Streams) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(streams.Err)
+	fs.Usage = func() {
+		fmt.Fprintf(streams.Err, "Usage: osko %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the arguments of a command and wraps parse errors into ErrUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMainExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no command", args: nil, want: 2},
+		{name: "unknown command", args: []string{"deploy"}, want: 2},
+		{name: "no manifests", args: []string{"generate"}, want: 2},
+		{name: "missing file", args: []string{"generate", "missing.yaml"}, want: 1},
+		{name: "help", args: []string{"generate", "-h"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if got := Main(tt.args, Streams{In: strings.NewReader(""), Out: &out, Err: &errOut}); got != tt.want {
+				t.Errorf("Main() = %d, want %d, stderr:\n%s", got, tt.want, errOut.String())
+			}
+		})
+	}
+}
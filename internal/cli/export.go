@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oskoperator/osko/internal/exporter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func runExport(args []string, streams Streams) error {
+	fs := newFlagSet("export", "", streams)
+	namespaces := fs.String("namespaces", "default", "Comma-separated namespaces to export")
+	allNamespaces := fs.Bool("all-namespaces", false, "Export every namespace with OpenSLO objects")
+	indicators := fs.String("indicators", exporter.IndicatorsReference, "How to export the indicators of the SLOs: reference (as SLIs) or inline")
+	outputDir := fs.String("output-dir", "", "Directory to write a <namespace>.yaml bundle per namespace to, instead of stdout")
+	kubeconfig := fs.String("kubeconfig", "", "Path to the kubeconfig file, the KUBECONFIG env variable or ~/.kube/config when empty")
+	kubeContext := fs.String("context", "", "Context of the kubeconfig to use, its current context when empty")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: export takes no arguments", ErrUsage)
+	}
+
+	var selected []string
+	if !*allNamespaces {
+		for _, namespace := range strings.Split(*namespaces, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace != "" {
+				selected = append(selected, namespace)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("%w: no namespaces given", ErrUsage)
+		}
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: *kubeContext}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	return Export(context.Background(), streams.Out, c, selected, *indicators, *outputDir)
+}
+
+// Export writes the OpenSLO bundles of the given namespaces, of every namespace with OpenSLO objects when none
+// are given. Bundles are written to a <namespace>.yaml file each when an output directory is given, otherwise
+// to the writer, every bundle preceded by a comment naming its namespace.
+func Export(ctx context.Context, w io.Writer, c client.Reader, namespaces []string, indicators, outputDir string) error {
+	listed := map[string]*exporter.Objects{}
+	if len(namespaces) == 0 {
+		objs, err := exporter.List(ctx, c, metav1.NamespaceAll)
+		if err != nil {
+			return err
+		}
+		namespaces = objs.Namespaces()
+		for _, namespace := range namespaces {
+			listed[namespace] = objs.InNamespace(namespace)
+		}
+	}
+
+	for i, namespace := range namespaces {
+		objs, ok := listed[namespace]
+		if !ok {
+			var err error
+			if objs, err = exporter.List(ctx, c, namespace); err != nil {
+				return fmt.Errorf("namespace %s: %w", namespace, err)
+			}
+		}
+		bundle, err := exporter.Render(objs, indicators)
+		if err != nil {
+			return fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+
+		if outputDir != "" {
+			if err := os.MkdirAll(outputDir, 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(outputDir, namespace+".yaml"), bundle, 0o644); err != nil {
+				return err
+			}
+			continue
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# Namespace %s\n%s", namespace, bundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
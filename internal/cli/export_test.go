@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/exporter"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newExportTestClient(t *testing.T) client.Client {
+	t.Helper()
+	objs, err := Load([]string{"-"}, "shop", strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range objs.SLOs {
+		builder.WithObjects(&objs.SLOs[i])
+	}
+	for i := range objs.SLIs {
+		builder.WithObjects(&objs.SLIs[i])
+	}
+	for i := range objs.Datasources {
+		builder.WithObjects(&objs.Datasources[i])
+	}
+	return builder.Build()
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	c := newExportTestClient(t)
+
+	for _, indicators := range exporter.Indicators {
+		t.Run(indicators, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Export(context.Background(), &out, c, nil, indicators, ""); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if !strings.HasPrefix(out.String(), "# Namespace shop\napiVersion: openslo/v1\n") {
+				t.Errorf("unexpected bundle:\n%s", out.String())
+			}
+
+			objs, err := Load([]string{"-"}, "default", &out)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(objs.SLOs) == 0 || len(objs.Datasources) == 0 {
+				t.Fatalf("bundle lost objects: %d SLOs, %d Datasources", len(objs.SLOs), len(objs.Datasources))
+			}
+			if findings := Lint(objs, config.Default(), LintOptions{}); len(findings) > 0 {
+				t.Errorf("exported bundle has findings %+v", findings)
+			}
+		})
+	}
+}
+
+func TestExportOutputDir(t *testing.T) {
+	c := newExportTestClient(t)
+	dir := filepath.Join(t.TempDir(), "bundles")
+
+	if err := Export(context.Background(), &bytes.Buffer{}, c, []string{"shop", "empty"}, exporter.IndicatorsReference, dir); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	shop, err := os.ReadFile(filepath.Join(dir, "shop.yaml"))
+	if err != nil || !strings.Contains(string(shop), "kind: DataSource") {
+		t.Errorf("unexpected shop bundle %q, error %v", shop, err)
+	}
+	if empty, err := os.ReadFile(filepath.Join(dir, "empty.yaml")); err != nil || len(empty) != 0 {
+		t.Errorf("unexpected empty bundle %q, error %v", empty, err)
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Output formats of the generate command
+const (
+	OutputPrometheusRule = "prometheusrule"
+	OutputMimirRule      = "mimirrule"
+	OutputRules          = "rules"
+)
+
+func runGenerate(args []string, streams Streams) error {
+	fs := newFlagSet("generate", "<files...>", streams)
+	output := fs.String("output", OutputPrometheusRule, "Output format: prometheusrule, mimirrule or rules (a Prometheus rule file)")
+	namespace := fs.String("namespace", "default", "Namespace of the objects whose manifests set none")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: no manifests given", ErrUsage)
+	}
+
+	objs, err := Load(fs.Args(), *namespace, streams.In)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return err
+	}
+	return Generate(streams.Out, objs, *output, cfg)
+}
+
+// Generate writes the rules the operator would generate for the SLOs of the manifests in the given format.
+// The rules are generated exactly as in the cluster: the SLODefaults of the manifests and the defaults of the
+// mutating webhook apply, inline indicators become SLIs, and the MimirRules are built from the Datasource the SLO references.
+func Generate(w io.Writer, objs *Objects, output string, cfg config.Config) error {
+	switch output {
+	case OutputPrometheusRule, OutputMimirRule, OutputRules:
+	default:
+		return fmt.Errorf("%w: unknown output format %q", ErrUsage, output)
+	}
+
+	if output == OutputRules {
+		groups, err := RuleFileGroups(objs, cfg)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(rulefmt.RuleGroups{Groups: groups}); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	var printed []runtime.Object
+	for i := range objs.SLOs {
+		slo := effectiveSLO(objs, &objs.SLOs[i], cfg)
+		prometheusRule, err := generatePrometheusRule(objs, slo, cfg)
+		if err != nil {
+			return fmt.Errorf("SLO %s/%s: %w", slo.Namespace, slo.Name, err)
+		}
+		if output == OutputPrometheusRule {
+			printed = append(printed, prometheusRule)
+			continue
+		}
+		mimirRule, err := generateMimirRule(objs, slo, prometheusRule, cfg)
+		if err != nil {
+			return fmt.Errorf("SLO %s/%s: %w", slo.Namespace, slo.Name, err)
+		}
+		printed = append(printed, mimirRule)
+	}
+	return PrintObjects(w, printed...)
+}
+
+// RuleFileGroups generates the rule groups of every SLO of the manifests in their rule file representation,
+// without source tenants, and validates them together the same way as the rule groups uploaded to the ruler
+func RuleFileGroups(objs *Objects, cfg config.Config) ([]rulefmt.RuleGroup, error) {
+	var groups []rulefmt.RuleGroup
+	for i := range objs.SLOs {
+		slo := effectiveSLO(objs, &objs.SLOs[i], cfg)
+		prometheusRule, err := generatePrometheusRule(objs, slo, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("SLO %s/%s: %w", slo.Namespace, slo.Name, err)
+		}
+		ruleGroups, err := helpers.NewMimirRuleGroups(prometheusRule, &oskov1alpha1.ConnectionDetails{}, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("SLO %s/%s: %w", slo.Namespace, slo.Name, err)
+		}
+		for j := range ruleGroups {
+			group, err := helpers.NewRuleFileGroup(&ruleGroups[j])
+			if err != nil {
+				return nil, fmt.Errorf("SLO %s/%s: %w", slo.Namespace, slo.Name, err)
+			}
+			groups = append(groups, group)
+		}
+	}
+	if err := helpers.ValidateRuleGroups(groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// effectiveSLO returns a copy of the SLO with its SLODefaults and the defaults of the mutating webhook applied
+func effectiveSLO(objs *Objects, slo *openslov1.SLO, cfg config.Config) *openslov1.SLO {
+	effective := helpers.WithSLODefaults(slo, objs.SLODefaultsFor(slo.Namespace))
+	helpers.SetSLODefaults(effective, cfg)
+	return effective
+}
+
+// generatePrometheusRule generates the PrometheusRule of an SLO, without the owner reference the SLO has no UID for
+func generatePrometheusRule(objs *Objects, slo *openslov1.SLO, cfg config.Config) (*monitoringv1.PrometheusRule, error) {
+	var sli *openslov1.SLI
+	switch {
+	case slo.Spec.IndicatorRef != nil:
+		if sli = objs.SLI(slo.Namespace, *slo.Spec.IndicatorRef); sli == nil {
+			return nil, fmt.Errorf("SLI %s not found in the manifests", *slo.Spec.IndicatorRef)
+		}
+	case slo.Spec.Indicator != nil:
+		sli = helpers.NewInlineSLI(slo)
+	default:
+		return nil, fmt.Errorf("SLO has neither an indicator nor an indicatorRef")
+	}
+
+	prometheusRule, err := helpers.CreatePrometheusRule(slo, sli, cfg)
+	if err != nil {
+		return nil, err
+	}
+	prometheusRule.OwnerReferences = nil
+	return prometheusRule, nil
+}
+
+// generateMimirRule generates the MimirRule of an SLO from the Datasource its osko.dev/datasourceRef names
+func generateMimirRule(objs *Objects, slo *openslov1.SLO, prometheusRule *monitoringv1.PrometheusRule, cfg config.Config) (*oskov1alpha1.MimirRule, error) {
+	dsName := slo.Annotations["osko.dev/datasourceRef"]
+	if dsName == "" {
+		return nil, fmt.Errorf("SLO has no osko.dev/datasourceRef annotation")
+	}
+	ds := objs.Datasource(slo.Namespace, dsName)
+	if ds == nil {
+		return nil, fmt.Errorf("Datasource %s not found in the manifests", dsName)
+	}
+
+	mimirRule, err := helpers.NewMimirRule(slo, prometheusRule, &ds.Spec.ConnectionDetails, cfg)
+	if err != nil {
+		return nil, err
+	}
+	mimirRule.TypeMeta.APIVersion = oskov1alpha1.GroupVersion.String()
+	mimirRule.TypeMeta.Kind = "MimirRule"
+	mimirRule.OwnerReferences = nil
+	return mimirRule, nil
+}
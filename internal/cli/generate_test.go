@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		manifest string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "prometheus rule",
+			output:   OutputPrometheusRule,
+			manifest: testManifests,
+			want:     []string{"kind: PrometheusRule", "name: availability", "record: osko_sli_good"},
+		},
+		{
+			name:     "mimir rule",
+			output:   OutputMimirRule,
+			manifest: testManifests,
+			want:     []string{"apiVersion: osko.dev/v1alpha1", "kind: MimirRule", "address: http://mimir:9009/"},
+		},
+		{
+			name:     "rule file from an inline indicator",
+			output:   OutputRules,
+			manifest: testInlineSLO,
+			want:     []string{"name: latency_sli_good", `sli_name: latency-sli`},
+		},
+		{
+			name:     "mimir rule without datasource",
+			output:   OutputMimirRule,
+			manifest: testInlineSLO,
+			wantErr:  "no osko.dev/datasourceRef annotation",
+		},
+		{
+			name:     "unknown output",
+			output:   "json",
+			manifest: testManifests,
+			wantErr:  `unknown output format "json"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, err := Load([]string{"-"}, "default", strings.NewReader(tt.manifest))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			err = Generate(&out, objs, tt.output, config.Default())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "ownerReferences") || strings.Contains(out.String(), "creationTimestamp") {
+				t.Errorf("output contains cluster-only metadata:\n%s", out.String())
+			}
+			if tt.output == OutputRules {
+				if _, errs := rulefmt.Parse(out.Bytes()); len(errs) > 0 {
+					t.Errorf("rule file does not parse: %v", errs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/oskoperator/osko/internal/importer"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func runImport(args []string, streams Streams) error {
+	fs := newFlagSet("import", "<files...>", streams)
+	opts := importer.Options{}
+	fs.StringVar(&opts.Namespace, "namespace", "default", "Namespace of the objects whose definitions set none")
+	fs.StringVar(&opts.Datasource, "datasource", "", "Datasource the SLOs reference with osko.dev/datasourceRef, the namespace default applies when empty")
+	fs.StringVar(&opts.Window, "window", importer.DefaultWindow, "Time window of the SLOs whose definitions set none")
+	fs.StringVar(&opts.MetricSourceType, "metric-source-type", importer.DefaultMetricSourceType, "Metric source type of the SLIs")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: no definitions given", ErrUsage)
+	}
+
+	objs, failed, err := Import(fs.Args(), opts, streams)
+	if err != nil {
+		return err
+	}
+	if err := PrintObjects(streams.Out, objs...); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d SLOs could not be imported", failed, failed+len(objs)/2)
+	}
+	return nil
+}
+
+// Import converts the Sloth and Pyrra SLO definitions of the given files and directories into OpenSLO SLOs and SLIs.
+// Warnings and the SLOs that cannot be converted are reported on the error stream, along with their source,
+// and counted in the second return value. Documents of other kinds are skipped.
+func Import(paths []string, opts importer.Options, streams Streams) ([]runtime.Object, int, error) {
+	var (
+		objs   []runtime.Object
+		failed int
+	)
+	convert := func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, doc := range splitDocuments(data) {
+			if len(bytes.TrimSpace(doc.content)) == 0 {
+				continue
+			}
+			result, err := importer.Convert(doc.content, opts)
+			if errors.Is(err, importer.ErrUnsupportedKind) {
+				continue
+			}
+			if result == nil {
+				return fmt.Errorf("%s:%d: %w", name, doc.line, err)
+			}
+			for _, warning := range result.Warnings {
+				fmt.Fprintf(streams.Err, "%s:%d: warning: %s\n", name, doc.line, warning)
+			}
+			if err != nil {
+				failed += len(unwrapJoined(err))
+				for _, e := range unwrapJoined(err) {
+					fmt.Fprintf(streams.Err, "%s:%d: error: %v\n", name, doc.line, e)
+				}
+			}
+			for i := range result.SLIs {
+				objs = append(objs, &result.SLIs[i], &result.SLOs[i])
+			}
+		}
+		return nil
+	}
+
+	for _, path := range paths {
+		if path == "-" {
+			if err := convert("<stdin>", streams.In); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, 0, err
+			}
+			err = convert(file, f)
+			f.Close()
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return objs, failed, nil
+}
+
+// unwrapJoined returns the errors joined into an error, or the error itself
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
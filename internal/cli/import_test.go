@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+)
+
+const testImportDefinitions = `apiVersion: pyrra.dev/v1alpha1
+kind: ServiceLevelObjective
+metadata:
+  name: api-errors
+  labels:
+    pyrra.dev/team: operations
+spec:
+  target: "99.5"
+  window: 2w
+  indicator:
+    ratio:
+      errors:
+        metric: http_requests_total{job="api",code=~"5.."}
+      total:
+        metric: http_requests_total{job="api"}
+---
+apiVersion: pyrra.dev/v1alpha1
+kind: ServiceLevelObjective
+metadata:
+  name: api-up
+spec:
+  target: "99"
+  window: 2w
+  indicator:
+    bool_gauge:
+      metric: up{job="api"}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+`
+
+func TestImport(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := Main([]string{"import", "-datasource", "mimir", "-"}, Streams{In: strings.NewReader(testImportDefinitions), Out: &out, Err: &errOut})
+	if code != 1 {
+		t.Errorf("Main() = %d, want 1 for the bool_gauge indicator", code)
+	}
+	if !strings.Contains(errOut.String(), "<stdin>:17: error: ServiceLevelObjective api-up: bool_gauge indicators are not supported") {
+		t.Errorf("unexpected stderr:\n%s", errOut.String())
+	}
+
+	objs, err := Load([]string{"-"}, "default", &out)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(objs.SLOs) != 1 || len(objs.SLIs) != 1 {
+		t.Fatalf("expected one SLO and SLI, got %d and %d", len(objs.SLOs), len(objs.SLIs))
+	}
+	if findings := Lint(objs, config.Default(), LintOptions{ExternalRefs: true}); len(findings) != 1 || findings[0].Rule != RuleDanglingRef.ID {
+		t.Errorf("expected the Datasource to be the only finding, got %+v", findings)
+	}
+}
@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Output formats of the lint command
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Severities of lint findings
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// LintRule is a check of the lint command
+type LintRule struct {
+	ID          string
+	Description string
+}
+
+// Rules of the lint command
+var (
+	RuleInvalidTarget   = LintRule{ID: "invalid-target", Description: "Objective targets must be between 0 and 1, targetPercent between 0 and 100, and agree with each other"}
+	RuleInvalidDuration = LintRule{ID: "invalid-duration", Description: "Durations must be a whole number of seconds, minutes, hours or days, such as 5m or 28d"}
+	RuleInvalidQuery    = LintRule{ID: "invalid-query", Description: "Metric source queries must be valid PromQL"}
+	RuleInvalidSpec     = LintRule{ID: "invalid-spec", Description: "Specs must pass the validation the admission webhooks run"}
+	RuleDanglingRef     = LintRule{ID: "dangling-ref", Description: "References must name an object of the manifests"}
+	RuleUnsupported     = LintRule{ID: "unsupported", Description: "OpenSLO features the operator does not implement are ignored"}
+	RuleRuleGeneration  = LintRule{ID: "rule-generation", Description: "The operator must be able to generate the rules of an SLO"}
+
+	LintRules = []LintRule{
+		RuleInvalidTarget, RuleInvalidDuration, RuleInvalidQuery, RuleInvalidSpec,
+		RuleDanglingRef, RuleUnsupported, RuleRuleGeneration,
+	}
+)
+
+// durationPattern is the pattern of the Duration CRD fields, the API server rejects every other duration
+var durationPattern = regexp.MustCompile(`^[1-9]\d*[smhd]$`)
+
+// Finding is a problem lint found in an object of the manifests
+type Finding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+}
+
+// LintOptions tune the checks of the lint command
+type LintOptions struct {
+	// ExternalRefs reports references to objects missing from the manifests as warnings instead of errors,
+	// for manifests that reference objects managed elsewhere
+	ExternalRefs bool
+}
+
+func runLint(args []string, streams Streams) error {
+	fs := newFlagSet("lint", "<files...>", streams)
+	format := fs.String("format", FormatText, "Output format: text, json or sarif")
+	namespace := fs.String("namespace", "default", "Namespace of the objects whose manifests set none")
+	externalRefs := fs.Bool("external-refs", false, "Report references to objects missing from the manifests as warnings")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: no manifests given", ErrUsage)
+	}
+	switch *format {
+	case FormatText, FormatJSON, FormatSARIF:
+	default:
+		return fmt.Errorf("%w: unknown output format %q", ErrUsage, *format)
+	}
+
+	objs, err := Load(fs.Args(), *namespace, streams.In)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	findings := Lint(objs, cfg, LintOptions{ExternalRefs: *externalRefs})
+	if err := PrintFindings(streams.Out, findings, *format); err != nil {
+		return err
+	}
+	if errs := countSeverity(findings, SeverityError); errs > 0 {
+		return fmt.Errorf("%d of %d findings are errors", errs, len(findings))
+	}
+	return nil
+}
+
+// Lint checks the objects of the manifests with the validation the operator and its webhooks run, and reports
+// references to objects missing from the manifests and the features the operator would silently ignore
+func Lint(objs *Objects, cfg config.Config, opts LintOptions) []Finding {
+	l := &linter{objs: objs, opts: opts}
+
+	for i := range objs.SLOs {
+		l.lintSLO(&objs.SLOs[i], cfg)
+	}
+	for i := range objs.SLIs {
+		sli := &objs.SLIs[i]
+		at := l.at("SLI", sli.Namespace, sli.Name)
+		l.fieldErrors(at, helpers.ValidateSLI(sli))
+		l.lintSLISpec(at, field.NewPath("spec"), &sli.Spec)
+	}
+	for i := range objs.Datasources {
+		ds := &objs.Datasources[i]
+		l.fieldErrors(l.at("Datasource", ds.Namespace, ds.Name), helpers.ValidateDatasource(ds))
+	}
+	for i := range objs.AlertPolicies {
+		policy := &objs.AlertPolicies[i]
+		l.lintAlertPolicySpec(l.at("AlertPolicy", policy.Namespace, policy.Name), field.NewPath("spec"), &policy.Spec)
+	}
+	for i := range objs.AlertConditions {
+		condition := &objs.AlertConditions[i]
+		l.lintAlertConditionSpec(l.at("AlertCondition", condition.Namespace, condition.Name), field.NewPath("spec"), &condition.Spec)
+	}
+	return l.findings
+}
+
+type linter struct {
+	objs     *Objects
+	opts     LintOptions
+	findings []Finding
+}
+
+// at returns a finding template for an object of the manifests
+func (l *linter) at(kind, namespace, name string) Finding {
+	source := l.objs.Source(kind, namespace, name)
+	return Finding{Kind: kind, Namespace: namespace, Name: name, File: source.File, Line: source.Line}
+}
+
+func (l *linter) report(at Finding, rule LintRule, severity string, path *field.Path, message string) {
+	at.Rule, at.Severity, at.Message = rule.ID, severity, message
+	if path != nil {
+		at.Field = path.String()
+	}
+	// The same problem is often caught by several checks, only the first one reports it
+	for _, f := range l.findings {
+		if f.Rule == at.Rule && f.Kind == at.Kind && f.Namespace == at.Namespace && f.Name == at.Name && f.Field == at.Field {
+			return
+		}
+	}
+	l.findings = append(l.findings, at)
+}
+
+// fieldErrors reports the errors of the operator validation under the rule their field belongs to
+func (l *linter) fieldErrors(at Finding, errs field.ErrorList) {
+	for _, err := range errs {
+		rule := RuleInvalidSpec
+		switch {
+		case strings.HasSuffix(err.Field, ".query"):
+			rule = RuleInvalidQuery
+		case strings.HasSuffix(err.Field, ".target"), strings.HasSuffix(err.Field, ".targetPercent"):
+			rule = RuleInvalidTarget
+		case strings.HasSuffix(err.Field, ".duration"):
+			rule = RuleInvalidDuration
+		}
+		at.Field = err.Field
+		l.report(at, rule, SeverityError, nil, err.ErrorBody())
+	}
+}
+
+func (l *linter) duration(at Finding, path *field.Path, value openslov1.Duration) {
+	if value != "" && !durationPattern.MatchString(string(value)) {
+		l.report(at, RuleInvalidDuration, SeverityError, path,
+			fmt.Sprintf("Invalid value: %q: must be a whole number of seconds, minutes, hours or days", value))
+	}
+}
+
+func (l *linter) ref(at Finding, path *field.Path, kind, name string, found bool) {
+	if found {
+		return
+	}
+	severity := SeverityError
+	if l.opts.ExternalRefs {
+		severity = SeverityWarning
+	}
+	l.report(at, RuleDanglingRef, severity, path, fmt.Sprintf("%s %s not found in the manifests", kind, name))
+}
+
+func (l *linter) unsupported(at Finding, path *field.Path, message string) {
+	l.report(at, RuleUnsupported, SeverityWarning, path, message)
+}
+
+func (l *linter) lintSLO(original *openslov1.SLO, cfg config.Config) {
+	at := l.at("SLO", original.Namespace, original.Name)
+	spec := field.NewPath("spec")
+
+	// The SLO is checked the way it is stored: with its SLODefaults and the defaults of the mutating webhook applied
+	slo := effectiveSLO(l.objs, original, cfg)
+	l.fieldErrors(at, helpers.ValidateSLO(slo))
+
+	for i, tw := range slo.Spec.TimeWindow {
+		l.duration(at, spec.Child("timeWindow").Index(i).Child("duration"), tw.Duration)
+	}
+	if len(slo.Spec.TimeWindow) > 1 {
+		l.unsupported(at, spec.Child("timeWindow"), "only the first time window is used, the others are ignored")
+	}
+	if len(original.Spec.TimeWindow) > 0 {
+		if calendar := original.Spec.TimeWindow[0].Calendar; calendar.StartTime != "" || calendar.TimeZone != "" {
+			l.unsupported(at, spec.Child("timeWindow").Index(0), "calendar-aligned windows are evaluated as rolling windows of the same duration")
+		}
+	}
+
+	if len(slo.Spec.Objectives) > 1 {
+		l.unsupported(at, spec.Child("objectives"), "only the first objective generates rules, the others are ignored")
+	}
+	for i, objective := range slo.Spec.Objectives {
+		path := spec.Child("objectives").Index(i)
+		l.duration(at, path.Child("timeSliceWindow"), objective.TimeSliceWindow)
+		if objective.Op != "" || objective.Value != "" {
+			l.unsupported(at, path.Child("op"), "threshold objectives are not supported, op and value are ignored")
+		}
+		if objective.TimeSliceTarget != "" || objective.TimeSliceWindow != "" {
+			l.unsupported(at, path.Child("timeSliceTarget"), "time slice objectives are not supported, the budget is computed over occurrences")
+		}
+		if objective.Indicator != nil || objective.IndicatorRef != nil {
+			l.unsupported(at, path.Child("indicator"), "indicators of single objectives are ignored, the indicator of the SLO is used")
+		}
+		if objective.IndicatorRef != nil && *objective.IndicatorRef != "" {
+			l.ref(at, path.Child("indicatorRef"), "SLI", *objective.IndicatorRef, l.objs.SLI(slo.Namespace, *objective.IndicatorRef) != nil)
+		}
+	}
+
+	if slo.Spec.IndicatorRef != nil && *slo.Spec.IndicatorRef != "" {
+		l.ref(at, spec.Child("indicatorRef"), "SLI", *slo.Spec.IndicatorRef, l.objs.SLI(slo.Namespace, *slo.Spec.IndicatorRef) != nil)
+	}
+	if slo.Spec.Indicator != nil {
+		l.lintSLISpec(at, spec.Child("indicator", "spec"), &slo.Spec.Indicator.Spec)
+	}
+
+	annotation := field.NewPath("metadata", "annotations").Key("osko.dev/datasourceRef")
+	if dsName := slo.Annotations["osko.dev/datasourceRef"]; dsName == "" {
+		l.report(at, RuleInvalidSpec, SeverityError, annotation, "Required value: the SLO has no Datasource to write its rules to")
+	} else {
+		l.ref(at, annotation, "Datasource", dsName, l.objs.Datasource(slo.Namespace, dsName) != nil)
+	}
+
+	if len(slo.Spec.AlertPolicies) > 0 {
+		l.unsupported(at, spec.Child("alertPolicies"), "alert policies are not turned into alerts, the osko.dev/magicAlerting annotation generates the burn rate alerts")
+	}
+	for i, policy := range slo.Spec.AlertPolicies {
+		path := spec.Child("alertPolicies").Index(i)
+		if policy.AlertPolicyRef != nil {
+			l.ref(at, path.Child("alertPolicyRef"), "AlertPolicy", *policy.AlertPolicyRef, l.alertPolicyExists(slo.Namespace, *policy.AlertPolicyRef))
+		}
+		if policy.Spec != nil {
+			l.lintAlertPolicySpec(at, path.Child("spec"), policy.Spec)
+		}
+	}
+
+	// Only SLOs that pass the validation are worth generating the rules of, references do not matter to the rules
+	for _, f := range l.findings {
+		if f.Kind == at.Kind && f.Namespace == at.Namespace && f.Name == at.Name && f.Severity == SeverityError && f.Rule != RuleDanglingRef.ID {
+			return
+		}
+	}
+	if slo.Spec.IndicatorRef != nil && l.objs.SLI(slo.Namespace, *slo.Spec.IndicatorRef) == nil {
+		return
+	}
+	if _, err := generatePrometheusRule(l.objs, slo, cfg); err != nil {
+		l.report(at, RuleRuleGeneration, SeverityError, nil, err.Error())
+	}
+}
+
+func (l *linter) lintSLISpec(at Finding, path *field.Path, spec *openslov1.SLISpec) {
+	sources := []struct {
+		path   *field.Path
+		source openslov1.MetricSource
+	}{
+		{path.Child("ratioMetric", "good", "metricSource"), spec.RatioMetric.Good.MetricSource},
+		{path.Child("ratioMetric", "bad", "metricSource"), spec.RatioMetric.Bad.MetricSource},
+		{path.Child("ratioMetric", "total", "metricSource"), spec.RatioMetric.Total.MetricSource},
+		{path.Child("ratioMetric", "raw", "metricSource"), spec.RatioMetric.Raw.MetricSource},
+		{path.Child("thresholdMetric", "metricSource"), spec.ThresholdMetric.MetricSource},
+	}
+	for _, s := range sources {
+		if s.source.MetricSourceRef != "" {
+			l.ref(at, s.path.Child("metricSourceRef"), "Datasource", s.source.MetricSourceRef, l.objs.Datasource(at.Namespace, s.source.MetricSourceRef) != nil)
+		}
+	}
+
+	if spec.ThresholdMetric.MetricSource.Spec.Query != "" {
+		l.unsupported(at, path.Child("thresholdMetric"), "threshold metrics are not supported, only ratio metrics generate rules")
+	}
+	if spec.RatioMetric.Raw.MetricSource.Spec.Query != "" || spec.RatioMetric.RawType != "" {
+		l.unsupported(at, path.Child("ratioMetric", "raw"), "raw ratio metrics are not supported, only good or bad and total queries generate rules")
+	}
+}
+
+func (l *linter) lintAlertPolicySpec(at Finding, path *field.Path, spec *openslov1.AlertPolicySpec) {
+	for i, condition := range spec.Conditions {
+		conditionPath := path.Child("conditions").Index(i)
+		if condition.ConditionRef != nil {
+			l.ref(at, conditionPath.Child("conditionRef"), "AlertCondition", *condition.ConditionRef, l.alertConditionExists(at.Namespace, *condition.ConditionRef))
+		}
+		if condition.Spec != nil {
+			l.lintAlertConditionSpec(at, conditionPath.Child("spec"), condition.Spec)
+		}
+	}
+	for i, target := range spec.NotificationTargets {
+		if target.TargetRef != nil {
+			l.ref(at, path.Child("notificationTargets").Index(i).Child("targetRef"), "AlertNotificationTarget", *target.TargetRef,
+				l.notificationTargetExists(at.Namespace, *target.TargetRef))
+		}
+	}
+}
+
+func (l *linter) lintAlertConditionSpec(at Finding, path *field.Path, spec *openslov1.AlertConditionSpec) {
+	l.duration(at, path.Child("condition", "lookbackWindow"), spec.Condition.LookbackWindow)
+	l.duration(at, path.Child("condition", "alertAfter"), spec.Condition.AlertAfter)
+}
+
+func (l *linter) alertPolicyExists(namespace, name string) bool {
+	for _, p := range l.objs.AlertPolicies {
+		if p.Namespace == namespace && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) alertConditionExists(namespace, name string) bool {
+	for _, c := range l.objs.AlertConditions {
+		if c.Namespace == namespace && c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) notificationTargetExists(namespace, name string) bool {
+	for _, t := range l.objs.AlertNotificationTargets {
+		if t.Namespace == namespace && t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func countSeverity(findings []Finding, severity string) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// PrintFindings writes lint findings in the given format
+func PrintFindings(w io.Writer, findings []Finding, format string) error {
+	switch format {
+	case FormatJSON:
+		if findings == nil {
+			findings = []Finding{}
+		}
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeJSON(w, newSARIFLog(findings))
+	}
+
+	for _, f := range findings {
+		location := f.File
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		object := fmt.Sprintf("%s %s/%s", f.Kind, f.Namespace, f.Name)
+		if f.Field != "" {
+			object += " " + f.Field
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s: %s [%s]\n", location, f.Severity, object, f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+)
+
+const testLintManifests = `apiVersion: openslo.com/v1
+kind: SLO
+metadata:
+  name: broken
+  annotations:
+    osko.dev/datasourceRef: missing
+spec:
+  service: checkout
+  budgetingMethod: Occurrences
+  indicatorRef: missing-sli
+  objectives:
+    - target: "1.5"
+  timeWindow:
+    - duration: 28d
+      isRolling: true
+---
+apiVersion: openslo.com/v1
+kind: SLO
+metadata:
+  name: ignored-features
+  annotations:
+    osko.dev/datasourceRef: mimir
+spec:
+  service: checkout
+  budgetingMethod: Occurrences
+  indicator:
+    spec:
+      ratioMetric:
+        counter: true
+        good:
+          metricSource:
+            type: Mimir
+            spec:
+              query: http_requests_total{code!~"5.."}
+        total:
+          metricSource:
+            type: Mimir
+            spec:
+              query: sum(rate(http_requests_total[5m])
+  objectives:
+    - target: "0.99"
+    - target: "0.95"
+  timeWindow:
+    - duration: 28d
+      isRolling: true
+  alertPolicies:
+    - alertPolicyRef: missing-policy
+---
+apiVersion: openslo.com/v1
+kind: AlertCondition
+metadata:
+  name: fast-burn
+spec:
+  severity: page
+  condition:
+    kind: burnrate
+    op: gte
+    threshold: "14"
+    lookbackWindow: 1w
+    alertAfter: 5m
+---
+apiVersion: openslo.com/v1
+kind: Datasource
+metadata:
+  name: mimir
+spec:
+  type: mimir
+  connectionDetails:
+    address: http://mimir:9009/
+`
+
+func TestLint(t *testing.T) {
+	objs, err := Load([]string{"-"}, "default", strings.NewReader(testLintManifests))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     LintOptions
+		object   string
+		rule     string
+		field    string
+		severity string
+	}{
+		{name: "target out of range", object: "broken", rule: "invalid-target", field: "spec.objectives[0].target", severity: SeverityError},
+		{name: "dangling indicatorRef", object: "broken", rule: "dangling-ref", field: "spec.indicatorRef", severity: SeverityError},
+		{name: "dangling datasourceRef", object: "broken", rule: "dangling-ref", field: "metadata.annotations[osko.dev/datasourceRef]", severity: SeverityError},
+		{name: "external refs", opts: LintOptions{ExternalRefs: true}, object: "broken", rule: "dangling-ref", field: "spec.indicatorRef", severity: SeverityWarning},
+		{name: "invalid query", object: "ignored-features", rule: "invalid-query", field: "spec.indicator.spec.ratioMetric.total.metricSource.spec.query", severity: SeverityError},
+		{name: "second objective", object: "ignored-features", rule: "unsupported", field: "spec.objectives", severity: SeverityWarning},
+		{name: "alert policies", object: "ignored-features", rule: "unsupported", field: "spec.alertPolicies", severity: SeverityWarning},
+		{name: "dangling alertPolicyRef", object: "ignored-features", rule: "dangling-ref", field: "spec.alertPolicies[0].alertPolicyRef", severity: SeverityError},
+		{name: "duration pattern", object: "fast-burn", rule: "invalid-duration", field: "spec.condition.lookbackWindow", severity: SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Lint(objs, config.Default(), tt.opts)
+			for _, f := range findings {
+				if f.Name == tt.object && f.Rule == tt.rule && f.Field == tt.field {
+					if f.Severity != tt.severity {
+						t.Errorf("severity = %s, want %s", f.Severity, tt.severity)
+					}
+					if f.File != "<stdin>" || f.Line == 0 {
+						t.Errorf("finding has no source: %+v", f)
+					}
+					return
+				}
+			}
+			t.Errorf("no %s finding on %s %s in %+v", tt.rule, tt.object, tt.field, findings)
+		})
+	}
+}
+
+func TestLintValidManifests(t *testing.T) {
+	objs, err := Load([]string{"-"}, "default", strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if findings := Lint(objs, config.Default(), LintOptions{}); len(findings) > 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestPrintFindingsSARIF(t *testing.T) {
+	findings := []Finding{
+		{Rule: "dangling-ref", Severity: SeverityError, Kind: "SLO", Namespace: "default", Name: "broken", Field: "spec.indicatorRef", Message: "SLI missing not found in the manifests", File: "slos/broken.yaml", Line: 3},
+		{Rule: "unsupported", Severity: SeverityWarning, Kind: "SLO", Namespace: "default", Name: "broken", Message: "ignored", File: "<stdin>", Line: 1},
+	}
+
+	var out bytes.Buffer
+	if err := PrintFindings(&out, findings, FormatSARIF); err != nil {
+		t.Fatalf("PrintFindings() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("output is no JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(LintRules) {
+		t.Fatalf("unexpected SARIF log %+v", log)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 || results[0].Level != "error" || results[1].Level != "warning" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	location := results[0].Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "slos/broken.yaml" || location.Region.StartLine != 3 {
+		t.Errorf("unexpected location %+v", location)
+	}
+	if len(results[1].Locations) != 0 {
+		t.Errorf("stdin findings must have no location, got %+v", results[1].Locations)
+	}
+}
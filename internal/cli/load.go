@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	openslov2alpha "github.com/oskoperator/osko/api/openslo/v2alpha"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/yaml"
+)
+
+// specAPIVersion is the apiVersion of the OpenSLO specification, documents using it are read as openslo.com/v1
+const specAPIVersion = "openslo/v1"
+
+var (
+	scheme = runtime.NewScheme()
+
+	// separator matches the lines separating the documents of a YAML stream
+	separator = regexp.MustCompile(`^---\s*(#.*)?$`)
+)
+
+func init() {
+	utilruntime.Must(openslov1.AddToScheme(scheme))
+	utilruntime.Must(openslov2alpha.AddToScheme(scheme))
+	utilruntime.Must(oskov1alpha1.AddToScheme(scheme))
+}
+
+// Objects are the OpenSLO and osko objects read from manifests
+type Objects struct {
+	SLOs                     []openslov1.SLO
+	SLIs                     []openslov1.SLI
+	Datasources              []openslov1.Datasource
+	AlertPolicies            []openslov1.AlertPolicy
+	AlertConditions          []openslov1.AlertCondition
+	AlertNotificationTargets []openslov1.AlertNotificationTarget
+	Services                 []openslov1.Service
+	SLODefaults              []oskov1alpha1.SLODefaults
+
+	sources map[objectRef]Source
+}
+
+// Source is the place of the manifest an object was read from
+type Source struct {
+	File string
+	// Line is the first line of the YAML document of the object
+	Line int
+}
+
+type objectRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Source returns where the object of the given kind and name was read from
+func (o *Objects) Source(kind, namespace, name string) Source {
+	return o.sources[objectRef{Kind: kind, Namespace: namespace, Name: name}]
+}
+
+// SLI returns the SLI of the given name, nil when the manifests have none
+func (o *Objects) SLI(namespace, name string) *openslov1.SLI {
+	for i := range o.SLIs {
+		if o.SLIs[i].Namespace == namespace && o.SLIs[i].Name == name {
+			return &o.SLIs[i]
+		}
+	}
+	return nil
+}
+
+// Datasource returns the Datasource of the given name, nil when the manifests have none
+func (o *Objects) Datasource(namespace, name string) *openslov1.Datasource {
+	for i := range o.Datasources {
+		if o.Datasources[i].Namespace == namespace && o.Datasources[i].Name == name {
+			return &o.Datasources[i]
+		}
+	}
+	return nil
+}
+
+// SLODefaultsFor returns the SLODefaults the SLOs of a namespace inherit from, nil when the manifests have none
+func (o *Objects) SLODefaultsFor(namespace string) *oskov1alpha1.SLODefaults {
+	for i := range o.SLODefaults {
+		if o.SLODefaults[i].Namespace == namespace && o.SLODefaults[i].Name == oskov1alpha1.SLODefaultsName {
+			return &o.SLODefaults[i]
+		}
+	}
+	return nil
+}
+
+// Load reads the objects of the given files and directories, "-" reads from stdin. Objects without
+// a namespace are put into the given one, documents of kinds other than OpenSLO and osko ones are skipped.
+func Load(paths []string, namespace string, stdin io.Reader) (*Objects, error) {
+	objs := &Objects{}
+	for _, path := range paths {
+		if path == "-" {
+			if err := objs.read("<stdin>", stdin, namespace); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			err = objs.read(file, f, namespace)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return objs, nil
+}
+
+// manifestFiles returns the YAML files of a directory tree, or the path itself when it is a file
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := filepath.Ext(p); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (o *Objects) read(name string, r io.Reader, namespace string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, doc := range splitDocuments(data) {
+		if len(bytes.TrimSpace(doc.content)) == 0 {
+			continue
+		}
+		if err := o.add(doc.content, namespace, Source{File: name, Line: doc.line}); err != nil {
+			return fmt.Errorf("%s:%d: %w", name, doc.line, err)
+		}
+	}
+	return nil
+}
+
+type document struct {
+	content []byte
+	line    int
+}
+
+// splitDocuments splits a YAML stream at its document separators and records the first line of every document
+func splitDocuments(data []byte) []document {
+	var (
+		docs    []document
+		current = document{line: 1}
+	)
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if separator.Match(line) {
+			docs = append(docs, current)
+			current = document{line: i + 2}
+			continue
+		}
+		current.content = append(current.content, line...)
+	}
+	return append(docs, current)
+}
+
+func (o *Objects) add(content []byte, namespace string, source Source) error {
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(content, &u.Object); err != nil {
+		return err
+	}
+	// Documents that are no Kubernetes objects, such as kustomization files, are skipped as well
+	if u.GetAPIVersion() == "" || u.GetKind() == "" {
+		return nil
+	}
+	if u.GetAPIVersion() == specAPIVersion {
+		u.SetAPIVersion(openslov1.GroupVersion.String())
+		// The specification spells the kind DataSource
+		if u.GetKind() == "DataSource" {
+			u.SetKind("Datasource")
+		}
+	}
+	gvk := u.GroupVersionKind()
+	if !scheme.Recognizes(gvk) {
+		return nil
+	}
+	if u.GetNamespace() == "" {
+		u.SetNamespace(namespace)
+	}
+
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return fmt.Errorf("%s %s: %w", gvk.Kind, u.GetName(), err)
+	}
+	if gvk.Version != openslov1.GroupVersion.Version && gvk.Group == openslov1.GroupVersion.Group {
+		if obj, err = toHub(obj); err != nil {
+			return fmt.Errorf("%s %s: %w", gvk.Kind, u.GetName(), err)
+		}
+	}
+
+	if o.sources == nil {
+		o.sources = map[objectRef]Source{}
+	}
+	o.sources[objectRef{Kind: gvk.Kind, Namespace: u.GetNamespace(), Name: u.GetName()}] = source
+
+	switch obj := obj.(type) {
+	case *openslov1.SLO:
+		o.SLOs = append(o.SLOs, *obj)
+	case *openslov1.SLI:
+		o.SLIs = append(o.SLIs, *obj)
+	case *openslov1.Datasource:
+		o.Datasources = append(o.Datasources, *obj)
+	case *openslov1.AlertPolicy:
+		o.AlertPolicies = append(o.AlertPolicies, *obj)
+	case *openslov1.AlertCondition:
+		o.AlertConditions = append(o.AlertConditions, *obj)
+	case *openslov1.AlertNotificationTarget:
+		o.AlertNotificationTargets = append(o.AlertNotificationTargets, *obj)
+	case *openslov1.Service:
+		o.Services = append(o.Services, *obj)
+	case *oskov1alpha1.SLODefaults:
+		o.SLODefaults = append(o.SLODefaults, *obj)
+	}
+	return nil
+}
+
+// toHub converts an object of another OpenSLO version into its openslo.com/v1 representation
+func toHub(obj runtime.Object) (runtime.Object, error) {
+	convertible, ok := obj.(conversion.Convertible)
+	if !ok {
+		return obj, nil
+	}
+
+	var hub conversion.Hub
+	switch obj.(type) {
+	case *openslov2alpha.SLO:
+		hub = &openslov1.SLO{}
+	case *openslov2alpha.SLI:
+		hub = &openslov1.SLI{}
+	case *openslov2alpha.Datasource:
+		hub = &openslov1.Datasource{}
+	case *openslov2alpha.AlertPolicy:
+		hub = &openslov1.AlertPolicy{}
+	default:
+		return obj, nil
+	}
+	if err := convertible.ConvertTo(hub); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
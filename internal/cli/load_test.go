@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testManifests = `apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: availability
+  annotations:
+    osko.dev/datasourceRef: mimir
+spec:
+  service: checkout
+  budgetingMethod: Occurrences
+  indicatorRef: availability-sli
+  objectives:
+    - target: "0.99"
+  timeWindow:
+    - duration: 28d
+      isRolling: true
+---
+apiVersion: openslo.com/v1
+kind: SLI
+metadata:
+  name: availability-sli
+spec:
+  ratioMetric:
+    counter: true
+    good:
+      metricSource:
+        type: Mimir
+        spec:
+          query: http_requests_total{code!~"5.."}
+    total:
+      metricSource:
+        type: Mimir
+        spec:
+          query: http_requests_total
+---
+apiVersion: openslo.com/v1
+kind: Datasource
+metadata:
+  name: mimir
+spec:
+  type: mimir
+  connectionDetails:
+    address: http://mimir:9009/
+    targetTenant: checkout
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+const testInlineSLO = `apiVersion: openslo.com/v2alpha
+kind: SLO
+metadata:
+  name: latency
+  namespace: shop
+spec:
+  service: checkout
+  budgetingMethod: Occurrences
+  sli:
+    spec:
+      ratioMetric:
+        counter: true
+        good:
+          type: Mimir
+          spec:
+            query: http_request_duration_seconds_bucket{le="0.3"}
+        total:
+          type: Mimir
+          spec:
+            query: http_request_duration_seconds_count
+  objectives:
+    - target: "0.95"
+  timeWindow:
+    - duration: 7d
+      isRolling: true
+`
+
+func writeManifest(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "slo.yaml", testManifests)
+	writeManifest(t, dir, "kustomization.yaml", "resources:\n  - slo.yaml\n")
+	writeManifest(t, dir, "README.md", "not a manifest")
+
+	objs, err := Load([]string{dir, "-"}, "team", strings.NewReader(testInlineSLO))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(objs.SLOs) != 2 || len(objs.SLIs) != 1 || len(objs.Datasources) != 1 {
+		t.Fatalf("unexpected objects: %d SLOs, %d SLIs, %d Datasources", len(objs.SLOs), len(objs.SLIs), len(objs.Datasources))
+	}
+	if objs.SLOs[0].Namespace != "team" || objs.SLI("team", "availability-sli") == nil {
+		t.Errorf("objects without a namespace are not in the default namespace")
+	}
+	inline := objs.SLOs[1]
+	if inline.Namespace != "shop" || inline.Spec.Indicator == nil {
+		t.Fatalf("v2alpha SLO was not converted: %+v", inline.Spec)
+	}
+	if got := inline.Spec.Indicator.Spec.RatioMetric.Total.MetricSource.Spec.Query; got != "http_request_duration_seconds_count" {
+		t.Errorf("converted total query = %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/ruletest"
+	"gopkg.in/yaml.v3"
+)
+
+func runTest(args []string, streams Streams) error {
+	fs := newFlagSet("test", "<test files...>", streams)
+	namespace := fs.String("namespace", "default", "Namespace of the objects whose manifests set none")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: no test files given", ErrUsage)
+	}
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	failed, total := 0, 0
+	for _, path := range fs.Args() {
+		results, err := RunTestFile(context.Background(), path, *namespace, cfg)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			total++
+			if len(result.Errors) == 0 {
+				fmt.Fprintf(streams.Out, "PASS %s: %s\n", path, result.Name)
+				continue
+			}
+			failed++
+			fmt.Fprintf(streams.Out, "FAIL %s: %s\n", path, result.Name)
+			for _, err := range result.Errors {
+				fmt.Fprintf(streams.Out, "  %v\n", err)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, total)
+	}
+	return nil
+}
+
+// RunTestFile runs the rule tests of a test file against the rules generated from its manifests
+func RunTestFile(ctx context.Context, path, namespace string, cfg config.Config) ([]ruletest.Result, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file ruletest.TestFile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(file.Manifests) == 0 {
+		return nil, fmt.Errorf("%s: no manifests to generate the rules from", path)
+	}
+
+	manifests := make([]string, 0, len(file.Manifests))
+	for _, m := range file.Manifests {
+		if !filepath.IsAbs(m) {
+			m = filepath.Join(filepath.Dir(path), m)
+		}
+		manifests = append(manifests, m)
+	}
+	objs, err := Load(manifests, namespace, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	groups, err := RuleFileGroups(objs, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ruletest.Run(ctx, groups, file.Tests, file.EvaluationInterval), nil
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+)
+
+const testRuleTestSLO = `apiVersion: openslo.com/v1
+kind: SLO
+metadata:
+  name: availability
+  namespace: shop
+  annotations:
+    osko.dev/magicAlerting: "true"
+spec:
+  service: checkout
+  budgetingMethod: Occurrences
+  indicator:
+    spec:
+      ratioMetric:
+        counter: true
+        good:
+          metricSource:
+            type: Mimir
+            spec:
+              query: http_requests_total{code="200"}
+        total:
+          metricSource:
+            type: Mimir
+            spec:
+              query: http_requests_total
+  objectives:
+    - target: "0.99"
+  timeWindow:
+    - duration: 28d
+      isRolling: true
+`
+
+const testRuleTestFile = `manifests:
+  - slo.yaml
+tests:
+  - name: outage pages
+    input_series:
+      - series: http_requests_total{code="200"}
+        values: 0+60x60 3600x60
+      - series: http_requests_total{code="500"}
+        values: 0x60 60+60x60
+    alert_rule_test:
+      - eval_time: 72m
+        alertname: availability_alert_page_critical
+    promql_expr_test:
+      - expr: osko_error_budget_burn_rate{window="1h"}
+        eval_time: 90m
+        exp_samples:
+          - labels: osko_error_budget_burn_rate{namespace="shop", service="checkout", sli_name="availability-sli", slo_name="availability", window="1h"}
+            value: 50
+  - name: no traffic pages
+    input_series:
+      - series: http_requests_total{code="200"}
+        values: 0+60x60
+    alert_rule_test:
+      - eval_time: 30m
+        alertname: availability_alert_page_critical
+        exp_alerts:
+          - exp_labels:
+              severity: P1
+`
+
+func TestRunTestFile(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "slo.yaml", testRuleTestSLO)
+	path := writeManifest(t, dir, "slo_test.yaml", testRuleTestFile)
+
+	results, err := RunTestFile(context.Background(), path, "default", config.Default())
+	if err != nil {
+		t.Fatalf("RunTestFile() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if len(results[0].Errors) > 0 {
+		t.Errorf("%s failed: %v", results[0].Name, results[0].Errors)
+	}
+	if len(results[1].Errors) == 0 {
+		t.Errorf("%s passed, expected the missing alert to be reported", results[1].Name)
+	}
+}
+
+func TestRunTestFileUnknownField(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "slo.yaml", testRuleTestSLO)
+	path := writeManifest(t, dir, "slo_test.yaml", "manifests: [slo.yaml]\nrule_files: [rules.yaml]\n")
+
+	if _, err := RunTestFile(context.Background(), path, "default", config.Default()); err == nil {
+		t.Error("expected an error for the unknown rule_files field")
+	}
+}
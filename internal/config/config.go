@@ -1,24 +1,196 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"time"
+
+	"github.com/prometheus/common/model"
 )
 
-var Cfg Config
+// Modes of the deployment freeze webhook, see DeploymentFreezeMode
+var deploymentFreezeModes = []string{"disabled", "warn", "enforce"}
 
-func NewConfig() {
-	alertingTool := GetEnv("OSKO_ALERTING_TOOL", "opsgenie")
+// Ways to export the indicators of the SLOs, see ExportConfig
+var exportIndicators = []string{"reference", "inline"}
 
-	Cfg = Config{
-		MimirRuleRequeuePeriod: GetEnvAsDuration("MIMIR_RULE_REQUEUE_PERIOD", 60*time.Second),
+// Default returns the built-in configuration, used for every setting the environment does not override
+func Default() Config {
+	return Config{
+		MimirRuleRequeuePeriod: 60 * time.Second,
 		AlertingBurnRates: AlertingBurnRates{
-			PageShortWindow:   GetEnvAsFloat64("ABR_PAGE_SHORT_WINDOW", 14.4),
-			PageLongWindow:    GetEnvAsFloat64("ABR_PAGE_LONG_WINDOW", 6),
-			TicketShortWindow: GetEnvAsFloat64("ABR_TICKET_SHORT_WINDOW", 3),
-			TicketLongWindow:  GetEnvAsFloat64("ABR_TICKET_LONG_WINDOW", 1),
+			PageShortWindow:   14.4,
+			PageLongWindow:    6,
+			TicketShortWindow: 3,
+			TicketLongWindow:  1,
+		},
+		DefaultBaseWindow:        5 * time.Minute,
+		AlertingTool:             "opsgenie",
+		SeverityMaps:             defaultSeverityMaps(),
+		AlertKeepFiringFor:       0,
+		SLOStatusRefreshPeriod:   1 * time.Minute,
+		SLIValidationPeriod:      5 * time.Minute,
+		BudgetExhaustedThreshold: 0,
+		EnableWebhooks:           false,
+		DeploymentFreezeMode:     "disabled",
+		Ruler: RulerConfig{
+			WriteQPS:       2,
+			WriteBurst:     10,
+			RetryBaseDelay: 1 * time.Second,
+			RetryMaxDelay:  5 * time.Minute,
+			ResyncPeriod:   10 * time.Minute,
+		},
+		RuleGroups: RuleGroupConfig{
+			MediumWindow: RuleGroupDefaults{Interval: 2 * time.Minute},
+			LongWindow:   RuleGroupDefaults{Interval: 4 * time.Minute},
+		},
+		Export: ExportConfig{
+			ConfigMapName: "openslo-export",
+			Indicators:    "reference",
 		},
-		DefaultBaseWindow: GetEnvAsDuration("DEFAULT_BASE_WINDOW", 5*time.Minute),
-		AlertingTool:      alertingTool,
-		// AlertSeverities:   AlertSeveritiesByTool(alertingTool), // I wouldn't default to opsgenie here, maybe better to default to custom and error on startup if no custom variables or valid tool is selected
 	}
 }
+
+// FromEnv returns the default configuration overridden by the environment variables of the operator.
+// Values that do not parse are reported instead of being replaced by their default.
+func FromEnv() (Config, error) {
+	cfg := Default()
+	env := &envReader{}
+
+	env.duration("MIMIR_RULE_REQUEUE_PERIOD", &cfg.MimirRuleRequeuePeriod)
+	env.float("ABR_PAGE_SHORT_WINDOW", &cfg.AlertingBurnRates.PageShortWindow)
+	env.float("ABR_PAGE_LONG_WINDOW", &cfg.AlertingBurnRates.PageLongWindow)
+	env.float("ABR_TICKET_SHORT_WINDOW", &cfg.AlertingBurnRates.TicketShortWindow)
+	env.float("ABR_TICKET_LONG_WINDOW", &cfg.AlertingBurnRates.TicketLongWindow)
+	env.duration("DEFAULT_BASE_WINDOW", &cfg.DefaultBaseWindow)
+	env.string("OSKO_ALERTING_TOOL", &cfg.AlertingTool)
+	env.severity("OSKO_ALERTING_SEVERITY_CRITICAL", cfg.SeverityMaps["custom"], PageCritical)
+	env.severity("OSKO_ALERTING_SEVERITY_HIGH", cfg.SeverityMaps["custom"], PageHigh)
+	env.severity("OSKO_ALERTING_SEVERITY_MEDIUM", cfg.SeverityMaps["custom"], TicketHigh)
+	env.severity("OSKO_ALERTING_SEVERITY_LOW", cfg.SeverityMaps["custom"], TicketMedium)
+	env.duration("ALERT_KEEP_FIRING_FOR", &cfg.AlertKeepFiringFor)
+	env.duration("SLO_STATUS_REFRESH_PERIOD", &cfg.SLOStatusRefreshPeriod)
+	env.duration("SLI_VALIDATION_PERIOD", &cfg.SLIValidationPeriod)
+	env.float("BUDGET_EXHAUSTED_THRESHOLD", &cfg.BudgetExhaustedThreshold)
+	env.bool("ENABLE_WEBHOOKS", &cfg.EnableWebhooks)
+	env.string("DEPLOYMENT_FREEZE_MODE", &cfg.DeploymentFreezeMode)
+	env.bool("SLO_REPORT_CROSS_NAMESPACE", &cfg.SLOReportCrossNamespace)
+	env.float("RULER_WRITE_QPS", &cfg.Ruler.WriteQPS)
+	env.int("RULER_WRITE_BURST", &cfg.Ruler.WriteBurst)
+	env.duration("RULER_RETRY_BASE_DELAY", &cfg.Ruler.RetryBaseDelay)
+	env.duration("RULER_RETRY_MAX_DELAY", &cfg.Ruler.RetryMaxDelay)
+	env.duration("RULER_RESYNC_PERIOD", &cfg.Ruler.ResyncPeriod)
+	env.duration("RULE_GROUP_SHORT_INTERVAL", &cfg.RuleGroups.ShortWindow.Interval)
+	env.duration("RULE_GROUP_SHORT_EVALUATION_DELAY", &cfg.RuleGroups.ShortWindow.EvaluationDelay)
+	env.duration("RULE_GROUP_MEDIUM_INTERVAL", &cfg.RuleGroups.MediumWindow.Interval)
+	env.duration("RULE_GROUP_MEDIUM_EVALUATION_DELAY", &cfg.RuleGroups.MediumWindow.EvaluationDelay)
+	env.duration("RULE_GROUP_LONG_INTERVAL", &cfg.RuleGroups.LongWindow.Interval)
+	env.duration("RULE_GROUP_LONG_EVALUATION_DELAY", &cfg.RuleGroups.LongWindow.EvaluationDelay)
+	env.list("EXPORT_NAMESPACES", &cfg.Export.Namespaces)
+	env.string("EXPORT_CONFIGMAP_NAME", &cfg.Export.ConfigMapName)
+	env.string("EXPORT_INDICATORS", &cfg.Export.Indicators)
+
+	if err := errors.Join(env.errs...); err != nil {
+		return cfg, err
+	}
+	return cfg, Validate(cfg)
+}
+
+// Validate checks that every setting is in its valid range
+func Validate(cfg Config) error {
+	var errs []error
+	for _, d := range []struct {
+		name     string
+		value    time.Duration
+		positive bool
+	}{
+		{"mimirRuleRequeuePeriod", cfg.MimirRuleRequeuePeriod, true},
+		{"defaultBaseWindow", cfg.DefaultBaseWindow, true},
+		{"alertKeepFiringFor", cfg.AlertKeepFiringFor, false},
+		{"sloStatusRefreshPeriod", cfg.SLOStatusRefreshPeriod, false},
+		{"sliValidationPeriod", cfg.SLIValidationPeriod, false},
+		{"ruleGroups.shortWindow.interval", cfg.RuleGroups.ShortWindow.Interval, false},
+		{"ruleGroups.shortWindow.evaluationDelay", cfg.RuleGroups.ShortWindow.EvaluationDelay, false},
+		{"ruleGroups.mediumWindow.interval", cfg.RuleGroups.MediumWindow.Interval, false},
+		{"ruleGroups.mediumWindow.evaluationDelay", cfg.RuleGroups.MediumWindow.EvaluationDelay, false},
+		{"ruleGroups.longWindow.interval", cfg.RuleGroups.LongWindow.Interval, false},
+		{"ruleGroups.longWindow.evaluationDelay", cfg.RuleGroups.LongWindow.EvaluationDelay, false},
+	} {
+		switch {
+		case d.positive && d.value <= 0:
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", d.name, d.value))
+		case d.value < 0:
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", d.name, d.value))
+		}
+	}
+	for _, rate := range []struct {
+		name  string
+		value float64
+	}{
+		{"alertingBurnRates.pageShortWindow", cfg.AlertingBurnRates.PageShortWindow},
+		{"alertingBurnRates.pageLongWindow", cfg.AlertingBurnRates.PageLongWindow},
+		{"alertingBurnRates.ticketShortWindow", cfg.AlertingBurnRates.TicketShortWindow},
+		{"alertingBurnRates.ticketLongWindow", cfg.AlertingBurnRates.TicketLongWindow},
+	} {
+		if rate.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %v", rate.name, rate.value))
+		}
+	}
+	if cfg.BudgetExhaustedThreshold < 0 || cfg.BudgetExhaustedThreshold >= 1 {
+		errs = append(errs, fmt.Errorf("budgetExhaustedThreshold must be in [0, 1), got %v", cfg.BudgetExhaustedThreshold))
+	}
+	if cfg.AlertingTool == "" {
+		errs = append(errs, errors.New("alertingTool must not be empty"))
+	}
+	errs = append(errs, validateSeverityMaps(cfg.SeverityMaps)...)
+	if !slices.Contains(deploymentFreezeModes, cfg.DeploymentFreezeMode) {
+		errs = append(errs, fmt.Errorf("deploymentFreezeMode must be one of %v, got %q", deploymentFreezeModes, cfg.DeploymentFreezeMode))
+	}
+	if !slices.Contains(exportIndicators, cfg.Export.Indicators) {
+		errs = append(errs, fmt.Errorf("export.indicators must be one of %v, got %q", exportIndicators, cfg.Export.Indicators))
+	}
+	if len(cfg.Export.Namespaces) > 0 && cfg.Export.ConfigMapName == "" {
+		errs = append(errs, errors.New("export.configMapName must not be empty when namespaces are exported"))
+	}
+	if cfg.Ruler.WriteQPS <= 0 || cfg.Ruler.WriteBurst <= 0 {
+		errs = append(errs, fmt.Errorf("ruler writeQPS and writeBurst must be positive, got %v and %d", cfg.Ruler.WriteQPS, cfg.Ruler.WriteBurst))
+	}
+	return errors.Join(errs...)
+}
+
+// reservedAlertLabels and reservedAlertAnnotations are set on every generated alert, severity maps cannot override them
+var (
+	reservedAlertLabels      = []string{"severity", "slo_name", "sli_name", "short_window", "long_window"}
+	reservedAlertAnnotations = []string{"summary", "description"}
+)
+
+// validateSeverityMaps checks that every severity map labels all SRE severities and only adds valid labels
+func validateSeverityMaps(severityMaps map[string]AlertToolSeverityMap) []error {
+	var errs []error
+	if _, exists := severityMaps["custom"]; !exists {
+		errs = append(errs, errors.New("severityMaps must contain the custom map, it is used for unknown alerting tools"))
+	}
+	for _, tool := range slices.Sorted(maps.Keys(severityMaps)) {
+		for _, sreSeverity := range SREAlertSeverities {
+			name := fmt.Sprintf("severityMaps.%s.%s", tool, sreSeverity)
+			severity, exists := severityMaps[tool][sreSeverity]
+			if !exists || severity.Severity == "" {
+				errs = append(errs, fmt.Errorf("%s.severity must not be empty", name))
+				continue
+			}
+			for _, label := range slices.Sorted(maps.Keys(severity.Labels)) {
+				if !model.LabelName(label).IsValid() || slices.Contains(reservedAlertLabels, label) {
+					errs = append(errs, fmt.Errorf("%s.labels: %q is not a valid label name or is set by the operator", name, label))
+				}
+			}
+			for _, annotation := range slices.Sorted(maps.Keys(severity.Annotations)) {
+				if !model.LabelName(annotation).IsValid() || slices.Contains(reservedAlertAnnotations, annotation) {
+					errs = append(errs, fmt.Errorf("%s.annotations: %q is not a valid annotation name or is set by the operator", name, annotation))
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("MIMIR_RULE_REQUEUE_PERIOD", "2m")
+	t.Setenv("ABR_PAGE_SHORT_WINDOW", "10")
+	t.Setenv("ENABLE_WEBHOOKS", "true")
+	t.Setenv("EXPORT_NAMESPACES", "shop, payments,")
+
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv() error = %v", err)
+	}
+	if cfg.MimirRuleRequeuePeriod != 2*time.Minute {
+		t.Errorf("MimirRuleRequeuePeriod = %s, want 2m", cfg.MimirRuleRequeuePeriod)
+	}
+	if cfg.AlertingBurnRates.PageShortWindow != 10 {
+		t.Errorf("PageShortWindow = %v, want 10", cfg.AlertingBurnRates.PageShortWindow)
+	}
+	if !cfg.EnableWebhooks {
+		t.Error("EnableWebhooks = false, want true")
+	}
+	if !reflect.DeepEqual(cfg.Export.Namespaces, []string{"shop", "payments"}) || !cfg.Export.Exports("shop") || cfg.Export.Exports("default") {
+		t.Errorf("Export.Namespaces = %v, want [shop payments]", cfg.Export.Namespaces)
+	}
+	if cfg.DefaultBaseWindow != Default().DefaultBaseWindow {
+		t.Errorf("DefaultBaseWindow = %s, want the default", cfg.DefaultBaseWindow)
+	}
+}
+
+func TestFromEnvRejectsInvalidValues(t *testing.T) {
+	tests := map[string]struct {
+		key, value string
+		want       string
+	}{
+		"unparsable duration": {"DEFAULT_BASE_WINDOW", "5 minutes", "DEFAULT_BASE_WINDOW"},
+		"unparsable number":   {"ABR_TICKET_LONG_WINDOW", "one", "ABR_TICKET_LONG_WINDOW"},
+		"unparsable bool":     {"ENABLE_WEBHOOKS", "yes please", "ENABLE_WEBHOOKS"},
+		"out of range":        {"BUDGET_EXHAUSTED_THRESHOLD", "1.5", "budgetExhaustedThreshold"},
+		"unknown freeze mode": {"DEPLOYMENT_FREEZE_MODE", "strict", "deploymentFreezeMode"},
+		"negative duration":   {"ALERT_KEEP_FIRING_FOR", "-1m", "alertKeepFiringFor"},
+		"unknown indicators":  {"EXPORT_INDICATORS", "embedded", "export.indicators"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			_, err := FromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromEnv() error = %v, want an error about %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	var nilStore *Store
+	if !reflect.DeepEqual(nilStore.Get(), Default()) {
+		t.Error("Get() of a nil Store should return the defaults")
+	}
+
+	store := NewStore(Default())
+	cfg := Default()
+	cfg.AlertingTool = "pagerduty"
+	store.Set(cfg)
+	if got := store.Get().AlertingTool; got != "pagerduty" {
+		t.Errorf("Get().AlertingTool = %q, want pagerduty", got)
+	}
+
+	changes := store.Subscribe()
+	store.Set(cfg)
+	if len(changes) != 0 {
+		t.Error("Set() with the configuration in effect should not notify subscribers")
+	}
+	cfg.AlertingTool = "opsgenie"
+	store.Set(cfg)
+	cfg.DefaultBaseWindow *= 2
+	store.Set(cfg)
+	if len(changes) != 1 {
+		t.Errorf("Set() should coalesce changes into one pending notification, got %d", len(changes))
+	}
+}
+
+func TestFromEnvCustomSeverities(t *testing.T) {
+	t.Setenv("OSKO_ALERTING_SEVERITY_HIGH", "sev2")
+	t.Setenv("OSKO_ALERTING_SEVERITY_MEDIUM", "sev3")
+
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv() error = %v", err)
+	}
+	want := map[SREAlertSeverity]string{PageCritical: "critical", PageHigh: "sev2", TicketHigh: "sev3", TicketMedium: "low"}
+	for sreSeverity, severity := range want {
+		if got := cfg.SeveritiesFor("custom").GetSeverity(sreSeverity).Severity; got != severity {
+			t.Errorf("custom %s = %q, want %q", sreSeverity, got, severity)
+		}
+	}
+	if got := Default().SeveritiesFor("custom").GetSeverity(PageHigh).Severity; got != "high" {
+		t.Errorf("FromEnv() modified the default custom map, page_high = %q", got)
+	}
+}
+
+func TestValidateSeverityMaps(t *testing.T) {
+	tests := map[string]struct {
+		severities AlertSeverity
+		want       string
+	}{
+		"empty severity":      {AlertSeverity{}, "severityMaps.incidentio.page_critical.severity"},
+		"invalid label":       {AlertSeverity{Severity: "critical", Labels: map[string]string{"team-channel": "x"}}, `"team-channel"`},
+		"reserved label":      {AlertSeverity{Severity: "critical", Labels: map[string]string{"slo_name": "x"}}, `"slo_name"`},
+		"reserved annotation": {AlertSeverity{Severity: "critical", Annotations: map[string]string{"summary": "x"}}, `"summary"`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := Default()
+			cfg.SeverityMaps["incidentio"] = severityLabels("critical", "major", "minor", "minor")
+			cfg.SeverityMaps["incidentio"][PageCritical] = tt.severities
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want an error about %s", err, tt.want)
+			}
+		})
+	}
+
+	cfg := Default()
+	delete(cfg.SeverityMaps, "custom")
+	if err := Validate(cfg); err == nil {
+		t.Error("Validate() should require the custom severity map")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds the configuration in effect and lets it be replaced while the operator is running
+type Store struct {
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []chan struct{}
+}
+
+// NewStore returns a Store holding cfg
+func NewStore(cfg Config) *Store {
+	s := &Store{}
+	s.Set(cfg)
+	return s
+}
+
+// Get returns the configuration in effect, the defaults if the Store is nil or empty
+func (s *Store) Get() Config {
+	if s == nil {
+		return Default()
+	}
+	if cfg := s.current.Load(); cfg != nil {
+		return *cfg
+	}
+	return Default()
+}
+
+// Set replaces the configuration in effect and notifies the subscribers when it changed
+func (s *Store) Set(cfg Config) {
+	previous := s.current.Swap(&cfg)
+	if previous == nil || reflect.DeepEqual(*previous, cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A notification is already pending, the subscriber reads the latest configuration anyway
+		}
+	}
+}
+
+// Subscribe returns a channel that receives a value whenever Set changes the configuration.
+// Changes made while a notification is still pending are coalesced into it.
+func (s *Store) Subscribe() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, ch)
+	return ch
+}
@@ -9,7 +9,75 @@ type Config struct {
 	AlertingBurnRates      AlertingBurnRates
 	DefaultBaseWindow      time.Duration
 	AlertingTool           string
-	AlertSeverities        AlertSeverities
+	// SeverityMaps are the severity maps of the alerting tools by name, selected with AlertingTool or the
+	// osko.dev/alertingTool annotation of an SLO
+	SeverityMaps           map[string]AlertToolSeverityMap
+	AlertKeepFiringFor     time.Duration
+	SLOStatusRefreshPeriod time.Duration
+	SLIValidationPeriod    time.Duration
+	// BudgetExhaustedThreshold is the share of the error budget left at or below which an SLO counts as exhausted
+	BudgetExhaustedThreshold float64
+	// EnableWebhooks serves the validating and defaulting webhooks of the OpenSLO resources
+	EnableWebhooks bool
+	// DeploymentFreezeMode enables the deployment freeze webhook, "disabled", "warn" or "enforce"
+	DeploymentFreezeMode string
+	// SLOReportCrossNamespace lets SLOReports select SLOs of namespaces other than their own
+	SLOReportCrossNamespace bool
+	Ruler                   RulerConfig
+	RuleGroups              RuleGroupConfig
+	Export                  ExportConfig
+}
+
+// ExportConfig controls the export of the OpenSLO objects of namespaces into OpenSLO bundles in ConfigMaps
+type ExportConfig struct {
+	// Namespaces are the namespaces to export, "*" exports all namespaces and none disables the export
+	Namespaces []string
+	// ConfigMapName is the name of the ConfigMap the bundle is written to in every exported namespace
+	ConfigMapName string
+	// Indicators is how the indicators of the SLOs are exported, "reference" or "inline"
+	Indicators string
+}
+
+// Exports reports whether the objects of the namespace are exported
+func (c ExportConfig) Exports(namespace string) bool {
+	for _, ns := range c.Namespaces {
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// RulerConfig controls how rule group writes are sent to the ruler API
+type RulerConfig struct {
+	// WriteQPS is the sustained number of ruler API writes per second allowed for a single datasource
+	WriteQPS float64
+	// WriteBurst is the number of ruler API writes that can be sent at once before WriteQPS applies
+	WriteBurst int
+	// RetryBaseDelay is the first backoff delay after a failed write, doubled on every consecutive failure
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay caps the backoff delay between retries of a failed write
+	RetryMaxDelay time.Duration
+	// ResyncPeriod is how long an unchanged rule group is trusted before it is written again
+	ResyncPeriod time.Duration
+}
+
+// RuleGroupConfig holds the default evaluation settings of generated rule groups per window class
+type RuleGroupConfig struct {
+	// ShortWindow applies to rule groups of windows up to 1h and to alerting rules
+	ShortWindow RuleGroupDefaults
+	// MediumWindow applies to rule groups of windows up to 24h
+	MediumWindow RuleGroupDefaults
+	// LongWindow applies to rule groups of windows longer than 24h
+	LongWindow RuleGroupDefaults
+}
+
+// RuleGroupDefaults are the evaluation settings of a rule group, zero values leave them to the ruler
+type RuleGroupDefaults struct {
+	// Interval is how often the rule group is evaluated, keep it below the query lookback delta (5m by default)
+	Interval time.Duration
+	// EvaluationDelay shifts evaluations back in time to account for late samples, only supported by Mimir
+	EvaluationDelay time.Duration
 }
 
 type AlertingBurnRates struct {
@@ -19,11 +87,6 @@ type AlertingBurnRates struct {
 	TicketLongWindow  float64
 }
 
-type AlertToolConfig struct {
-	Tool       string
-	Severities map[string]string
-}
-
 type SREAlertSeverity string
 
 const (
@@ -33,17 +96,20 @@ const (
 	TicketMedium SREAlertSeverity = "ticket_medium"
 )
 
-type AlertToolSeverityMap map[SREAlertSeverity]string
+// SREAlertSeverities are the severities of the generated alerts, from the most to the least urgent
+var SREAlertSeverities = []SREAlertSeverity{PageCritical, PageHigh, TicketHigh, TicketMedium}
+
+// AlertToolSeverityMap is how an alerting tool labels the alerts of every SRE severity
+type AlertToolSeverityMap map[SREAlertSeverity]AlertSeverity
 
-type AlertSeverities struct {
-	Critical string
-	HighFast string
-	HighSlow string
-	Low      string
-	Tool     string
+// AlertSeverity is the severity label of the alerts of one SRE severity, and the extra labels and annotations they get
+type AlertSeverity struct {
+	Severity    string
+	Labels      map[string]string
+	Annotations map[string]string
 }
 
-func (m AlertToolSeverityMap) GetSeverity(sreSeverity SREAlertSeverity) string {
+func (m AlertToolSeverityMap) GetSeverity(sreSeverity SREAlertSeverity) AlertSeverity {
 	if sev, ok := m[sreSeverity]; ok {
 		return sev
 	}
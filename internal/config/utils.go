@@ -1,74 +1,98 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// GetEnv Helper function to read an environment variable or return a default value
-func GetEnv(key, defaultValue string) string {
+// envReader reads typed environment variables and collects the values that do not parse
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) string(key string, target *string) {
 	if value, exists := os.LookupEnv(key); exists {
-		return value
+		*target = value
 	}
-	return defaultValue
 }
 
-// GetEnvAsInt Helper function to read an environment variable as an integer or return a default value
-func GetEnvAsInt(key string, defaultValue int) int {
-	if valueStr, exists := os.LookupEnv(key); exists {
-		if value, err := strconv.Atoi(valueStr); err == nil {
-			return value
+// list reads a comma-separated list, skipping empty items
+func (r *envReader) list(key string, target *[]string) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
-	return defaultValue
+	*target = items
 }
 
-// GetEnvAsFloat64 Helper function to read an environment variable as a float64 or return a default value
-func GetEnvAsFloat64(key string, defaultValue float64) float64 {
-	if valueStr, exists := os.LookupEnv(key); exists {
-		if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
-			return value
-		}
+// severity sets the severity label of one SRE severity in the severity map if the environment variable is set
+func (r *envReader) severity(key string, severities AlertToolSeverityMap, sreSeverity SREAlertSeverity) {
+	if value, exists := os.LookupEnv(key); exists {
+		severities[sreSeverity] = AlertSeverity{Severity: value}
 	}
-	return defaultValue
 }
 
-// GetEnvAsDuration Helper function to read an environment variable as a time.Duration or return a default value
-func GetEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	if valueStr, exists := os.LookupEnv(key); exists {
-		if value, err := time.ParseDuration(valueStr); err == nil {
-			return value
-		}
+func (r *envReader) bool(key string, target *bool) {
+	parseEnv(r, key, target, strconv.ParseBool)
+}
+
+func (r *envReader) int(key string, target *int) {
+	parseEnv(r, key, target, strconv.Atoi)
+}
+
+func (r *envReader) float(key string, target *float64) {
+	parseEnv(r, key, target, func(value string) (float64, error) { return strconv.ParseFloat(value, 64) })
+}
+
+func (r *envReader) duration(key string, target *time.Duration) {
+	parseEnv(r, key, target, time.ParseDuration)
+}
+
+// parseEnv sets target to the parsed value of the environment variable if it is set and valid
+func parseEnv[T any](r *envReader, key string, target *T, parse func(string) (T, error)) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return
 	}
-	return defaultValue
+	parsed, err := parse(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid value %q of %s: %w", value, key, err))
+		return
+	}
+	*target = parsed
 }
 
-func AlertSeveritiesByTool(tool string) AlertToolSeverityMap {
-	severityMaps := map[string]AlertToolSeverityMap{
-		"opsgenie": {
-			PageCritical: "P1",
-			PageHigh:     "P2",
-			TicketHigh:   "P3",
-			TicketMedium: "P4",
-		},
-		"pagerduty": {
-			PageCritical: "SEV_1",
-			PageHigh:     "SEV_2",
-			TicketHigh:   "SEV_3",
-			TicketMedium: "SEV_4",
-		},
-		"custom": {
-			PageCritical: GetEnv("OSKO_ALERTING_SEVERITY_CRITICAL", "critical"),
-			PageHigh:     GetEnv("OSKO_ALERTING_SEVERITY_HIGH", "high"),
-			TicketHigh:   GetEnv("OSKO_ALERTING_SEVERITY_HIGH", "medium"),
-			TicketMedium: GetEnv("OSKO_ALERTING_SEVERITY_LOW", "low"),
-		},
+// defaultSeverityMaps returns the built-in severity maps of the alerting tools
+func defaultSeverityMaps() map[string]AlertToolSeverityMap {
+	return map[string]AlertToolSeverityMap{
+		"opsgenie":  severityLabels("P1", "P2", "P3", "P4"),
+		"pagerduty": severityLabels("SEV_1", "SEV_2", "SEV_3", "SEV_4"),
+		"custom":    severityLabels("critical", "high", "medium", "low"),
 	}
+}
 
-	if toolMap, exists := severityMaps[tool]; exists {
-		return toolMap
+// severityLabels returns a severity map setting only the severity label, from the most to the least urgent
+func severityLabels(severities ...string) AlertToolSeverityMap {
+	m := AlertToolSeverityMap{}
+	for i, sreSeverity := range SREAlertSeverities {
+		m[sreSeverity] = AlertSeverity{Severity: severities[i]}
 	}
+	return m
+}
 
-	return severityMaps["custom"]
+// SeveritiesFor returns the severity map of an alerting tool, the custom map for tools without one
+func (c Config) SeveritiesFor(tool string) AlertToolSeverityMap {
+	if severities, exists := c.SeverityMaps[tool]; exists {
+		return severities
+	}
+	return c.SeverityMaps["custom"]
 }
@@ -2,8 +2,18 @@ package monitoringcoreoscom
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"time"
+
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
 	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/metrics"
+	"github.com/oskoperator/osko/internal/reconciler"
 	"github.com/oskoperator/osko/internal/utils"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -12,11 +22,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
-	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -29,15 +39,19 @@ type PrometheusRuleReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// Config holds the operator settings the rules are generated with
+	Config *config.Store
 }
 
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx)
+	cfg := r.Config.Get()
 
 	slo := &openslov1.SLO{}
 	sli := &openslov1.SLI{}
@@ -83,16 +97,15 @@ func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	if slo.Spec.IndicatorRef != nil {
 		err = r.Get(ctx, client.ObjectKey{Name: *slo.Spec.IndicatorRef, Namespace: slo.Namespace}, sli)
 		if err != nil {
-			apierrors.IsNotFound(err)
-			{
-				log.Error(err, errGetSLI)
-				err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "SLI Object not found")
-				if err != nil {
-					log.Error(err, "Failed to update SLO status")
-					return ctrl.Result{}, err
-				}
-				return ctrl.Result{}, err
+			log.Error(err, errGetSLI)
+			if !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonSLINotFound, "SLI Object not found")...); statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+			}
+			return ctrl.Result{}, errors.DependencyNotReady(err)
 		}
 	} else if slo.Spec.Indicator != nil {
 		log.V(1).Info("SLO has an inline SLI")
@@ -102,70 +115,53 @@ func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			sli.Spec.RatioMetric = slo.Spec.Indicator.Spec.RatioMetric
 		}
 	} else {
-		err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "SLI Object not found")
-		if err != nil {
-			log.Error(err, "Failed to update SLO status")
-			r.Recorder.Event(slo, "Warning", "SLIObjectNotFound", "SLI Object not found")
+		if slo.Name == "" {
+			log.V(1).Info("Not generating rules for a PrometheusRule without an SLO")
 			return ctrl.Result{}, nil
 		}
-		log.Error(err, "SLO has no SLI reference")
-		return ctrl.Result{}, err
+		r.Recorder.Event(slo, "Warning", "SLIObjectNotFound", "SLI Object not found")
+		if statusErr := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonSLINotFound, "SLO has neither an indicator nor an indicatorRef")...); statusErr != nil {
+			log.Error(statusErr, "Failed to update SLO status")
+			return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+		}
+		log.Info("SLO has no SLI reference")
+		return ctrl.Result{}, errors.Permanent(fmt.Errorf("SLO has no SLI reference"))
+	}
+
+	// Settings the SLO does not set itself come from the SLODefaults of its namespace
+	effective, defaultsErr := helpers.EffectiveSLO(ctx, r.Client, slo)
+	if defaultsErr != nil {
+		log.Error(defaultsErr, "Failed to get SLODefaults")
+		return ctrl.Result{}, defaultsErr
 	}
 
 	if apierrors.IsNotFound(err) {
 		log.V(1).Info("PrometheusRule not found. Let's make one.")
-		prometheusRule, err = helpers.CreatePrometheusRule(slo, sli)
+		prometheusRule, err = helpers.CreatePrometheusRule(effective, sli, cfg)
 		if err != nil {
-			err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create Prometheus Rule")
-			if err != nil {
-				log.Error(err, "Failed to update SLO status")
-				return ctrl.Result{}, err
-			}
 			log.Error(err, "Failed to create new PrometheusRule")
-			return ctrl.Result{}, err
+			message := fmt.Sprintf("Failed to create new Prometheus Rule: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesGenerated, utils.ReasonRuleGenerationFailed, message)...); statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+			}
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
 		if err := r.Create(ctx, prometheusRule); err != nil {
-			r.Recorder.Event(slo, "Error", "FailedToCreatePrometheusRule", "Failed to create Prometheus Rule")
-			if statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-				if err := r.Get(ctx, req.NamespacedName, prometheusRule); err != nil {
-					return err
-				}
-				return r.Status().Update(ctx, prometheusRule)
-			}); statusErr != nil {
+			r.Recorder.Event(slo, "Warning", "FailedToCreatePrometheusRule", "Failed to create Prometheus Rule")
+			message := fmt.Sprintf("Failed to create PrometheusRule: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesGenerated, utils.ReasonRuleGenerationFailed, message)...); statusErr != nil {
 				log.Error(statusErr, "Failed to update SLO status")
-				slo.Status.Ready = "Failed"
-				if statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-					sloNamespacedName := types.NamespacedName{
-						Name:      slo.Name,
-						Namespace: slo.Namespace,
-					}
-					if err := r.Get(ctx, sloNamespacedName, slo); err != nil {
-						return err
-					}
-					return r.Status().Update(ctx, slo)
-				}); statusErr != nil {
-					log.Error(statusErr, "Failed to update SLO ready status")
-					return ctrl.Result{}, statusErr
-				}
-				return ctrl.Result{}, statusErr
-			}
-		} else {
-			log.V(1).Info("PrometheusRule created successfully")
-			r.Recorder.Event(slo, "Normal", "PrometheusRuleCreated", "PrometheusRule created successfully")
-			if statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-				sloNamespacedName := types.NamespacedName{
-					Name:      slo.Name,
-					Namespace: slo.Namespace,
-				}
-				if err := r.Get(ctx, sloNamespacedName, slo); err != nil {
-					return err
-				}
-				slo.Status.Ready = "True"
-				return r.Status().Update(ctx, slo)
-			}); statusErr != nil {
-				log.Error(statusErr, "Failed to update SLO ready status")
-				return ctrl.Result{}, nil
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 			}
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		log.V(1).Info("PrometheusRule created successfully")
+		r.Recorder.Event(slo, "Normal", "PrometheusRuleCreated", "PrometheusRule created successfully")
+		condition := utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionTrue, utils.ReasonRulesGenerated, "PrometheusRule created")
+		if err := utils.UpdateConditions(ctx, slo, r.Client, condition); err != nil {
+			log.Error(err, "Failed to update SLO status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
 	}
 
@@ -173,12 +169,32 @@ func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// This is the main logic for the PrometheusRule update
 	// Here we should take the existing PrometheusRule and update it with the new one
 	log.V(1).Info("PrometheusRule already exists, we should update it")
-	newPrometheusRule, err = helpers.CreatePrometheusRule(slo, sli)
+	newPrometheusRule, err = helpers.CreatePrometheusRule(effective, sli, cfg)
 	if err != nil {
 		log.Error(err, "Failed to create new PrometheusRule")
+		if stderrors.Is(err, errors.ErrInvalidRule) {
+			r.Recorder.Event(slo, "Warning", "InvalidRule", err.Error())
+			message := fmt.Sprintf("Generated rules are invalid: %v", err)
+			statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesGenerated, utils.ReasonInvalidRule, message)...)
+			if statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, statusErr
+			}
+			// Keep the last valid PrometheusRule, regenerating the same rules will not make them valid
+			return ctrl.Result{}, errors.Permanent(err)
+		}
 		return ctrl.Result{}, err
 	}
 
+	if slo.Name != "" {
+		metrics.SetGeneratedRules(slo.Namespace, slo.Name, newPrometheusRule)
+		condition := utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionTrue, utils.ReasonRulesGenerated, "PrometheusRule generated")
+		if err := utils.UpdateConditions(ctx, slo, r.Client, condition); err != nil {
+			log.Error(err, "Failed to update SLO status")
+			return ctrl.Result{}, err
+		}
+	}
+
 	compareResult := reflect.DeepEqual(prometheusRule, newPrometheusRule)
 	if compareResult {
 		log.V(1).Info("PrometheusRule is already up to date")
@@ -194,37 +210,45 @@ func (r *PrometheusRuleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return r.Update(ctx, newPrometheusRule)
 	}); err != nil {
 		log.Error(err, "Failed to update PrometheusRule")
-		return ctrl.Result{}, err
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
-	if statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		sloNamespacedName := types.NamespacedName{
-			Name:      slo.Name,
-			Namespace: slo.Namespace,
-		}
-		if err := r.Get(ctx, sloNamespacedName, slo); err != nil {
-			return err
+
+	return ctrl.Result{}, nil
+}
+
+// notReady returns the given condition set to False together with a Ready condition with the same reason
+func notReady(conditionType, reason, message string) []metav1.Condition {
+	return []metav1.Condition{
+		utils.NewCondition(conditionType, metav1.ConditionFalse, reason, message),
+		utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, reason, message),
+	}
+}
+
+// dependencyNotResolved returns the conditions of an SLO waiting for one of the objects it references
+func dependencyNotResolved(reason, message string) []metav1.Condition {
+	return notReady(utils.ConditionDependenciesResolved, reason, message)
+}
+
+// findRulesForDefaults enqueues the PrometheusRule of every SLO in the namespace of an SLODefaults, the rules are
+// named after their SLO
+func (r *PrometheusRuleReconciler) findRulesForDefaults() func(ctx context.Context, a client.Object) []reconcile.Request {
+	return func(ctx context.Context, a client.Object) []reconcile.Request {
+		slos := &openslov1.SLOList{}
+		if err := r.Client.List(ctx, slos, client.InNamespace(a.GetNamespace())); err != nil {
+			return []reconcile.Request{}
 		}
-		return r.Status().Update(ctx, slo)
-	}); statusErr != nil {
-		log.Error(statusErr, "Failed to update SLO status")
-		slo.Status.Ready = "Failed"
-		if statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			sloNamespacedName := types.NamespacedName{
-				Name:      slo.Name,
-				Namespace: slo.Namespace,
-			}
-			if err := r.Get(ctx, sloNamespacedName, slo); err != nil {
-				return err
+
+		requests := make([]reconcile.Request, len(slos.Items))
+		for i, item := range slos.Items {
+			requests[i] = reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.Name,
+					Namespace: item.Namespace,
+				},
 			}
-			return r.Status().Update(ctx, slo)
-		}); statusErr != nil {
-			log.Error(statusErr, "Failed to update SLO ready status")
-			return ctrl.Result{}, statusErr
 		}
-		return ctrl.Result{}, statusErr
+		return requests
 	}
-
-	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -235,5 +259,11 @@ func (r *PrometheusRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&openslov1.SLO{},
 			&handler.EnqueueRequestForObject{},
 		).
-		Complete(r)
+		Watches(
+			&oskov1alpha1.SLODefaults{},
+			handler.EnqueueRequestsFromMapFunc(r.findRulesForDefaults()),
+		).
+		// Existing rules are regenerated with the new burn rates, windows and rule group settings
+		WatchesRawSource(reconciler.ConfigChanges(r.Config, reconciler.EnqueueAll(mgr.GetClient(), &monitoringv1.PrometheusRuleList{}))).
+		Complete(reconciler.Wrap(mgr, "prometheusrule", &monitoringv1.PrometheusRule{}, r))
 }
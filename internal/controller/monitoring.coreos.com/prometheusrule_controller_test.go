@@ -0,0 +1,85 @@
+package monitoringcoreoscom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPrometheusRuleReconcilerRegeneratesRulesOnConfigChange(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	require.NoError(t, monitoringv1.AddToScheme(scheme))
+
+	indicatorRef := "checkout-sli"
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "checkout",
+			Namespace:   "default",
+			UID:         "slo-uid",
+			Annotations: map[string]string{"osko.dev/magicAlerting": "true"},
+		},
+		Spec: openslov1.SLOSpec{
+			Service:      "shop",
+			IndicatorRef: &indicatorRef,
+			TimeWindow:   []openslov1.TimeWindowSpec{{Duration: "28d", IsRolling: true}},
+			Objectives:   []openslov1.ObjectivesSpec{{Target: "0.99"}},
+		},
+	}
+	sli := &openslov1.SLI{
+		ObjectMeta: metav1.ObjectMeta{Name: indicatorRef, Namespace: "default"},
+		Spec: openslov1.SLISpec{
+			RatioMetric: openslov1.RatioMetricSpec{
+				Counter: true,
+				Total:   openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Type: "prometheus", Spec: openslov1.MetricSourceSpec{Query: "http_requests_total"}}},
+				Good:    openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Type: "prometheus", Spec: openslov1.MetricSourceSpec{Query: "http_requests_success_total"}}},
+			},
+		},
+	}
+	store := config.NewStore(config.Default())
+	existing, err := helpers.CreatePrometheusRule(slo, sli, store.Get())
+	require.NoError(t, err)
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(slo, sli, existing).WithStatusSubresource(&openslov1.SLO{}).Build()
+	r := &PrometheusRuleReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), Config: store}
+
+	cfg := config.Default()
+	cfg.AlertKeepFiringFor = 10 * time.Minute
+	changes := store.Subscribe()
+	store.Set(cfg)
+	require.Len(t, changes, 1, "a config change notifies the controllers")
+
+	key := types.NamespacedName{Name: slo.Name, Namespace: slo.Namespace}
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	updated := &monitoringv1.PrometheusRule{}
+	require.NoError(t, c.Get(context.Background(), key, updated))
+	alerts := 0
+	for _, group := range updated.Spec.Groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == "" {
+				continue
+			}
+			alerts++
+			require.NotNil(t, rule.KeepFiringFor, "alert %s", rule.Alert)
+			assert.Equal(t, monitoringv1.NonEmptyDuration("10m"), *rule.KeepFiringFor, "alert %s", rule.Alert)
+		}
+	}
+	assert.NotZero(t, alerts)
+}
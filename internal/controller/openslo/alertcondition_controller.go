@@ -2,13 +2,18 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
 )
 
 // AlertConditionReconciler reconciles a AlertCondition object
@@ -22,8 +27,21 @@ type AlertConditionReconciler struct {
 //+kubebuilder:rbac:groups=openslo.com,resources=alertconditions/finalizers,verbs=update
 
 func (r *AlertConditionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
-
+	log := log.FromContext(ctx)
+
+	alertCondition := &openslov1.AlertCondition{}
+	if err := r.Get(ctx, req.NamespacedName, alertCondition); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("AlertCondition resource not found. Object must have been deleted.")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	if err := utils.UpdateConditions(ctx, alertCondition, r.Client, utils.ReadyConditions(utils.ReasonAccepted, "AlertCondition accepted")...); err != nil {
+		log.Error(err, "Failed to update AlertCondition status")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -31,5 +49,5 @@ func (r *AlertConditionReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 func (r *AlertConditionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&openslov1.AlertCondition{}).
-		Complete(r)
+		Complete(reconciler.Wrap(mgr, "alertcondition", &openslov1.AlertCondition{}, r))
 }
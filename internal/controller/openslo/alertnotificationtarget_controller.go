@@ -2,13 +2,18 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
 )
 
 // AlertNotificationTargetReconciler reconciles a AlertNotificationTarget object
@@ -24,8 +29,21 @@ type AlertNotificationTargetReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.15.0/pkg/reconcile
 func (r *AlertNotificationTargetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
-
+	log := log.FromContext(ctx)
+
+	alertNotificationTarget := &openslov1.AlertNotificationTarget{}
+	if err := r.Get(ctx, req.NamespacedName, alertNotificationTarget); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("AlertNotificationTarget resource not found. Object must have been deleted.")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	if err := utils.UpdateConditions(ctx, alertNotificationTarget, r.Client, utils.ReadyConditions(utils.ReasonAccepted, "AlertNotificationTarget accepted")...); err != nil {
+		log.Error(err, "Failed to update AlertNotificationTarget status")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -33,5 +51,5 @@ func (r *AlertNotificationTargetReconciler) Reconcile(ctx context.Context, req c
 func (r *AlertNotificationTargetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&openslov1.AlertNotificationTarget{}).
-		Complete(r)
+		Complete(reconciler.Wrap(mgr, "alertnotificationtarget", &openslov1.AlertNotificationTarget{}, r))
 }
@@ -2,13 +2,18 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
 )
 
 // AlertPolicyReconciler reconciles a AlertPolicy object
@@ -22,8 +27,21 @@ type AlertPolicyReconciler struct {
 //+kubebuilder:rbac:groups=openslo.com,resources=alertpolicies/finalizers,verbs=update
 
 func (r *AlertPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
-
+	log := log.FromContext(ctx)
+
+	alertPolicy := &openslov1.AlertPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, alertPolicy); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("AlertPolicy resource not found. Object must have been deleted.")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	if err := utils.UpdateConditions(ctx, alertPolicy, r.Client, utils.ReadyConditions(utils.ReasonAccepted, "AlertPolicy accepted")...); err != nil {
+		log.Error(err, "Failed to update AlertPolicy status")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -31,5 +49,5 @@ func (r *AlertPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *AlertPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&openslov1.AlertPolicy{}).
-		Complete(r)
+		Complete(reconciler.Wrap(mgr, "alertpolicy", &openslov1.AlertPolicy{}, r))
 }
@@ -2,26 +2,41 @@ package controller
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
-	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
 	"github.com/oskoperator/osko/internal/errors"
-	"github.com/prometheus/client_golang/api"
-	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/ruler"
+	"github.com/oskoperator/osko/internal/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
 	errGetDS     = "could not get Datasource"
 	errConnectDS = "could not connect to Datasource"
 	errQueryAPI  = "could not query API"
+
+	rulerQueueStatusPeriod = 15 * time.Second
+	// datasourceProbePeriod is how often the connection and the ruler configuration of a Datasource are checked
+	// again when its spec does not change
+	datasourceProbePeriod  = 5 * time.Minute
+	datasourceProbeTimeout = 30 * time.Second
 )
 
 // DatasourceReconciler reconciles a Datasource object
@@ -29,11 +44,7 @@ type DatasourceReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
-}
-
-type CustomRoundTripper struct {
-	Transport http.RoundTripper
-	TenantID  string
+	Ruler    *ruler.Dispatcher
 }
 
 //+kubebuilder:rbac:groups=openslo.com,resources=datasources,verbs=get;list;watch;create;update;patch;delete
@@ -56,66 +67,200 @@ func (r *DatasourceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		log.Error(err, errGetDS)
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
+	original := ds.Status.DeepCopy()
+
+	// The ruler queue triggers a reconcile on every change of its state, only reach out to the Datasource again
+	// once the last probe is due
+	probe := probeDue(&ds.Status, ds.Generation)
+	result := ctrl.Result{}
 	switch ds.Spec.Type {
 	case "mimir":
-		log.Info("Datasource Type is Mimir", "address", ds.Spec.ConnectionDetails.Address)
-		err = r.connectDatasource(ctx, ds)
-		if err != nil {
-			log.Error(err, errConnectDS)
-			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		probeCtx, cancel := context.WithTimeout(ctx, datasourceProbeTimeout)
+		defer cancel()
+		if probe {
+			log.V(1).Info("Probing Mimir Datasource", "address", ds.Spec.ConnectionDetails.Address)
+			if err := r.connectDatasource(probeCtx, ds); err != nil {
+				log.Error(err, errConnectDS)
+				condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonConnectionFailed, err.Error())
+				if statusErr := utils.UpdateConditions(ctx, ds, r.Client, condition); statusErr != nil {
+					log.Error(statusErr, "Failed to update Datasource status")
+				}
+				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			}
+			ds.Status.LastProbeTime = metav1.Now()
 		}
+		r.setFederationCondition(probeCtx, ds, probe)
+		result.RequeueAfter = datasourceProbePeriod
 	case "cortex":
-		log.Info("Datasource Type is Cortex", "address", ds.Spec.ConnectionDetails.Address)
-		r.Recorder.Event(ds, "Warning", "NotImplemented", "Cortex support is not implemented yet")
+		if probe {
+			r.Recorder.Event(ds, "Warning", "NotImplemented", "Cortex support is not implemented yet")
+			ds.Status.LastProbeTime = metav1.Now()
+		}
+	}
+
+	if r.setRulerQueueStatus(ds) && (result.RequeueAfter == 0 || rulerQueueStatusPeriod < result.RequeueAfter) {
+		result.RequeueAfter = rulerQueueStatusPeriod
+	}
+
+	wasReady := apimeta.IsStatusConditionTrue(original.Conditions, utils.ConditionReady)
+	utils.SetConditions(ds,
+		utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled, "Datasource reconciled"),
+		degradedCondition(ds.Status.RulerQueue),
+	)
+	if !reflect.DeepEqual(original, &ds.Status) {
+		if err := r.Status().Update(ctx, ds); err != nil {
+			log.Error(err, "Failed to update Datasource status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
 	}
 
 	log.V(1).Info("Datasource reconciled")
-	r.Recorder.Event(ds, "Normal", "DatasourceReconciled", "Datasource reconciled")
+	if !wasReady {
+		r.Recorder.Event(ds, "Normal", "DatasourceReconciled", "Datasource reconciled")
+	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *DatasourceReconciler) connectDatasource(ctx context.Context, ds *openslov1.Datasource) error {
-	datasourceAddress := ""
+// probeDue reports whether the last probe of the Datasource is older than the probe period, belongs to a previous
+// generation of the Datasource or failed
+func probeDue(status *openslov1.DatasourceStatus, generation int64) bool {
+	return status.ObservedGeneration != generation ||
+		!apimeta.IsStatusConditionTrue(status.Conditions, utils.ConditionReady) ||
+		time.Since(status.LastProbeTime.Time) >= datasourceProbePeriod
+}
+
+// setRulerQueueStatus copies the state of the Datasource's ruler write queue into its status and reports whether
+// writes are still pending, the Datasource is then requeued so the reported depth drains to zero
+func (r *DatasourceReconciler) setRulerQueueStatus(ds *openslov1.Datasource) bool {
+	if r.Ruler == nil {
+		return false
+	}
+	queue, ok := r.Ruler.Lookup(types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace})
+	if !ok {
+		return false
+	}
+
+	stats := queue.Stats()
+	queueStatus := &openslov1.RulerQueueStatus{
+		Depth:     stats.Depth,
+		Failing:   stats.Failing,
+		LastError: stats.LastError,
+	}
+	if !stats.LastSyncTime.IsZero() {
+		lastSyncTime := metav1.NewTime(stats.LastSyncTime)
+		queueStatus.LastSyncTime = &lastSyncTime
+	}
+
+	ds.Status.RulerQueue = queueStatus
+	return stats.Depth > 0
+}
 
-	if ds.Spec.Type != "mimir" {
-		return fmt.Errorf("unsupported datasource type: %s", ds.Spec.Type)
-	} else {
-		datasourceAddress = ds.Spec.ConnectionDetails.Address + "/prometheus"
+// degradedCondition reports a Datasource as degraded while the ruler rejects some of its rule groups
+func degradedCondition(queue *openslov1.RulerQueueStatus) metav1.Condition {
+	if queue != nil && queue.Failing > 0 {
+		return utils.NewCondition(utils.ConditionDegraded, metav1.ConditionTrue, utils.ReasonSyncFailed,
+			fmt.Sprintf("%d rule groups could not be written to the ruler: %s", queue.Failing, queue.LastError))
 	}
+	return utils.NewCondition(utils.ConditionDegraded, metav1.ConditionFalse, utils.ReasonAsExpected, "")
+}
+
+// setFederationCondition records whether the ruler behind the Datasource accepts federated rule groups. The
+// ruler configuration is only read again when probe is set, the condition is kept as it is otherwise.
+func (r *DatasourceReconciler) setFederationCondition(ctx context.Context, ds *openslov1.Datasource, probe bool) {
+	condition, ok := r.federationCondition(ctx, ds, probe)
+	if !ok {
+		return
+	}
+	condition.ObservedGeneration = ds.Generation
+	if !apimeta.SetStatusCondition(&ds.Status.Conditions, condition) {
+		return
+	}
+	// A disabled ruler is only a problem for Datasources asking for federation, SLOs report their own overrides
+	if condition.Status == metav1.ConditionFalse &&
+		(condition.Reason == "InvalidTenants" || len(ds.Spec.ConnectionDetails.SourceTenants) > 0) {
+		r.Recorder.Event(ds, "Warning", condition.Reason, condition.Message)
+	}
+}
 
-	customRoundtripper := &CustomRoundTripper{
-		Transport: api.DefaultRoundTripper,
-		TenantID:  ds.Spec.ConnectionDetails.TargetTenant,
+// federationCondition returns the RulerFederation condition of the Datasource, it reports false when the ruler
+// configuration would have to be read and probe is not set
+func (r *DatasourceReconciler) federationCondition(ctx context.Context, ds *openslov1.Datasource, probe bool) (metav1.Condition, bool) {
+	connectionDetails := ds.Spec.ConnectionDetails
+	condition := metav1.Condition{Type: helpers.RulerFederationCondition}
+
+	if err := helpers.ValidateTenants(connectionDetails.TargetTenant, connectionDetails.SourceTenants); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidTenants"
+		condition.Message = err.Error()
+		return condition, true
+	}
+
+	// A federated rule group rejected by the ruler is more reliable than the advertised configuration
+	if r.Ruler != nil {
+		if queue, ok := r.Ruler.Lookup(types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}); ok {
+			if stats := queue.Stats(); stats.Failing > 0 && strings.Contains(stats.LastError, helpers.ErrFederationDisabled) {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = "FederationDisabled"
+				condition.Message = fmt.Sprintf("The ruler rejected a federated rule group: %s", stats.LastError)
+				return condition, true
+			}
+		}
 	}
 
-	newDsClient, err := api.NewClient(api.Config{
-		Address:      datasourceAddress,
-		RoundTripper: customRoundtripper,
-	})
+	if !probe {
+		return condition, false
+	}
+	enabled, err := helpers.RulerFederationEnabled(ctx, connectionDetails.Address, connectionDetails.TargetTenant)
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "ConfigUnavailable"
+		condition.Message = fmt.Sprintf("Could not read the ruler configuration: %s", err.Error())
+	case !enabled:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "FederationDisabled"
+		condition.Message = "Tenant federation is disabled on the ruler, federated rule groups will be rejected"
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "FederationEnabled"
+		condition.Message = "The ruler accepts federated rule groups"
+	}
+	return condition, true
+}
+
+// connectDatasource runs a query against the Datasource. Events are only emitted when the connection state
+// changes, the Datasource is probed again on every retry while it cannot be reached.
+func (r *DatasourceReconciler) connectDatasource(ctx context.Context, ds *openslov1.Datasource) error {
+	ready := apimeta.FindStatusCondition(ds.Status.Conditions, utils.ConditionReady)
+	wasFailing := ready != nil && ready.Status == metav1.ConditionFalse && ready.Reason == utils.ReasonConnectionFailed
+
+	newAPI, datasourceAddress, err := helpers.NewDatasourceAPI(ds)
 	if err != nil {
-		r.Recorder.Event(ds, "Warning", "DatasourceConnectionFailed", "Datasource connection failed")
+		if !stderrors.Is(err, helpers.ErrUnsupportedDatasource) && !wasFailing {
+			r.Recorder.Event(ds, "Warning", "DatasourceConnectionFailed", "Datasource connection failed")
+		}
 		return err
 	}
 
-	newAPI := v1.NewAPI(newDsClient)
-	result, _, err := newAPI.Query(ctx, "up", time.Now())
-	if err != nil {
-		r.Recorder.Event(ds, "Warning", "DatasourceConnectionFailed", fmt.Sprintf("API query failed to address: %s with error: %s", datasourceAddress, err.Error()))
+	if _, _, err := newAPI.Query(ctx, "up", time.Now()); err != nil {
+		if !wasFailing {
+			r.Recorder.Event(ds, "Warning", "DatasourceConnectionFailed", fmt.Sprintf("API query failed to address: %s with error: %s", datasourceAddress, err.Error()))
+		}
 		return err
 	}
-	r.Recorder.Event(ds, "Normal", "DatasourceConnected", fmt.Sprintf("Datasource successfully connected - %s", result.String()))
+	if ready == nil || ready.Status != metav1.ConditionTrue {
+		r.Recorder.Event(ds, "Normal", "DatasourceConnected", fmt.Sprintf("Datasource successfully connected to %s", datasourceAddress))
+	}
 	return nil
 }
 
-func (c *CustomRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("X-Scope-OrgId", c.TenantID)
-	return c.Transport.RoundTrip(req)
-}
-
 // SetupWithManager sets up the controller with the Manager.
 func (r *DatasourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&openslov1.Datasource{}).
-		Complete(r)
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&openslov1.Datasource{})
+	if r.Ruler != nil {
+		builder = builder.WatchesRawSource(source.Channel(r.Ruler.Events(), &handler.EnqueueRequestForObject{}))
+	}
+	return builder.Complete(reconciler.Wrap(mgr, "datasource", &openslov1.Datasource{}, r))
 }
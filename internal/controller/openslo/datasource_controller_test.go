@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/utils"
+)
+
+func TestDatasourceReconcilerProbesOncePerPeriod(t *testing.T) {
+	var queries, configReads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/config") {
+			configReads.Add(1)
+			_, _ = fmt.Fprint(w, "ruler:\n  tenant_federation:\n    enabled: true\n")
+			return
+		}
+		queries.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	ds := &openslov1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "default", Generation: 1},
+		Spec: openslov1.DatasourceSpec{
+			Type:              "mimir",
+			ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ds).WithStatusSubresource(&openslov1.Datasource{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &DatasourceReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}}
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, datasourceProbePeriod, result.RequeueAfter)
+	assert.Equal(t, int32(1), queries.Load())
+	assert.Equal(t, int32(1), configReads.Load())
+	assert.Len(t, recorder.Events, 2, "connecting and becoming ready are reported once")
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+
+	updated := &openslov1.Datasource{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.False(t, updated.Status.LastProbeTime.IsZero())
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, helpers.RulerFederationCondition))
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionReady))
+
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), queries.Load(), "the probe is not due yet")
+	assert.Equal(t, int32(1), configReads.Load())
+	assert.Empty(t, recorder.Events)
+
+	updated.Status.LastProbeTime = metav1.NewTime(time.Now().Add(-datasourceProbePeriod))
+	require.NoError(t, c.Status().Update(context.Background(), updated))
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), queries.Load())
+	assert.Equal(t, int32(2), configReads.Load())
+	assert.Empty(t, recorder.Events, "nothing changed")
+}
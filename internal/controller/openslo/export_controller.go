@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/exporter"
+	"github.com/oskoperator/osko/internal/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ExportBundleKey is the key of the OpenSLO bundle in the export ConfigMaps
+const ExportBundleKey = "openslo.yaml"
+
+// exportManagedBy is the app.kubernetes.io/managed-by label of the export ConfigMaps, the controller neither
+// overwrites nor deletes ConfigMaps of the export name without it
+const exportManagedBy = "osko-controller"
+
+var errExportNotManaged = stderrors.New("ConfigMap exists and is not managed by the operator")
+
+// ExportReconciler keeps the OpenSLO bundle of the SLOs, SLIs, Datasources and AlertPolicies of every exported
+// namespace in a ConfigMap of that namespace. Requests name the ConfigMap of a namespace.
+type ExportReconciler struct {
+	client.Client
+	Export config.ExportConfig
+}
+
+// +kubebuilder:rbac:groups=openslo.com,resources=slos;slis;datasources;alertpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+func (r *ExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+	if !r.Export.Exports(req.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
+	objs, err := exporter.List(ctx, r.Client, req.Namespace)
+	if err != nil {
+		log.Error(err, "Failed to list the objects to export")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	if len(objs.SLOs)+len(objs.SLIs)+len(objs.Datasources)+len(objs.AlertPolicies) == 0 {
+		// The bundle of a namespace without objects is removed rather than left empty
+		if err := r.Get(ctx, req.NamespacedName, configMap); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		if !exportManaged(configMap) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		log.Info("Deleted the OpenSLO export of an empty namespace")
+		return ctrl.Result{}, nil
+	}
+
+	bundle, err := exporter.Render(objs, r.Export.Indicators)
+	if err != nil {
+		log.Error(err, "Failed to render the OpenSLO bundle")
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.ResourceVersion != "" && !exportManaged(configMap) {
+			return fmt.Errorf("%w, remove it or set another EXPORT_CONFIGMAP_NAME", errExportNotManaged)
+		}
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels["app.kubernetes.io/managed-by"] = exportManagedBy
+		configMap.Labels["osko.dev/openslo-export"] = "true"
+		configMap.Data = map[string]string{ExportBundleKey: string(bundle)}
+		return nil
+	})
+	if stderrors.Is(err, errExportNotManaged) {
+		log.Error(err, "Refusing to overwrite the ConfigMap with the OpenSLO export")
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+	if err != nil {
+		log.Error(err, "Failed to write the OpenSLO export ConfigMap")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.V(1).Info("Exported OpenSLO bundle", "operation", result, "slos", len(objs.SLOs))
+	}
+	return ctrl.Result{}, nil
+}
+
+// exportManaged reports whether the ConfigMap was written by the controller
+func exportManaged(configMap *corev1.ConfigMap) bool {
+	return configMap.Labels["app.kubernetes.io/managed-by"] == exportManagedBy
+}
+
+// exportRequest maps an exported object to the request of the export ConfigMap of its namespace
+func (r *ExportReconciler) exportRequest() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if !r.Export.Exports(obj.GetNamespace()) {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: r.Export.ConfigMapName, Namespace: obj.GetNamespace()}}}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("openslo-export").
+		Watches(&openslov1.SLO{}, handler.EnqueueRequestsFromMapFunc(r.exportRequest())).
+		Watches(&openslov1.SLI{}, handler.EnqueueRequestsFromMapFunc(r.exportRequest())).
+		Watches(&openslov1.Datasource{}, handler.EnqueueRequestsFromMapFunc(r.exportRequest())).
+		Watches(&openslov1.AlertPolicy{}, handler.EnqueueRequestsFromMapFunc(r.exportRequest())).
+		Complete(reconciler.Wrap(mgr, "openslo-export", nil, r))
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newExportTestReconciler(t *testing.T, objs ...client.Object) (*ExportReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	export := config.Default().Export
+	export.Namespaces = []string{"shop"}
+	return &ExportReconciler{Client: c, Export: export}, c
+}
+
+func exportTestSLO(namespace string) *openslov1.SLO {
+	sliName := "availability"
+	return &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "availability", Namespace: namespace},
+		Spec: openslov1.SLOSpec{
+			Service:      "checkout",
+			IndicatorRef: &sliName,
+			Objectives:   []openslov1.ObjectivesSpec{{Target: "0.99"}},
+		},
+	}
+}
+
+func TestExportReconcilerWritesBundle(t *testing.T) {
+	r, c := newExportTestReconciler(t, exportTestSLO("shop"))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "openslo-export", Namespace: "shop"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, configMap))
+	assert.Equal(t, "osko-controller", configMap.Labels["app.kubernetes.io/managed-by"])
+	assert.Contains(t, configMap.Data[ExportBundleKey], "apiVersion: openslo/v1\nkind: SLO\n")
+	assert.Contains(t, configMap.Data[ExportBundleKey], "indicatorRef: availability")
+}
+
+func TestExportReconcilerSkipsOtherNamespaces(t *testing.T) {
+	r, c := newExportTestReconciler(t, exportTestSLO("default"))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "openslo-export", Namespace: "default"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	err = c.Get(context.Background(), req.NamespacedName, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err), "expected no ConfigMap, got %v", err)
+	assert.Empty(t, r.exportRequest()(context.Background(), exportTestSLO("default")))
+}
+
+func TestExportReconcilerDeletesBundleOfEmptyNamespace(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "openslo-export",
+		Namespace: "shop",
+		Labels:    map[string]string{"app.kubernetes.io/managed-by": "osko-controller"},
+	}}
+	r, c := newExportTestReconciler(t, configMap)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "openslo-export", Namespace: "shop"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	err = c.Get(context.Background(), req.NamespacedName, &corev1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the ConfigMap to be deleted, got %v", err)
+}
+
+func TestExportReconcilerKeepsUnmanagedConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "openslo-export", Namespace: "shop"},
+		Data:       map[string]string{"settings": "mine"},
+	}
+	r, c := newExportTestReconciler(t, configMap, exportTestSLO("shop"))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "openslo-export", Namespace: "shop"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	var reconcileErr *errors.ReconcileError
+	require.ErrorAs(t, err, &reconcileErr)
+	assert.Equal(t, errors.ErrPermanent, reconcileErr.Type)
+
+	kept := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, kept))
+	assert.Equal(t, map[string]string{"settings": "mine"}, kept.Data)
+	assert.Empty(t, kept.Labels)
+}
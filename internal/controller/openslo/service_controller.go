@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServiceReconciler reconciles a Service object
+type ServiceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Config holds the operator settings the rollup rules are generated with
+	Config *config.Store
+}
+
+//+kubebuilder:rbac:groups=openslo.com,resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=openslo.com,resources=services/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=openslo.com,resources=slos,verbs=get;list;watch
+//+kubebuilder:rbac:groups=openslo.com,resources=datasources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=osko.dev,resources=mimirrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+	cfg := r.Config.Get()
+
+	service := &openslov1.Service{}
+	if err := r.Get(ctx, req.NamespacedName, service); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("Service resource not found. Object must have been deleted.")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get Service")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	original := service.Status.DeepCopy()
+
+	slos := &openslov1.SLOList{}
+	if err := r.List(ctx, slos, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(utils.SLOServiceIndex, service.Name),
+		Namespace:     service.Namespace,
+	}); err != nil {
+		log.Error(err, "Failed to list the SLOs of the Service")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	sort.Slice(slos.Items, func(i, j int) bool { return slos.Items[i].Name < slos.Items[j].Name })
+	notReady := setServiceSLOStatus(service, slos.Items)
+
+	if len(slos.Items) == 0 {
+		if err := r.deleteRollupRules(ctx, service); err != nil {
+			log.Error(err, "Failed to delete the rollup rules of the Service")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		for _, conditionType := range []string{utils.ConditionRulesGenerated, utils.ConditionRulesSynced, utils.ConditionDependenciesResolved} {
+			apimeta.RemoveStatusCondition(&service.Status.Conditions, conditionType)
+		}
+		utils.SetConditions(service, utils.ReadyConditions(utils.ReasonReconciled, "Service has no SLOs")...)
+		return ctrl.Result{}, r.updateStatus(ctx, service, original)
+	}
+
+	// The Datasource of the Service, or the one all of its SLOs share
+	dsName, err := r.serviceDatasource(ctx, service, slos.Items)
+	if err != nil {
+		message := err.Error()
+		utils.SetConditions(service,
+			utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionFalse, utils.ReasonInvalidSpec, message),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInvalidSpec, message),
+		)
+		if statusErr := r.updateStatus(ctx, service, original); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+	ds := &openslov1.Datasource{}
+	if err := r.Get(ctx, client.ObjectKey{Name: dsName, Namespace: service.Namespace}, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			utils.SetConditions(service, dependencyNotResolved(utils.ReasonDatasourceNotFound, errDatasourceRef)...)
+			if statusErr := r.updateStatus(ctx, service, original); statusErr != nil {
+				return ctrl.Result{}, statusErr
+			}
+			return ctrl.Result{}, errors.DependencyNotReady(err)
+		}
+		log.Error(err, errGetDS)
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	utils.SetCondition(service, utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionTrue, utils.ReasonDependenciesResolved, ""))
+
+	rollup, err := helpers.CreateServiceRollupRule(service, slos.Items, cfg)
+	if err != nil {
+		reason := utils.ReasonRuleGenerationFailed
+		if stderrors.Is(err, errors.ErrInvalidRule) || stderrors.Is(err, errors.ErrInvalidTarget) {
+			reason = utils.ReasonInvalidRule
+		}
+		message := fmt.Sprintf("Failed to generate the rollup rules: %v", err)
+		utils.SetConditions(service,
+			utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionFalse, reason, message),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, reason, message),
+		)
+		if statusErr := r.updateStatus(ctx, service, original); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		if reason == utils.ReasonInvalidRule {
+			return ctrl.Result{}, errors.Permanent(err)
+		}
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	prometheusRule, err := r.createOrUpdatePrometheusRule(ctx, service, rollup, dsName)
+	if err != nil {
+		log.Error(err, "Failed to create or update the rollup PrometheusRule")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	utils.SetCondition(service, utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionTrue, utils.ReasonRulesGenerated, "Rollup PrometheusRule generated"))
+
+	mimirRule, err := r.createOrUpdateMimirRule(ctx, service, prometheusRule, ds, cfg)
+	if err != nil {
+		log.Error(err, "Failed to create or update the rollup MimirRule")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	utils.SetCondition(service, rulesSyncedCondition(mimirRule))
+
+	ready := utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled, "Service reconciled")
+	if len(notReady) > 0 {
+		ready = utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSLOsNotReady,
+			fmt.Sprintf("SLOs not ready: %s", strings.Join(notReady, ", ")))
+	} else if c := utils.FindCondition(service, utils.ConditionRulesSynced); c != nil && c.Status == metav1.ConditionFalse {
+		ready = utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, c.Reason, c.Message)
+	}
+	utils.SetCondition(service, ready)
+
+	log.V(1).Info("Reconciliation completed")
+	return ctrl.Result{}, r.updateStatus(ctx, service, original)
+}
+
+// setServiceSLOStatus aggregates the readiness and error budget of the SLOs into the status of the Service
+// and returns the names of the SLOs that are not ready
+func setServiceSLOStatus(service *openslov1.Service, slos []openslov1.SLO) []string {
+	var notReady, exhausted []string
+	var worst *float64
+	service.Status.SLOs = nil
+	for i := range slos {
+		slo := &slos[i]
+		sloStatus := openslov1.ServiceSLOStatus{
+			Name:                 slo.Name,
+			Ready:                metav1.ConditionUnknown,
+			ErrorBudgetRemaining: slo.Status.ErrorBudgetRemaining,
+		}
+		if ready := utils.FindCondition(slo, utils.ConditionReady); ready != nil {
+			sloStatus.Ready = ready.Status
+		}
+		if sloStatus.Ready != metav1.ConditionTrue {
+			notReady = append(notReady, slo.Name)
+		}
+		if c := utils.FindCondition(slo, utils.ConditionBudgetExhausted); c != nil && c.Status == metav1.ConditionTrue {
+			sloStatus.BudgetExhausted = true
+			exhausted = append(exhausted, slo.Name)
+		}
+		if remaining, err := strconv.ParseFloat(slo.Status.ErrorBudgetRemaining, 64); err == nil && (worst == nil || remaining < *worst) {
+			worst = &remaining
+		}
+		service.Status.SLOs = append(service.Status.SLOs, sloStatus)
+	}
+
+	service.Status.ReadySLOs = fmt.Sprintf("%d/%d", len(slos)-len(notReady), len(slos))
+	service.Status.ErrorBudgetRemaining = formatStatusValue(worst, 4)
+	if len(exhausted) > 0 {
+		utils.SetCondition(service, utils.NewCondition(utils.ConditionBudgetExhausted, metav1.ConditionTrue, utils.ReasonBudgetExhausted,
+			fmt.Sprintf("Error budget exhausted: %s", strings.Join(exhausted, ", "))))
+	} else {
+		utils.SetCondition(service, utils.NewCondition(utils.ConditionBudgetExhausted, metav1.ConditionFalse, utils.ReasonBudgetAvailable,
+			"No SLO has exhausted its error budget"))
+	}
+	return notReady
+}
+
+// serviceDatasource returns the Datasource the rollup rules are evaluated on: the osko.dev/datasourceRef annotation
+// of the Service, otherwise the Datasource all of its SLOs use
+func (r *ServiceReconciler) serviceDatasource(ctx context.Context, service *openslov1.Service, slos []openslov1.SLO) (string, error) {
+	if name := service.ObjectMeta.Annotations["osko.dev/datasourceRef"]; name != "" {
+		return name, nil
+	}
+	names := map[string]bool{}
+	for i := range slos {
+		effective, err := helpers.EffectiveSLO(ctx, r.Client, &slos[i])
+		if err != nil {
+			return "", err
+		}
+		if name := effective.ObjectMeta.Annotations["osko.dev/datasourceRef"]; name != "" {
+			names[name] = true
+		}
+	}
+	if len(names) != 1 {
+		return "", fmt.Errorf("the SLOs of the Service use %d Datasources, set the osko.dev/datasourceRef annotation on the Service", len(names))
+	}
+	for name := range names {
+		return name, nil
+	}
+	return "", nil
+}
+
+func (r *ServiceReconciler) createOrUpdatePrometheusRule(ctx context.Context, service *openslov1.Service, rollup *monitoringv1.PrometheusRule, dsName string) (*monitoringv1.PrometheusRule, error) {
+	prometheusRule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: rollup.Name, Namespace: rollup.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, prometheusRule, func() error {
+		prometheusRule.Labels = rollup.Labels
+		prometheusRule.Annotations = rollupAnnotations(rollup.Annotations, dsName)
+		prometheusRule.Spec = rollup.Spec
+		return controllerutil.SetControllerReference(service, prometheusRule, r.Scheme)
+	})
+	return prometheusRule, err
+}
+
+// createOrUpdateMimirRule writes the rollup rules to the target tenant of the Datasource, where the SLOs record
+// their error budget ratios
+func (r *ServiceReconciler) createOrUpdateMimirRule(ctx context.Context, service *openslov1.Service, prometheusRule *monitoringv1.PrometheusRule, ds *openslov1.Datasource, cfg config.Config) (*oskov1alpha1.MimirRule, error) {
+	connectionDetails := ds.Spec.ConnectionDetails.DeepCopy()
+	connectionDetails.SourceTenants = nil
+	groups, err := helpers.NewMimirRuleGroups(prometheusRule, connectionDetails, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mimirRule := &oskov1alpha1.MimirRule{
+		ObjectMeta: metav1.ObjectMeta{Name: prometheusRule.Name, Namespace: prometheusRule.Namespace},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, mimirRule, func() error {
+		mimirRule.Labels = prometheusRule.Labels
+		mimirRule.Annotations = rollupAnnotations(prometheusRule.Annotations, ds.Name)
+		mimirRule.Spec.ConnectionDetails = *connectionDetails
+		mimirRule.Spec.Groups = groups
+		return controllerutil.SetControllerReference(service, mimirRule, r.Scheme)
+	})
+	return mimirRule, err
+}
+
+// rollupAnnotations returns a copy of the annotations pointing at the Datasource the rollup rules are written to
+func rollupAnnotations(annotations map[string]string, dsName string) map[string]string {
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result["osko.dev/datasourceRef"] = dsName
+	return result
+}
+
+// deleteRollupRules removes the rollup rules of a Service that no longer has SLOs
+func (r *ServiceReconciler) deleteRollupRules(ctx context.Context, service *openslov1.Service) error {
+	key := types.NamespacedName{Name: helpers.ServiceRollupRuleName(service), Namespace: service.Namespace}
+	for _, obj := range []client.Object{&oskov1alpha1.MimirRule{}, &monitoringv1.PrometheusRule{}} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(obj, service) {
+			continue
+		}
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStatus writes the status of the Service when it changed
+func (r *ServiceReconciler) updateStatus(ctx context.Context, service *openslov1.Service, original *openslov1.ServiceStatus) error {
+	if reflect.DeepEqual(original, &service.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, service); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Failed to update Service status")
+		return errors.Transient(err, 5*time.Second)
+	}
+	return nil
+}
+
+// findServiceForSLO enqueues the Service an SLO belongs to
+func (r *ServiceReconciler) findServiceForSLO() func(ctx context.Context, a client.Object) []reconcile.Request {
+	return func(ctx context.Context, a client.Object) []reconcile.Request {
+		slo, ok := a.(*openslov1.SLO)
+		if !ok || slo.Spec.Service == "" {
+			return []reconcile.Request{}
+		}
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{
+				Name:      slo.Spec.Service,
+				Namespace: slo.Namespace,
+			},
+		}}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openslov1.Service{}).
+		Owns(&monitoringv1.PrometheusRule{}).
+		Owns(&oskov1alpha1.MimirRule{}).
+		Watches(
+			&openslov1.SLO{},
+			handler.EnqueueRequestsFromMapFunc(r.findServiceForSLO()),
+		).
+		WatchesRawSource(reconciler.ConfigChanges(r.Config, reconciler.EnqueueAll(mgr.GetClient(), &openslov1.ServiceList{}))).
+		Complete(reconciler.Wrap(mgr, "service", &openslov1.Service{}, r))
+}
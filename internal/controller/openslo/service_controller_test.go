@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/utils"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newServiceTestReconciler(t *testing.T, objs ...client.Object) (*ServiceReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	require.NoError(t, monitoringv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&openslov1.Service{}).
+		WithIndex(&openslov1.SLO{}, utils.SLOServiceIndex, utils.SLOService).
+		Build()
+	return &ServiceReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config:   config.NewStore(config.Default()),
+	}, c
+}
+
+func serviceTestSLO(name, service, remaining string, ready, exhausted metav1.ConditionStatus) *openslov1.SLO {
+	return &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{"osko.dev/datasourceRef": "mimir"},
+		},
+		Spec: openslov1.SLOSpec{
+			Service:    service,
+			Objectives: []openslov1.ObjectivesSpec{{Target: "0.99"}},
+		},
+		Status: openslov1.SLOStatus{
+			ErrorBudgetRemaining: remaining,
+			Conditions: []metav1.Condition{
+				{Type: utils.ConditionReady, Status: ready, Reason: utils.ReasonReconciled},
+				{Type: utils.ConditionBudgetExhausted, Status: exhausted, Reason: utils.ReasonBudgetAvailable},
+			},
+		},
+	}
+}
+
+func TestServiceReconcilerAggregatesSLOs(t *testing.T) {
+	service := &openslov1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", UID: "service-uid"}}
+	r, c := newServiceTestReconciler(t,
+		service,
+		&openslov1.Datasource{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "default"}},
+		serviceTestSLO("availability", "checkout", "0.8000", metav1.ConditionTrue, metav1.ConditionFalse),
+		serviceTestSLO("latency", "checkout", "-0.2500", metav1.ConditionFalse, metav1.ConditionTrue),
+		serviceTestSLO("search", "search", "0.1000", metav1.ConditionTrue, metav1.ConditionFalse),
+	)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &openslov1.Service{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.Equal(t, "1/2", updated.Status.ReadySLOs)
+	assert.Equal(t, "-0.2500", updated.Status.ErrorBudgetRemaining)
+	require.Len(t, updated.Status.SLOs, 2)
+	assert.Equal(t, openslov1.ServiceSLOStatus{Name: "latency", Ready: metav1.ConditionFalse, ErrorBudgetRemaining: "-0.2500", BudgetExhausted: true}, updated.Status.SLOs[1])
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionBudgetExhausted))
+	ready := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, utils.ReasonSLOsNotReady, ready.Reason)
+
+	key := types.NamespacedName{Name: helpers.ServiceRollupRuleName(service), Namespace: "default"}
+	prometheusRule := &monitoringv1.PrometheusRule{}
+	require.NoError(t, c.Get(context.Background(), key, prometheusRule))
+	assert.Len(t, prometheusRule.Spec.Groups[0].Rules, 3)
+	assert.Equal(t, "mimir", prometheusRule.Annotations["osko.dev/datasourceRef"])
+	mimirRule := &oskov1alpha1.MimirRule{}
+	require.NoError(t, c.Get(context.Background(), key, mimirRule))
+	assert.True(t, metav1.IsControlledBy(mimirRule, service))
+}
+
+func TestServiceReconcilerWithoutSLOs(t *testing.T) {
+	service := &openslov1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", UID: "service-uid"}}
+	ownerRef := *metav1.NewControllerRef(service, openslov1.GroupVersion.WithKind("Service"))
+	key := types.NamespacedName{Name: helpers.ServiceRollupRuleName(service), Namespace: "default"}
+	r, c := newServiceTestReconciler(t,
+		service,
+		&monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, OwnerReferences: []metav1.OwnerReference{ownerRef}}},
+		&oskov1alpha1.MimirRule{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, OwnerReferences: []metav1.OwnerReference{ownerRef}}},
+	)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &openslov1.Service{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.Equal(t, "0/0", updated.Status.ReadySLOs)
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionReady))
+	assert.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, &monitoringv1.PrometheusRule{})))
+	assert.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, &oskov1alpha1.MimirRule{})))
+}
+
+func TestServiceReconcilerConflictingDatasources(t *testing.T) {
+	other := serviceTestSLO("latency", "checkout", "", metav1.ConditionTrue, metav1.ConditionFalse)
+	other.Annotations["osko.dev/datasourceRef"] = "prometheus"
+	r, c := newServiceTestReconciler(t,
+		&openslov1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"}},
+		serviceTestSLO("availability", "checkout", "", metav1.ConditionTrue, metav1.ConditionFalse),
+		other,
+	)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.Error(t, err)
+
+	updated := &openslov1.Service{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	generated := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionRulesGenerated)
+	require.NotNil(t, generated)
+	assert.Equal(t, utils.ReasonInvalidSpec, generated.Reason)
+}
+
+func TestFindServiceForSLO(t *testing.T) {
+	r := &ServiceReconciler{}
+	requests := r.findServiceForSLO()(context.Background(), serviceTestSLO("availability", "checkout", "", metav1.ConditionTrue, metav1.ConditionFalse))
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "checkout", Namespace: "default"}, requests[0].NamespacedName)
+	assert.Empty(t, r.findServiceForSLO()(context.Background(), serviceTestSLO("standalone", "", "", metav1.ConditionTrue, metav1.ConditionFalse)))
+}
@@ -2,33 +2,58 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
 	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
-	errGetSLI = "could not get SLI Object"
+	errGetSLI    = "could not get SLI Object"
+	sliFinalizer = "finalizer.sli.osko.dev"
+
+	sliValidationQueryTimeout = 10 * time.Second
 )
 
 // SLIReconciler reconciles a SLI object
 type SLIReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Config holds the operator settings, the SLI validation period among them
+	Config *config.Store
+	// BlockDeletionInUse holds the deletion of SLIs that SLOs still use back with a finalizer. It is the fallback
+	// for when the SLI webhook, which rejects such deletions right away, is not served.
+	BlockDeletionInUse bool
 }
 
 //+kubebuilder:rbac:groups=openslo.com,resources=slis,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=openslo.com,resources=slis/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=openslo.com,resources=slis/finalizers,verbs=update
+//+kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get;list;watch
 
 func (r *SLIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
+	validationPeriod := r.Config.Get().SLIValidationPeriod
 
 	sli := &openslov1.SLI{}
 	err := r.Get(ctx, req.NamespacedName, sli)
@@ -41,14 +66,182 @@ func (r *SLIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.Error(err, errGetSLI)
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
+	original := sli.Status.DeepCopy()
+
+	consumers, err := helpers.SLIConsumers(ctx, r.Client, sli)
+	if err != nil {
+		log.Error(err, "Failed to list SLOs using the SLI")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	if sli.DeletionTimestamp != nil {
+		return r.finalize(ctx, sli, consumers)
+	}
+
+	// The finalizer of an operator that ran without webhooks is dropped once they are served
+	if controllerutil.ContainsFinalizer(sli, sliFinalizer) != r.BlockDeletionInUse {
+		if r.BlockDeletionInUse {
+			controllerutil.AddFinalizer(sli, sliFinalizer)
+		} else {
+			controllerutil.RemoveFinalizer(sli, sliFinalizer)
+		}
+		if err := r.Update(ctx, sli); err != nil {
+			log.Error(err, "Failed to update finalizer")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+	}
+
+	sli.Status.SLOs = sloNames(consumers)
+	// Status writes trigger another reconcile, only run the queries again once the last validation is due
+	if queryValidationDue(original, sli.Generation, validationPeriod) {
+		utils.SetCondition(sli, r.validateQueries(ctx, sli, consumers, validationPeriod))
+	}
+	utils.SetCondition(sli, utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled, "SLI reconciled"))
+	if !reflect.DeepEqual(original, &sli.Status) {
+		if err := r.Status().Update(ctx, sli); err != nil {
+			log.Error(err, "Failed to update SLI status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+	}
 
 	log.V(1).Info("SLI reconciled", "SLI Name", sli.Name, "SLI Namespace", sli.Namespace)
+	return ctrl.Result{RequeueAfter: validationPeriod}, nil
+}
+
+// finalize holds the deletion of an SLI back while SLOs still use it, they would lose their indicator otherwise
+func (r *SLIReconciler) finalize(ctx context.Context, sli *openslov1.SLI, consumers []openslov1.SLO) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(sli, sliFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if r.BlockDeletionInUse && len(consumers) > 0 {
+		names := sloNames(consumers)
+		message := fmt.Sprintf("SLI is still used by SLOs %s, deletion is blocked until they no longer reference it", strings.Join(names, ", "))
+		log.Info("Blocking deletion of SLI in use", "SLOs", names)
+		if r.Recorder != nil {
+			r.Recorder.Event(sli, "Warning", utils.ReasonInUse, message)
+		}
+		sli.Status.SLOs = names
+		utils.SetCondition(sli, utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInUse, message))
+		if err := r.Status().Update(ctx, sli); err != nil {
+			log.Error(err, "Failed to update SLI status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		// The SLO watch brings the SLI back once its consumers are gone or changed
+		return ctrl.Result{}, nil
+	}
+
+	controllerutil.RemoveFinalizer(sli, sliFinalizer)
+	if err := r.Update(ctx, sli); err != nil {
+		log.Error(err, "Failed to remove finalizer")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// validateQueries runs the SLI queries against the Datasource of the SLI and returns its Degraded condition.
+// The Datasource is taken from the osko.dev/datasourceRef annotation of the SLI, or of the first SLO using it.
+func (r *SLIReconciler) validateQueries(ctx context.Context, sli *openslov1.SLI, consumers []openslov1.SLO, validationPeriod time.Duration) metav1.Condition {
+	log := log.FromContext(ctx)
+	asExpected := utils.NewCondition(utils.ConditionDegraded, metav1.ConditionFalse, utils.ReasonAsExpected, "")
+
+	if validationPeriod == 0 {
+		return asExpected
+	}
+
+	dsName := sli.Annotations["osko.dev/datasourceRef"]
+	defaults, err := helpers.GetSLODefaults(ctx, r.Client, sli.Namespace)
+	if err != nil {
+		log.V(1).Info("Not validating SLI queries", "reason", err.Error())
+		return asExpected
+	}
+	for _, slo := range consumers {
+		if dsName != "" {
+			break
+		}
+		dsName = helpers.WithSLODefaults(&slo, defaults).Annotations["osko.dev/datasourceRef"]
+	}
+	if dsName == "" {
+		log.V(1).Info("Not validating SLI queries, no Datasource to run them against")
+		return asExpected
+	}
+
+	ds := &openslov1.Datasource{}
+	if err := r.Get(ctx, types.NamespacedName{Name: dsName, Namespace: sli.Namespace}, ds); err != nil {
+		log.V(1).Info("Not validating SLI queries", "datasource", dsName, "reason", err.Error())
+		return asExpected
+	}
+	dsAPI, _, err := helpers.NewDatasourceAPI(ds)
+	if err != nil {
+		log.V(1).Info("Not validating SLI queries", "datasource", dsName, "reason", err.Error())
+		return asExpected
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, sliValidationQueryTimeout)
+	defer cancel()
+	now := time.Now()
+	sli.Status.Queries = helpers.ValidateSLIQueries(queryCtx, dsAPI, sli, now)
+	sli.Status.LastValidationTime = metav1.NewTime(now)
+
+	var failed []string
+	for _, q := range sli.Status.Queries {
+		if q.Error != "" {
+			failed = append(failed, q.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return utils.NewCondition(utils.ConditionDegraded, metav1.ConditionTrue, utils.ReasonQueryFailed,
+			fmt.Sprintf("Queries %s failed against Datasource %s", strings.Join(failed, ", "), dsName))
+	}
+	return asExpected
+}
+
+// queryValidationDue reports whether the last validation of the SLI queries is older than the validation period
+// or belongs to a previous generation of the SLI
+func queryValidationDue(status *openslov1.SLIStatus, generation int64, validationPeriod time.Duration) bool {
+	return status.ObservedGeneration != generation ||
+		time.Since(status.LastValidationTime.Time) >= validationPeriod
+}
+
+func sloNames(slos []openslov1.SLO) []string {
+	names := make([]string, 0, len(slos))
+	for _, slo := range slos {
+		names = append(names, slo.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findSLIsForSLO maps an SLO to the SLI it references or owns, keeping the back-references of the SLI current
+func (r *SLIReconciler) findSLIsForSLO() func(ctx context.Context, a client.Object) []reconcile.Request {
+	return func(ctx context.Context, a client.Object) []reconcile.Request {
+		slo, ok := a.(*openslov1.SLO)
+		if !ok {
+			return nil
+		}
+		var name string
+		switch {
+		case slo.Spec.IndicatorRef != nil:
+			name = *slo.Spec.IndicatorRef
+		case slo.Spec.Indicator != nil:
+			name = helpers.InlineSLIName(slo)
+		default:
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: slo.Namespace}}}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. It relies on the utils.SLOIndicatorRefIndex
+// registered by the manager.
 func (r *SLIReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&openslov1.SLI{}).
-		Complete(r)
+		Watches(
+			&openslov1.SLO{},
+			handler.EnqueueRequestsFromMapFunc(r.findSLIsForSLO()),
+		).
+		Complete(reconciler.Wrap(mgr, "sli", &openslov1.SLI{}, r))
 }
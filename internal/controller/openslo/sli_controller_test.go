@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/utils"
+)
+
+func newSLITestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&openslov1.SLI{}).
+		WithIndex(&openslov1.SLO{}, utils.SLOIndicatorRefIndex, utils.SLOIndicatorRef).
+		Build()
+}
+
+func TestSLIReconcilerRecordsConsumers(t *testing.T) {
+	sli := &openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: "shared-sli", Namespace: "default", Generation: 1}}
+	c := newSLITestClient(t,
+		sli,
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("other-sli")}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "other"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}},
+	)
+	r := &SLIReconciler{Client: c, Recorder: record.NewFakeRecorder(10), BlockDeletionInUse: true}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared-sli", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &openslov1.SLI{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.Equal(t, []string{"api", "checkout"}, updated.Status.SLOs)
+	assert.Contains(t, updated.Finalizers, sliFinalizer)
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionReady))
+}
+
+func TestSLIReconcilerBlocksDeletionInUse(t *testing.T) {
+	sli := &openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: "shared-sli", Namespace: "default", Finalizers: []string{sliFinalizer}}}
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}}
+	c := newSLITestClient(t, sli, slo)
+	recorder := record.NewFakeRecorder(10)
+	r := &SLIReconciler{Client: c, Recorder: recorder, BlockDeletionInUse: true}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared-sli", Namespace: "default"}}
+
+	require.NoError(t, c.Delete(context.Background(), sli))
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	blocked := &openslov1.SLI{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, blocked))
+	ready := apimeta.FindStatusCondition(blocked.Status.Conditions, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, utils.ReasonInUse, ready.Reason)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning InUse SLI is still used by SLOs checkout")
+
+	slo.Spec.IndicatorRef = stringPtr("new-sli")
+	require.NoError(t, c.Update(context.Background(), slo))
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	err = c.Get(context.Background(), req.NamespacedName, &openslov1.SLI{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the SLI to be deleted once unused, got %v", err)
+}
+
+func TestSLIReconcilerDropsFinalizerWithWebhooks(t *testing.T) {
+	sli := &openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: "shared-sli", Namespace: "default", Finalizers: []string{sliFinalizer}}}
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}}
+	c := newSLITestClient(t, sli, slo)
+	r := &SLIReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared-sli", Namespace: "default"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &openslov1.SLI{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.NotContains(t, updated.Finalizers, sliFinalizer)
+	assert.Equal(t, []string{"checkout"}, updated.Status.SLOs)
+}
+
+func TestFindSLIsForSLO(t *testing.T) {
+	r := &SLIReconciler{}
+	tests := []struct {
+		name string
+		slo  *openslov1.SLO
+		want []string
+	}{
+		{
+			name: "referenced SLI",
+			slo:  &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout"}, Spec: openslov1.SLOSpec{IndicatorRef: stringPtr("shared-sli")}},
+			want: []string{"shared-sli"},
+		},
+		{
+			name: "inline SLI",
+			slo:  &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout"}, Spec: openslov1.SLOSpec{Indicator: &openslov1.Indicator{}}},
+			want: []string{"checkout-sli"},
+		},
+		{
+			name: "no SLI",
+			slo:  &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, req := range r.findSLIsForSLO()(context.Background(), tt.slo) {
+				got = append(got, req.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,17 +2,23 @@ package controller
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
 	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
 	"github.com/oskoperator/osko/internal/errors"
 	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/metrics"
+	"github.com/oskoperator/osko/internal/reconciler"
 	"github.com/oskoperator/osko/internal/utils"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,11 +33,12 @@ import (
 )
 
 const (
-	indicatorRef       = ".spec.indicatorRef"
 	errGetSLO          = "could not get SLO Object"
 	errDatasourceRef   = "Unable to get Datasource. Check if the referenced datasource exists."
 	mimirRuleFinalizer = "finalizer.mimir.osko.dev"
 	sloFinalizer       = "finalizer.slo.osko.dev"
+
+	sloStatusQueryTimeout = 10 * time.Second
 )
 
 // SLOReconciler reconciles a SLO object
@@ -39,6 +46,8 @@ type SLOReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// Config holds the operator settings the rules and status are computed with
+	Config *config.Store
 }
 
 //+kubebuilder:rbac:groups=openslo.com,resources=slos,verbs=get;list;watch;create;update;patch;delete
@@ -48,9 +57,11 @@ type SLOReconciler struct {
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=osko.dev,resources=alertmanagerconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=osko.dev,resources=alertmanagerconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get;list;watch
 
 func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx)
+	cfg := r.Config.Get()
 
 	sli := &openslov1.SLI{}
 	slo := &openslov1.SLO{}
@@ -59,11 +70,13 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info("SLO resource not found. Object must have been deleted.")
+			metrics.DeleteGeneratedRules(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, errGetSLO)
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
+	original := slo.Status.DeepCopy()
 
 	// Handle deletion
 	if slo.DeletionTimestamp != nil {
@@ -92,16 +105,22 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Settings the SLO does not set itself come from the SLODefaults of its namespace, they are never written back
+	effective, err := helpers.EffectiveSLO(ctx, r.Client, slo)
+	if err != nil {
+		log.Error(err, "Failed to get SLODefaults")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
 	// Get DS from SLO's ref
-	err = r.Get(ctx, client.ObjectKey{Name: slo.ObjectMeta.Annotations["osko.dev/datasourceRef"], Namespace: slo.Namespace}, ds)
+	err = r.Get(ctx, client.ObjectKey{Name: effective.ObjectMeta.Annotations["osko.dev/datasourceRef"], Namespace: slo.Namespace}, ds)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info(fmt.Sprintf("datasourceRef: %v", errGetDS))
-			slo.Status.Ready = "False"
 			if r.Recorder != nil {
 				r.Recorder.Event(slo, "Warning", "datasourceRef", errDatasourceRef)
 			}
-			if err := r.Status().Update(ctx, slo); err != nil {
+			if err := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonDatasourceNotFound, errDatasourceRef)...); err != nil {
 				log.Error(err, "Failed to update SLO ready status")
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
@@ -111,16 +130,45 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
 
+	// Validate the tenants the SLI queries span before generating any rules
+	federated, err := helpers.FederatedConnectionDetails(slo, &ds.Spec.ConnectionDetails)
+	if err != nil {
+		log.Error(err, "Invalid tenant configuration")
+		if r.Recorder != nil {
+			r.Recorder.Event(slo, "Warning", "InvalidTenants", err.Error())
+		}
+		condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInvalidTenants, fmt.Sprintf("Invalid tenant configuration: %v", err))
+		if err := utils.UpdateConditions(ctx, slo, r.Client, condition); err != nil {
+			log.Error(err, "Failed to update SLO status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+	if len(federated.SourceTenants) > 0 {
+		federation := apimeta.FindStatusCondition(ds.Status.Conditions, helpers.RulerFederationCondition)
+		if federation != nil && federation.Status == metav1.ConditionFalse {
+			err = fmt.Errorf("datasource %s does not accept federated rule groups: %s", ds.Name, federation.Message)
+			log.Error(err, "Ruler federation is not available")
+			if r.Recorder != nil {
+				r.Recorder.Event(slo, "Warning", federation.Reason, err.Error())
+			}
+			if err := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonFederationDisabled, err.Error())...); err != nil {
+				log.Error(err, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			}
+			return ctrl.Result{}, errors.DependencyNotReady(err)
+		}
+	}
+
 	// Handle SLI - either reference existing or create inline SLI
 	if slo.Spec.IndicatorRef != nil {
 		err = r.Get(ctx, client.ObjectKey{Name: *slo.Spec.IndicatorRef, Namespace: slo.Namespace}, sli)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				log.Error(err, "could not get SLI Object")
-				err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "SLI Object not found")
-				if err != nil {
-					log.Error(err, "Failed to update SLO status")
-					return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+				if statusErr := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonSLINotFound, "SLI Object not found")...); statusErr != nil {
+					log.Error(statusErr, "Failed to update SLO status")
+					return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 				}
 				return ctrl.Result{}, errors.DependencyNotReady(err)
 			}
@@ -131,15 +179,15 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		sli, err = r.createOrUpdateInlineSLI(ctx, slo)
 		if err != nil {
 			log.Error(err, "Failed to create inline SLI")
-			err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create inline SLI")
-			if err != nil {
-				log.Error(err, "Failed to update SLO status")
-				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			message := fmt.Sprintf("Failed to create inline SLI: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonInlineSLIFailed, message)...); statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 			}
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
 	} else {
-		err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "SLI Object not found")
+		err = utils.UpdateConditions(ctx, slo, r.Client, dependencyNotResolved(utils.ReasonSLINotFound, "SLO has neither an indicator nor an indicatorRef")...)
 		if err != nil {
 			log.Error(err, "Failed to update SLO status")
 			r.Recorder.Event(slo, "Warning", "SLIObjectNotFound", "SLI Object not found")
@@ -148,6 +196,7 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.Error(err, "SLO has no SLI reference")
 		return ctrl.Result{}, errors.Permanent(fmt.Errorf("SLO has no SLI reference"))
 	}
+	utils.SetCondition(slo, utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionTrue, utils.ReasonDependenciesResolved, ""))
 
 	prometheusRule := &monitoringv1.PrometheusRule{}
 	err = r.Get(ctx, types.NamespacedName{
@@ -157,32 +206,40 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 	if apierrors.IsNotFound(err) {
 		log.V(1).Info("PrometheusRule not found. Let's make one.")
-		prometheusRule, err = helpers.CreatePrometheusRule(slo, sli)
+		prometheusRule, err = helpers.CreatePrometheusRule(effective, sli, cfg)
 		if err != nil {
 			r.Recorder.Event(slo, "Warning", "FailedToCreatePrometheusRule", "Failed to create Prometheus Rule")
-			errUpdateStatus := utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, fmt.Sprintf("Failed to create new Prometheus Rule: %v", err))
+			reason := utils.ReasonRuleGenerationFailed
+			if stderrors.Is(err, errors.ErrInvalidRule) {
+				reason = utils.ReasonInvalidRule
+			}
+			message := fmt.Sprintf("Failed to create new Prometheus Rule: %v", err)
+			errUpdateStatus := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesGenerated, reason, message)...)
 			if errUpdateStatus != nil {
 				log.Error(errUpdateStatus, "Failed to update SLO status")
 				return ctrl.Result{}, errors.Transient(errUpdateStatus, 5*time.Second)
 			}
 			log.V(3).Info(fmt.Sprintf("Failed to create new Prometheus Rule: %v", err))
+			if stderrors.Is(err, errors.ErrInvalidRule) {
+				// Regenerating the same rules will not make them valid, wait for the SLO or SLI to change
+				return ctrl.Result{}, errors.Permanent(err)
+			}
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
 		if err := r.Create(ctx, prometheusRule); err != nil {
 			if r.Recorder != nil {
 				r.Recorder.Event(slo, "Warning", "FailedToCreatePrometheusRule", "Failed to create Prometheus Rule")
 			}
-			if err := r.Status().Update(ctx, prometheusRule); err != nil {
-				log.Error(err, "Failed to update SLO status")
-				if err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create Prometheus Rule"); err != nil {
-					log.Error(err, "Failed to update SLO ready status")
-					return ctrl.Result{}, errors.Transient(err, 5*time.Second)
-				}
-				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			log.Error(err, "Failed to create PrometheusRule")
+			message := fmt.Sprintf("Failed to create PrometheusRule: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesGenerated, utils.ReasonRuleGenerationFailed, message)...); statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 			}
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		} else {
 			log.V(1).Info("PrometheusRule created successfully")
+			metrics.SetGeneratedRules(slo.Namespace, slo.Name, prometheusRule)
 			if r.Recorder != nil {
 				r.Recorder.Event(slo, "Normal", "PrometheusRuleCreated", "PrometheusRule created successfully")
 			}
@@ -197,9 +254,9 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 				return ctrl.Result{}, err
 			}
 
-			slo.Status.Ready = "True"
-			if err := r.Status().Update(ctx, slo); err != nil {
-				log.Error(err, "Failed to update SLO ready status")
+			condition := utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionTrue, utils.ReasonRulesGenerated, "PrometheusRule created")
+			if err := utils.UpdateConditions(ctx, slo, r.Client, condition); err != nil {
+				log.Error(err, "Failed to update SLO status")
 				return ctrl.Result{}, err
 			}
 			return ctrl.Result{}, nil
@@ -207,6 +264,10 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	log.V(1).Info("PrometheusRule found", "Name", prometheusRule.Name, "Namespace", prometheusRule.Namespace)
+	// The PrometheusRule controller owns the condition once the rule exists, it regenerates the rules on SLO changes
+	if utils.FindCondition(slo, utils.ConditionRulesGenerated) == nil {
+		utils.SetCondition(slo, utils.NewCondition(utils.ConditionRulesGenerated, metav1.ConditionTrue, utils.ReasonRulesGenerated, "PrometheusRule generated"))
+	}
 
 	mimirRule := &oskov1alpha1.MimirRule{}
 	err = r.Get(ctx, types.NamespacedName{
@@ -216,11 +277,13 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 	if apierrors.IsNotFound(err) {
 		log.V(1).Info("MimirRule not found. Let's make one.")
-		mimirRule, err = helpers.NewMimirRule(slo, prometheusRule, &ds.Spec.ConnectionDetails)
+		mimirRule, err = helpers.NewMimirRule(effective, prometheusRule, &ds.Spec.ConnectionDetails, cfg)
 		if err != nil {
-			if err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create Mimir Rule Object"); err != nil {
-				log.Error(err, "Failed to update SLO status")
-				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			log.Error(err, "Failed to build MimirRule")
+			message := fmt.Sprintf("Failed to build MimirRule: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesSynced, utils.ReasonSyncFailed, message)...); statusErr != nil {
+				log.Error(statusErr, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 			}
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
@@ -230,14 +293,12 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 				r.Recorder.Event(slo, "Warning", "FailedToCreateMimirRule", "Failed to create Mimir Rule")
 			}
 			log.Error(err, "Failed to create MimirRule")
-			createErr := err
-			if statusErr := r.Status().Update(ctx, slo); statusErr != nil {
+			message := fmt.Sprintf("Failed to create MimirRule: %v", err)
+			if statusErr := utils.UpdateConditions(ctx, slo, r.Client, notReady(utils.ConditionRulesSynced, utils.ReasonSyncFailed, message)...); statusErr != nil {
 				log.Error(statusErr, "Failed to update SLO status")
-				if statusErr = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create Mimir Rule"); statusErr != nil {
-					log.Error(statusErr, "Failed to update SLO ready status")
-				}
+				return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 			}
-			return ctrl.Result{}, errors.Transient(createErr, 5*time.Second)
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		} else {
 			log.V(1).Info("MimirRule created successfully")
 			if r.Recorder != nil {
@@ -253,14 +314,9 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 				log.Error(err, "Failed to update MimirRule with owner reference")
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
-			slo.Status.Ready = "True"
-			mimirRule.Status.Ready = "True"
-			if err := r.Status().Update(ctx, slo); err != nil {
-				log.Error(err, "Failed to update SLO ready status")
-				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
-			}
-			if err := r.Status().Update(ctx, mimirRule); err != nil {
-				log.Error(err, "Failed to update MimirRule ready status")
+			condition := utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionUnknown, utils.ReasonSyncPending, "MimirRule created")
+			if err := utils.UpdateConditions(ctx, slo, r.Client, condition); err != nil {
+				log.Error(err, "Failed to update SLO status")
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
 			return ctrl.Result{}, nil
@@ -268,9 +324,10 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	log.V(1).Info("MimirRule found", "Name", mimirRule.Name, "Namespace", mimirRule.Namespace)
+	utils.SetCondition(slo, rulesSyncedCondition(mimirRule))
 
 	// Create AlertManagerConfig if magic alerting is enabled
-	if slo.ObjectMeta.Annotations["osko.dev/magicAlerting"] == "true" {
+	if effective.ObjectMeta.Annotations["osko.dev/magicAlerting"] == "true" {
 		alertManagerConfig := &oskov1alpha1.AlertManagerConfig{}
 		err = r.Get(ctx, types.NamespacedName{
 			Name:      fmt.Sprintf("%s-alerting", slo.Name),
@@ -279,12 +336,13 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info("AlertManagerConfig not found. Creating one for magic alerting.")
-			alertManagerConfig, err = r.createAlertManagerConfig(ctx, slo, ds)
+			alertManagerConfig, err = r.createAlertManagerConfig(ctx, effective, ds)
 			if err != nil {
 				log.Error(err, "Failed to create AlertManagerConfig")
-				if err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionFalse, "Failed to create AlertManagerConfig"); err != nil {
-					log.Error(err, "Failed to update SLO status")
-					return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+				condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSyncFailed, fmt.Sprintf("Failed to create AlertManagerConfig: %v", err))
+				if statusErr := utils.UpdateConditions(ctx, slo, r.Client, condition); statusErr != nil {
+					log.Error(statusErr, "Failed to update SLO status")
+					return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
 				}
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
@@ -294,6 +352,11 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 				if r.Recorder != nil {
 					r.Recorder.Event(slo, "Warning", "FailedToCreateAlertManagerConfig", "Failed to create AlertManagerConfig")
 				}
+				condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSyncFailed, fmt.Sprintf("Failed to create AlertManagerConfig: %v", err))
+				if statusErr := utils.UpdateConditions(ctx, slo, r.Client, condition); statusErr != nil {
+					log.Error(statusErr, "Failed to update SLO status")
+					return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+				}
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
 
@@ -318,35 +381,152 @@ func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		}
 	}
 
-	if err = utils.UpdateStatus(ctx, slo, r.Client, "Ready", metav1.ConditionTrue, "PrometheusRule created"); err != nil {
-		log.V(1).Error(err, "Failed to update SLO status")
-		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	// Status writes trigger another reconcile, only query the Datasource once the live values are due
+	if liveStatusDue(original, slo.Generation, cfg.SLOStatusRefreshPeriod) {
+		degraded := utils.NewCondition(utils.ConditionDegraded, metav1.ConditionFalse, utils.ReasonAsExpected, "")
+		if err := r.refreshLiveStatus(ctx, slo, ds); err != nil {
+			degraded = utils.NewCondition(utils.ConditionDegraded, metav1.ConditionTrue, utils.ReasonStatusQueryFailed, err.Error())
+		}
+		utils.SetCondition(slo, degraded)
+	}
+
+	ready := utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled, "SLO reconciled")
+	for _, conditionType := range []string{utils.ConditionRulesGenerated, utils.ConditionRulesSynced} {
+		if c := utils.FindCondition(slo, conditionType); c != nil && c.Status == metav1.ConditionFalse {
+			ready = utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, c.Reason, c.Message)
+			break
+		}
+	}
+	utils.SetCondition(slo, ready)
+	if !reflect.DeepEqual(original, &slo.Status) {
+		if err = r.Status().Update(ctx, slo); err != nil {
+			log.V(1).Error(err, "Failed to update SLO status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
 	}
 
 	log.V(1).Info("Reconciliation completed")
 
-	return ctrl.Result{}, nil
+	// Requeue to keep the live SLI and error budget values in the status current
+	return ctrl.Result{RequeueAfter: cfg.SLOStatusRefreshPeriod}, nil
 }
 
-func (r *SLOReconciler) createIndices(mgr ctrl.Manager) error {
-	return mgr.GetFieldIndexer().IndexField(
-		context.TODO(),
-		&openslov1.SLO{},
-		indicatorRef,
-		func(object client.Object) []string {
-			slo := object.(*openslov1.SLO)
-			if slo.Spec.IndicatorRef == nil {
-				return nil
-			}
-			return []string{*slo.Spec.IndicatorRef}
-		})
+// refreshLiveStatus queries the Datasource for the values the generated rules record and copies them into the SLO status.
+// Failures only leave the previous values in place, the rules may simply not have been evaluated yet.
+func (r *SLOReconciler) refreshLiveStatus(ctx context.Context, slo *openslov1.SLO, ds *openslov1.Datasource) error {
+	log := ctrllog.FromContext(ctx)
+
+	if r.Config.Get().SLOStatusRefreshPeriod == 0 {
+		return nil
+	}
+
+	dsAPI, _, err := helpers.NewDatasourceAPI(ds)
+	if err != nil {
+		log.V(1).Info("Not refreshing SLO status", "reason", err.Error())
+		return nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, sloStatusQueryTimeout)
+	defer cancel()
+	values, err := helpers.QuerySLOStatus(queryCtx, dsAPI, slo)
+	if err != nil {
+		log.Error(err, "Failed to query SLO status from datasource", "datasource", ds.Name)
+		return err
+	}
+	setLiveStatus(&slo.Status, values)
+	r.updateBudgetExhausted(ctx, slo, values.ErrorBudgetRemaining)
+	return nil
+}
+
+// updateBudgetExhausted sets the BudgetExhausted condition from the error budget left and emits an event when the
+// budget crosses the threshold, in either direction. Without a value the condition is left as it is.
+func (r *SLOReconciler) updateBudgetExhausted(ctx context.Context, slo *openslov1.SLO, remaining *float64) {
+	if remaining == nil {
+		return
+	}
+	cfg := r.Config.Get()
+	threshold, err := helpers.BudgetExhaustedThreshold(slo, cfg)
+	if err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Falling back to the default error budget threshold")
+		threshold = cfg.BudgetExhaustedThreshold
+	}
+
+	wasExhausted := false
+	if previous := utils.FindCondition(slo, utils.ConditionBudgetExhausted); previous != nil {
+		wasExhausted = previous.Status == metav1.ConditionTrue
+	}
+	condition := budgetExhaustedCondition(*remaining, threshold)
+	utils.SetCondition(slo, condition)
+	if r.Recorder == nil {
+		return
+	}
+	switch {
+	case condition.Status == metav1.ConditionTrue && !wasExhausted:
+		r.Recorder.Event(slo, "Warning", utils.ReasonBudgetExhausted, condition.Message)
+	case condition.Status == metav1.ConditionFalse && wasExhausted:
+		r.Recorder.Event(slo, "Normal", "BudgetRecovered", condition.Message)
+	}
+}
+
+// budgetExhaustedCondition is True when the share of the error budget left is at or below the threshold
+func budgetExhaustedCondition(remaining, threshold float64) metav1.Condition {
+	if remaining <= threshold {
+		return utils.NewCondition(utils.ConditionBudgetExhausted, metav1.ConditionTrue, utils.ReasonBudgetExhausted,
+			fmt.Sprintf("%.2f%% of the error budget left, at or below the threshold of %.2f%%", remaining*100, threshold*100))
+	}
+	return utils.NewCondition(utils.ConditionBudgetExhausted, metav1.ConditionFalse, utils.ReasonBudgetAvailable,
+		fmt.Sprintf("%.2f%% of the error budget left, above the threshold of %.2f%%", remaining*100, threshold*100))
+}
+
+// dependencyNotResolved returns the conditions of an SLO waiting for one of the objects it references
+func dependencyNotResolved(reason, message string) []metav1.Condition {
+	return notReady(utils.ConditionDependenciesResolved, reason, message)
+}
+
+// notReady returns the given condition set to False together with a Ready condition with the same reason
+func notReady(conditionType, reason, message string) []metav1.Condition {
+	return []metav1.Condition{
+		utils.NewCondition(conditionType, metav1.ConditionFalse, reason, message),
+		utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, reason, message),
+	}
+}
+
+// rulesSyncedCondition mirrors the RulesSynced condition of the MimirRule owned by an SLO
+func rulesSyncedCondition(mimirRule *oskov1alpha1.MimirRule) metav1.Condition {
+	synced := apimeta.FindStatusCondition(mimirRule.Status.Conditions, utils.ConditionRulesSynced)
+	if synced == nil {
+		return utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionUnknown, utils.ReasonSyncPending, "MimirRule has not been synced yet")
+	}
+	return utils.NewCondition(utils.ConditionRulesSynced, synced.Status, synced.Reason, synced.Message)
+}
+
+// liveStatusDue reports whether the live values in the status are older than the refresh period or belong to
+// a previous generation of the SLO
+func liveStatusDue(status *openslov1.SLOStatus, generation int64, refreshPeriod time.Duration) bool {
+	return status.ObservedGeneration != generation ||
+		time.Since(status.LastEvaluationTime.Time) >= refreshPeriod
+}
+
+func setLiveStatus(status *openslov1.SLOStatus, values *helpers.SLOStatusValues) {
+	status.LastEvaluationTime = metav1.NewTime(values.EvaluationTime)
+	status.CurrentSLO = formatStatusValue(values.SLI, 6)
+	status.ErrorBudgetRemaining = formatStatusValue(values.ErrorBudgetRemaining, 4)
+	status.FastestBurnRate = formatStatusValue(values.FastestBurnRate, 2)
+	status.FastestBurnRateWindow = values.FastestBurnRateWindow
+}
+
+func formatStatusValue(value *float64, precision int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', precision, 64)
 }
 
 func (r *SLOReconciler) findObjectsForSli() func(ctx context.Context, a client.Object) []reconcile.Request {
 	return func(ctx context.Context, a client.Object) []reconcile.Request {
 		attachedSLOs := &openslov1.SLOList{}
 		listOpts := &client.ListOptions{
-			FieldSelector: fields.OneTermEqualSelector(indicatorRef, a.GetName()),
+			FieldSelector: fields.OneTermEqualSelector(utils.SLOIndicatorRefIndex, a.GetName()),
 			Namespace:     a.GetNamespace(),
 		}
 		err := r.Client.List(ctx, attachedSLOs, listOpts)
@@ -367,11 +547,29 @@ func (r *SLOReconciler) findObjectsForSli() func(ctx context.Context, a client.O
 	}
 }
 
+// findObjectsForDefaults enqueues every SLO in the namespace of an SLODefaults, any of them may inherit from it
+func (r *SLOReconciler) findObjectsForDefaults() func(ctx context.Context, a client.Object) []reconcile.Request {
+	return func(ctx context.Context, a client.Object) []reconcile.Request {
+		slos := &openslov1.SLOList{}
+		if err := r.Client.List(ctx, slos, client.InNamespace(a.GetNamespace())); err != nil {
+			return []reconcile.Request{}
+		}
+
+		requests := make([]reconcile.Request, len(slos.Items))
+		for i, item := range slos.Items {
+			requests[i] = reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      item.Name,
+					Namespace: item.Namespace,
+				},
+			}
+		}
+		return requests
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := r.createIndices(mgr); err != nil {
-		return err
-	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&openslov1.SLO{}).
 		Owns(&monitoringv1.PrometheusRule{}).
@@ -382,35 +580,26 @@ func (r *SLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&openslov1.SLI{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSli()),
 		).
-		Complete(r)
+		Watches(
+			&oskov1alpha1.SLODefaults{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForDefaults()),
+		).
+		WatchesRawSource(reconciler.ConfigChanges(r.Config, reconciler.EnqueueAll(mgr.GetClient(), &openslov1.SLOList{}))).
+		Complete(reconciler.Wrap(mgr, "slo", &openslov1.SLO{}, r))
 }
 
 // createOrUpdateInlineSLI creates or updates an inline SLI resource owned by the SLO
 func (r *SLOReconciler) createOrUpdateInlineSLI(ctx context.Context, slo *openslov1.SLO) (*openslov1.SLI, error) {
 	log := ctrllog.FromContext(ctx)
 
-	// Generate SLI name based on SLO
-	sliName := fmt.Sprintf("%s-sli", slo.Name)
-	if slo.Spec.Indicator.Metadata.Name != "" {
-		sliName = slo.Spec.Indicator.Metadata.Name
-	}
+	sliName := helpers.InlineSLIName(slo)
 
 	sli := &openslov1.SLI{}
 	err := r.Get(ctx, types.NamespacedName{Name: sliName, Namespace: slo.Namespace}, sli)
 
 	if apierrors.IsNotFound(err) {
 		// Create new SLI
-		sli = &openslov1.SLI{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      sliName,
-				Namespace: slo.Namespace,
-			},
-			Spec: openslov1.SLISpec{
-				Description:     slo.Spec.Indicator.Spec.Description,
-				RatioMetric:     slo.Spec.Indicator.Spec.RatioMetric,
-				ThresholdMetric: slo.Spec.Indicator.Spec.ThresholdMetric,
-			},
-		}
+		sli = helpers.NewInlineSLI(slo)
 
 		// Set owner reference
 		if err := controllerutil.SetOwnerReference(slo, sli, r.Scheme); err != nil {
@@ -463,6 +652,8 @@ func (r *SLOReconciler) cleanupSLOResources(ctx context.Context, slo *openslov1.
 	// The owned resources (PrometheusRule, MimirRule, inline SLI, AlertManagerConfig) will be cleaned up automatically
 	// via garbage collection due to owner references
 
+	metrics.DeleteGeneratedRules(slo.Namespace, slo.Name)
+
 	log.Info("SLO cleanup completed", "slo", slo.Name)
 	return nil
 }
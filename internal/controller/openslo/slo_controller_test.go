@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/utils"
 )
 
 // TestSLOOwnershipLogic tests the logic for determining what resources should be owned
@@ -147,7 +152,87 @@ func TestResourceNaming(t *testing.T) {
 	})
 }
 
+func TestUpdateBudgetExhausted(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		previous    metav1.ConditionStatus
+		remaining   float64
+		want        metav1.ConditionStatus
+		event       string
+	}{
+		{name: "budget left", remaining: 0.4, want: metav1.ConditionFalse},
+		{name: "budget overspent", remaining: -0.1, want: metav1.ConditionTrue, event: "Warning BudgetExhausted -10.00% of the error budget left"},
+		{name: "still exhausted", previous: metav1.ConditionTrue, remaining: -0.2, want: metav1.ConditionTrue},
+		{name: "recovered", previous: metav1.ConditionTrue, remaining: 0.05, want: metav1.ConditionFalse, event: "Normal BudgetRecovered 5.00% of the error budget left"},
+		{
+			name:        "below annotation threshold",
+			annotations: map[string]string{"osko.dev/budgetExhaustedThreshold": "0.1"},
+			previous:    metav1.ConditionFalse,
+			remaining:   0.05,
+			want:        metav1.ConditionTrue,
+			event:       "Warning BudgetExhausted 5.00% of the error budget left, at or below the threshold of 10.00%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Annotations: tt.annotations}}
+			if tt.previous != "" {
+				utils.SetCondition(slo, utils.NewCondition(utils.ConditionBudgetExhausted, tt.previous, "", ""))
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &SLOReconciler{Recorder: recorder}
+
+			r.updateBudgetExhausted(context.Background(), slo, &tt.remaining)
+
+			condition := utils.FindCondition(slo, utils.ConditionBudgetExhausted)
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.want, condition.Status)
+			if tt.event == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, tt.event)
+		})
+	}
+}
+
+func TestNotReady(t *testing.T) {
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Generation: 2}}
+
+	utils.SetConditions(slo, notReady(utils.ConditionRulesSynced, utils.ReasonSyncFailed, "Failed to create MimirRule")...)
+
+	synced := utils.FindCondition(slo, utils.ConditionRulesSynced)
+	require.NotNil(t, synced)
+	assert.Equal(t, metav1.ConditionFalse, synced.Status)
+	assert.Equal(t, utils.ReasonSyncFailed, synced.Reason)
+	ready := utils.FindCondition(slo, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, utils.ReasonSyncFailed, ready.Reason)
+	assert.Equal(t, "Failed to create MimirRule", ready.Message)
+	assert.Equal(t, "False", slo.Status.Ready)
+	assert.Equal(t, int64(2), slo.Status.ObservedGeneration)
+}
+
 // Helper function for string pointers
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestFindObjectsForDefaults(t *testing.T) {
+	c := newSLITestClient(t,
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "payment", Namespace: "shop"}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "catalog"}},
+	)
+	r := &SLOReconciler{Client: c}
+	defaults := &oskov1alpha1.SLODefaults{ObjectMeta: metav1.ObjectMeta{Name: oskov1alpha1.SLODefaultsName, Namespace: "shop"}}
+
+	var got []string
+	for _, req := range r.findObjectsForDefaults()(context.Background(), defaults) {
+		assert.Equal(t, "shop", req.Namespace)
+		got = append(got, req.Name)
+	}
+	assert.ElementsMatch(t, []string{"checkout", "payment"}, got)
+}
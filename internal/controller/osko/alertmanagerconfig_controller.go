@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/reconciler"
 	"github.com/oskoperator/osko/internal/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -125,7 +126,8 @@ func (r *AlertManagerConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info(fmt.Sprintf("datasourceRef: %v", "errGetDS"))
 			r.Recorder.Event(amc, "Warning", "datasourceRef", "errDatasourceRef")
-			if err := r.Status().Update(ctx, amc); err != nil {
+			condition := utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionFalse, utils.ReasonDatasourceNotFound, "Datasource from osko.dev/datasourceRef not found")
+			if err := utils.UpdateConditions(ctx, amc, r.Client, condition); err != nil {
 				log.Error(err, "Failed to update amc ready status")
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
@@ -142,7 +144,11 @@ func (r *AlertManagerConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	err = r.Get(ctx, client.ObjectKey{Namespace: amc.Spec.SecretRef.Namespace, Name: amc.Spec.SecretRef.Name}, secret)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			if err = utils.UpdateStatus(ctx, amc, r.Client, "Ready", metav1.ConditionFalse, "Secret from secretRef not found"); err != nil {
+			conditions := []metav1.Condition{
+				utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionFalse, utils.ReasonSecretNotFound, "Secret from secretRef not found"),
+				utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSecretNotFound, "Secret from secretRef not found"),
+			}
+			if err = utils.UpdateConditions(ctx, amc, r.Client, conditions...); err != nil {
 				log.Error(err, "Failed to update amc status")
 				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 			}
@@ -155,7 +161,8 @@ func (r *AlertManagerConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	yamlData, ok := secret.Data["alertmanager.yaml"]
 	if !ok {
-		if err = utils.UpdateStatus(ctx, amc, r.Client, "Ready", metav1.ConditionFalse, "alertmanager.yaml key not found in secret"); err != nil {
+		condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonKeyNotFound, "alertmanager.yaml key not found in secret")
+		if err = utils.UpdateConditions(ctx, amc, r.Client, condition); err != nil {
 			log.Error(err, "Failed to update amc status")
 			return ctrl.Result{}, err
 		}
@@ -180,8 +187,11 @@ func (r *AlertManagerConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	r.Recorder.Event(amc, "Normal", "AlertManagerConfigCreated", "AlertManagerConfig created successfully")
-	if err = utils.UpdateStatus(ctx, amc, r.Client, "Ready", metav1.ConditionTrue, "PrometheusRule created"); err != nil {
-		log.V(1).Error(err, "Failed to update SLO status")
+	conditions := append([]metav1.Condition{
+		utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionTrue, utils.ReasonDependenciesResolved, ""),
+	}, utils.ReadyConditions(utils.ReasonReconciled, "Alertmanager configuration uploaded")...)
+	if err = utils.UpdateConditions(ctx, amc, r.Client, conditions...); err != nil {
+		log.V(1).Error(err, "Failed to update AlertManagerConfig status")
 		return ctrl.Result{}, err
 	}
 
@@ -231,5 +241,5 @@ func (r *AlertManagerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret()),
 		).
-		Complete(r)
+		Complete(reconciler.Wrap(mgr, "alertmanagerconfig", &oskov1alpha1.AlertManagerConfig{}, r))
 }
@@ -4,19 +4,22 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
-	mimirclient "github.com/grafana/mimir/pkg/mimirtool/client"
 	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
 	"github.com/oskoperator/osko/internal/errors"
 	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/ruler"
+	"github.com/oskoperator/osko/internal/utils"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/rulefmt"
-	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -32,10 +35,11 @@ import (
 // MimirRuleReconciler reconciles a MimirRule object
 type MimirRuleReconciler struct {
 	client.Client
-	Scheme             *runtime.Scheme
-	Recorder           record.EventRecorder
-	MimirClient        *mimirclient.MimirClient
-	RequeueAfterPeriod time.Duration
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Ruler    *ruler.Dispatcher
+	// Config holds the operator settings, the requeue period and rule group defaults among them
+	Config *config.Store
 }
 
 const (
@@ -50,10 +54,12 @@ const (
 // +kubebuilder:rbac:groups=osko.dev,resources=mimirrules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=osko.dev,resources=mimirrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=osko.dev,resources=mimirrules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openslo.com,resources=datasources,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx)
+	cfg := r.Config.Get()
 
 	slo := &openslov1.SLO{}
 	prometheusRule := &monitoringv1.PrometheusRule{}
@@ -80,12 +86,21 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
 
-	if err := r.newMimirClient(&mimirRule.Spec.ConnectionDetails); err != nil {
-		log.Error(err, "Failed to create MimirClient")
+	datasource, err := r.datasourceFor(ctx, mimirRule)
+	if err != nil {
+		log.Error(err, "Failed to resolve the Datasource of the MimirRule")
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
+	var rulerQueue *ruler.Queue
+	if datasource.Name != "" {
+		rulerQueue, err = r.Ruler.Queue(datasource, &mimirRule.Spec.ConnectionDetails)
+		if err != nil {
+			log.Error(err, "Failed to create MimirClient")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+	}
 
-	rgs, err := helpers.NewMimirRuleGroups(prometheusRule, &mimirRule.Spec.ConnectionDetails)
+	rgs, err := helpers.NewMimirRuleGroups(prometheusRule, &mimirRule.Spec.ConnectionDetails, cfg)
 	if err != nil {
 		log.Error(err, "Failed to convert MimirRuleGroup")
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
@@ -93,11 +108,23 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	isMimirRuleMarkedToBeDeleted := mimirRule.GetDeletionTimestamp() != nil
 	if isMimirRuleMarkedToBeDeleted {
+		groupNames := make([]string, 0, len(rgs))
 		for _, rg := range rgs {
-			if err := r.deleteMimirRuleGroupAPI(log, rg.Name); err != nil {
-				log.Error(err, "Failed to delete MimirRule from the Mimir API")
-				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			groupNames = append(groupNames, rg.Name)
+		}
+		// Groups synced earlier may no longer be generated, they are deleted all the same
+		groupNames = append(groupNames, staleGroups(previousGroups(mimirRule), groupNames)...)
+		if rulerQueue == nil {
+			log.V(1).Info("Datasource not found for cleanup, skipping Mimir API deletion", "groups", groupNames)
+		} else {
+			if !rulerQueue.Deleted(mimirRuleNamespace, groupNames...) {
+				for _, name := range groupNames {
+					submitDelete(rulerQueue, name)
+				}
+				log.V(1).Info("Waiting for rule groups to be deleted from the Mimir API", "queueDepth", rulerQueue.Depth())
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 			}
+			rulerQueue.Forget(mimirRuleNamespace, groupNames...)
 		}
 		if controllerutil.ContainsFinalizer(mimirRule, mimirRuleFinalizer) {
 			if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -128,7 +155,7 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	if apierrors.IsNotFound(err) {
 		log.V(1).Info("MimirRule not found. Let's make one.")
-		mimirRule, err = helpers.NewMimirRule(slo, prometheusRule, &mimirRule.Spec.ConnectionDetails)
+		mimirRule, err = helpers.NewMimirRule(slo, prometheusRule, &mimirRule.Spec.ConnectionDetails, cfg)
 
 		if err = r.Create(ctx, mimirRule); err != nil {
 			r.Recorder.Event(mimirRule, "Error", "FailedToCreateMimirRule", "Failed to create Mimir Rule")
@@ -149,13 +176,28 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			if err := r.Get(ctx, req.NamespacedName, mimirRule); err != nil {
 				return err
 			}
-			mimirRule.Status.Ready = "True"
+			utils.SetCondition(mimirRule, utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionUnknown, utils.ReasonSyncPending, "MimirRule created"))
 			return r.Status().Update(ctx, mimirRule)
 		}); err != nil {
 			log.Error(err, "Failed to update MimirRule ready status")
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
-		return ctrl.Result{RequeueAfter: r.RequeueAfterPeriod}, nil
+		return ctrl.Result{RequeueAfter: cfg.MimirRuleRequeuePeriod}, nil
+	}
+
+	if rulerQueue == nil {
+		err := fmt.Errorf("no Datasource found for the MimirRule, set the osko.dev/datasourceRef annotation or create a Datasource with the address %q", mimirRule.Spec.ConnectionDetails.Address)
+		log.Error(err, "Datasource not found")
+		r.Recorder.Event(mimirRule, "Warning", utils.ReasonDatasourceNotFound, err.Error())
+		conditions := []metav1.Condition{
+			utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionFalse, utils.ReasonDatasourceNotFound, err.Error()),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonDatasourceNotFound, err.Error()),
+		}
+		if statusErr := utils.UpdateConditions(ctx, mimirRule, r.Client, conditions...); statusErr != nil {
+			log.Error(statusErr, "Failed to update MimirRule status")
+			return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+		}
+		return ctrl.Result{}, errors.DependencyNotReady(err)
 	}
 
 	for _, ref := range mimirRule.ObjectMeta.OwnerReferences {
@@ -172,11 +214,85 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	federated, err := helpers.FederatedConnectionDetails(slo, &mimirRule.Spec.ConnectionDetails)
+	if err != nil {
+		log.Error(err, "Invalid tenant configuration")
+		r.Recorder.Event(mimirRule, "Warning", "InvalidTenants", err.Error())
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+	rgs, err = helpers.NewMimirRuleGroups(prometheusRule, federated, cfg)
+	if err != nil {
+		log.Error(err, "Failed to convert MimirRuleGroup")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	payloads := make([]rwrulefmt.RuleGroup, 0, len(rgs))
+	groups := make([]rulefmt.RuleGroup, 0, len(rgs))
 	for _, rg := range rgs {
-		if err := r.createMimirRuleGroupAPI(log, &rg); err != nil {
+		payload, err := newRuleGroupPayload(log, &rg)
+		if err != nil {
 			log.Error(err, "Failed to create MimirRuleGroup")
 			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 		}
+		payloads = append(payloads, payload)
+		groups = append(groups, payload.RuleGroup)
+	}
+
+	// Never send rule groups the ruler would reject, retrying them can only fail again
+	if err := helpers.ValidateRuleGroups(groups); err != nil {
+		log.Error(err, "Generated rule groups are invalid")
+		r.Recorder.Event(mimirRule, "Warning", "InvalidRule", err.Error())
+		message := fmt.Sprintf("Generated rules are invalid: %v", err)
+		conditions := []metav1.Condition{
+			utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionFalse, utils.ReasonInvalidRule, message),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInvalidRule, message),
+		}
+		if err := utils.UpdateConditions(ctx, mimirRule, r.Client, conditions...); err != nil {
+			log.Error(err, "Failed to update MimirRule status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+		if slo.Name != "" {
+			if err := utils.UpdateConditions(ctx, slo, r.Client, conditions...); err != nil {
+				log.Error(err, "Failed to update SLO status")
+				return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+			}
+		}
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+
+	names := make([]string, 0, len(payloads))
+	for _, payload := range payloads {
+		rulerQueue.Submit(ruler.Operation{Namespace: mimirRuleNamespace, Group: payload})
+		names = append(names, payload.Name)
+	}
+
+	// Groups that dropped out of the spec would keep being evaluated by the ruler, delete them
+	stale := staleGroups(previousGroups(mimirRule), names)
+	for _, name := range stale {
+		submitDelete(rulerQueue, name)
+	}
+	syncedGroups := names
+	if rulerQueue.Deleted(mimirRuleNamespace, stale...) {
+		rulerQueue.Forget(mimirRuleNamespace, stale...)
+	} else {
+		log.V(1).Info("Deleting rule groups no longer in the MimirRule", "groups", stale)
+		syncedGroups = append(syncedGroups, stale...)
+	}
+
+	// Check back soon while the ruler queue still holds writes for this rule, the Datasource reports the queue itself
+	requeueAfter := cfg.MimirRuleRequeuePeriod
+	pending, syncErr := rulerQueue.SyncState(mimirRuleNamespace, syncedGroups...)
+	if pending {
+		requeueAfter = 5 * time.Second
+	}
+	groupsChanged := !slices.Equal(mimirRule.Status.SyncedGroups, syncedGroups)
+	mimirRule.Status.SyncedGroups = syncedGroups
+	resolved := utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionTrue, utils.ReasonDependenciesResolved, "")
+	if utils.SetConditions(mimirRule, append(syncConditions(pending, syncErr), resolved)...) || groupsChanged {
+		if err := r.Status().Update(ctx, mimirRule); err != nil {
+			log.Error(err, "Failed to update MimirRule status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
 	}
 
 	if !controllerutil.ContainsFinalizer(mimirRule, mimirRuleFinalizer) {
@@ -212,16 +328,20 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	log.V(1).Info("MimirRule already exists, we should update it.")
-	newMimirRule, err = helpers.NewMimirRule(slo, prometheusRule, &mimirRule.Spec.ConnectionDetails)
+	newMimirRule, err = helpers.NewMimirRule(slo, prometheusRule, &mimirRule.Spec.ConnectionDetails, cfg)
 	if err != nil {
 		log.Error(err, "Failed to create new MimirRule")
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
+	if slo.Name == "" {
+		// Rules not owned by an SLO, like the rollup rules of a Service, keep their owner
+		newMimirRule.OwnerReferences = mimirRule.OwnerReferences
+	}
 
 	compareResult := reflect.DeepEqual(mimirRule.Spec, newMimirRule.Spec)
 	if compareResult {
 		log.V(1).Info("MimirRule is up to date")
-		return ctrl.Result{RequeueAfter: r.RequeueAfterPeriod}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -232,118 +352,149 @@ func (r *MimirRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return r.Update(ctx, newMimirRule)
 	}); err != nil {
 		log.Error(err, "Failed to update MimirRule")
-		mimirRule.Status.Ready = "False"
-		if statusErr := r.Status().Update(ctx, mimirRule); statusErr != nil {
-			log.Error(statusErr, "Failed to update SLO status")
-		}
-		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
-	}
-
-	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		if err := r.Get(ctx, req.NamespacedName, mimirRule); err != nil {
-			return err
-		}
-		mimirRule.Status.Ready = "True"
-		return r.Status().Update(ctx, mimirRule)
-	}); err != nil {
-		log.Error(err, "Failed to update MimirRule ready status")
 		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
 	}
 
 	r.Recorder.Event(mimirRule, "Normal", "MimirRuleUpdated", "MimirRule updated successfully")
 
 	log.V(1).Info("MimirRule reconciled")
-	return ctrl.Result{RequeueAfter: r.RequeueAfterPeriod}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *MimirRuleReconciler) newMimirClient(connectionDetails *oskov1alpha1.ConnectionDetails) error {
-	mClientConfig := helpers.MimirClientConfig{
-		Address:  connectionDetails.Address,
-		TenantId: connectionDetails.TargetTenant,
+// syncConditions translates the ruler queue state of the rule groups of a MimirRule into its conditions
+func syncConditions(pending bool, syncErr error) []metav1.Condition {
+	switch {
+	case syncErr != nil:
+		message := syncErr.Error()
+		return []metav1.Condition{
+			utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionFalse, utils.ReasonSyncFailed, message),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSyncFailed, message),
+		}
+	case pending:
+		message := "Rule groups are waiting to be written to the ruler"
+		return []metav1.Condition{
+			utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionFalse, utils.ReasonSyncPending, message),
+			utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSyncPending, message),
+		}
+	default:
+		return append([]metav1.Condition{
+			utils.NewCondition(utils.ConditionRulesSynced, metav1.ConditionTrue, utils.ReasonRulesSynced, "Rule groups written to the ruler"),
+		}, utils.ReadyConditions(utils.ReasonReconciled, "MimirRule reconciled")...)
 	}
+}
 
-	mimirClient, err := mClientConfig.NewMimirClient()
-	if err != nil {
-		return err
+// legacyGroupSuffixes are the rule groups generated per SLO before the groups were split by window and recorded in
+// status.syncedGroups
+var legacyGroupSuffixes = []string{"slo_target", "sli_good", "sli_total", "sli_measurement", "error_budget_ratio", "burn_rate", "slo_alert"}
+
+// previousGroups returns the rule groups written to the ruler for the MimirRule before. A MimirRule synced by an
+// operator that did not record its groups yet gets the groups that version generated, named after the SLO like the
+// MimirRule, so they are deleted instead of being evaluated next to the current ones.
+func previousGroups(mimirRule *oskov1alpha1.MimirRule) []string {
+	if len(mimirRule.Status.SyncedGroups) > 0 {
+		return mimirRule.Status.SyncedGroups
+	}
+	if mimirRule.Status.Ready == "" && len(mimirRule.Status.Conditions) == 0 {
+		return nil
+	}
+	groups := make([]string, 0, len(legacyGroupSuffixes))
+	for _, suffix := range legacyGroupSuffixes {
+		groups = append(groups, mimirRule.Name+"_"+suffix)
 	}
+	return groups
+}
 
-	r.MimirClient = mimirClient
+// staleGroups returns the synced rule groups that are not among the current ones
+func staleGroups(synced, current []string) []string {
+	var stale []string
+	for _, name := range synced {
+		if !slices.Contains(current, name) {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
 
-	return nil
+// submitDelete schedules the deletion of a rule group from the ruler
+func submitDelete(rulerQueue *ruler.Queue, name string) {
+	rulerQueue.Submit(ruler.Operation{
+		Namespace: mimirRuleNamespace,
+		Group:     rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{Name: name}},
+		Delete:    true,
+	})
 }
 
-func (r *MimirRuleReconciler) createMimirRuleGroupAPI(log logr.Logger, rule *oskov1alpha1.RuleGroup) error {
-	var mimirRuleNodes []rulefmt.RuleNode
-	mimirRuleNode := rulefmt.RuleNode{}
-	for _, r := range rule.Rules {
-		if r.Alert == "" {
-			mimirRuleNode = rulefmt.RuleNode{
-				Record: yaml.Node{
-					Kind:  8,
-					Value: r.Record,
-				},
-				Alert: yaml.Node{},
-				Expr: yaml.Node{
-					Kind:  8,
-					Value: r.Expr,
-				},
-				Labels: r.Labels,
-			}
-		} else {
-			var modelDuration model.Duration
-			if r.For != nil {
-				parsedDuration, err := model.ParseDuration(string(*r.For))
-				if err != nil {
-					return nil
-				}
-				modelDuration = parsedDuration
-			}
-			mimirRuleNode = rulefmt.RuleNode{
-				Alert: yaml.Node{
-					Kind:  8,
-					Value: r.Alert,
-				},
-				Expr: yaml.Node{
-					Kind:  8,
-					Value: r.Expr,
-				},
-				For:    modelDuration,
-				Labels: r.Labels,
+// datasourceFor resolves the Datasource whose ruler queue a MimirRule is written through: the one the
+// osko.dev/datasourceRef annotation of the MimirRule or of its SLO names, or else the one with the ruler address
+// of the MimirRule. The name is empty when no such Datasource exists.
+func (r *MimirRuleReconciler) datasourceFor(ctx context.Context, mimirRule *oskov1alpha1.MimirRule) (types.NamespacedName, error) {
+	refs := []string{mimirRule.ObjectMeta.Annotations["osko.dev/datasourceRef"]}
+	for _, ref := range mimirRule.ObjectMeta.OwnerReferences {
+		if ref.Kind != "SLO" {
+			continue
+		}
+		slo := &openslov1.SLO{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: mimirRule.Namespace}, slo); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
 			}
+			return types.NamespacedName{}, err
+		}
+		effective, err := helpers.EffectiveSLO(ctx, r.Client, slo)
+		if err != nil {
+			return types.NamespacedName{}, err
 		}
-		mimirRuleNodes = append(mimirRuleNodes, mimirRuleNode)
+		refs = append(refs, effective.ObjectMeta.Annotations["osko.dev/datasourceRef"])
 	}
 
-	log.V(1).Info("Source tenants", "SourceTenants", rule.SourceTenants)
+	for _, name := range refs {
+		if name == "" {
+			continue
+		}
+		key := types.NamespacedName{Name: name, Namespace: mimirRule.Namespace}
+		if err := r.Get(ctx, key, &openslov1.Datasource{}); err == nil {
+			return key, nil
+		} else if !apierrors.IsNotFound(err) {
+			return types.NamespacedName{}, err
+		}
+	}
 
-	mimirRule := rwrulefmt.RuleGroup{
-		RuleGroup: rulefmt.RuleGroup{
-			Name:          rule.Name,
-			Rules:         mimirRuleNodes,
-			SourceTenants: rule.SourceTenants,
-		},
+	address := mimirRule.Spec.ConnectionDetails.Address
+	if address == "" {
+		return types.NamespacedName{}, nil
+	}
+	datasources := &openslov1.DatasourceList{}
+	if err := r.List(ctx, datasources, client.InNamespace(mimirRule.Namespace)); err != nil {
+		return types.NamespacedName{}, err
+	}
+	// Datasources with the same address share the ruler, the first by name keeps the choice stable
+	name := ""
+	for _, ds := range datasources.Items {
+		if ds.Spec.ConnectionDetails.Address == address && (name == "" || ds.Name < name) {
+			name = ds.Name
+		}
+	}
+	if name == "" {
+		return types.NamespacedName{}, nil
 	}
+	return types.NamespacedName{Name: name, Namespace: mimirRule.Namespace}, nil
+}
 
-	err := r.MimirClient.CreateRuleGroup(context.Background(), mimirRuleNamespace, mimirRule)
+// newRuleGroupPayload converts a MimirRule group into the ruler API representation
+func newRuleGroupPayload(log logr.Logger, rule *oskov1alpha1.RuleGroup) (rwrulefmt.RuleGroup, error) {
+	group, err := helpers.NewRuleFileGroup(rule)
 	if err != nil {
-		log.Error(err, "Failed to create rule group")
-		return err
+		return rwrulefmt.RuleGroup{}, err
 	}
 
-	return nil
-}
+	log.V(1).Info("Source tenants", "SourceTenants", rule.SourceTenants)
 
-func (r *MimirRuleReconciler) deleteMimirRuleGroupAPI(log logr.Logger, name string) error {
-	if err := r.MimirClient.DeleteRuleGroup(context.Background(), mimirRuleNamespace, name); err != nil {
-		log.Error(err, "Failed to delete rule group")
-		return err
-	}
-	return nil
+	return rwrulefmt.RuleGroup{RuleGroup: group}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *MimirRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&oskov1alpha1.MimirRule{}).
-		Complete(r)
+		Complete(reconciler.Wrap(mgr, "mimirrule", &oskov1alpha1.MimirRule{}, r))
 }
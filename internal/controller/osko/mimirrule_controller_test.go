@@ -0,0 +1,208 @@
+package osko
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/utils"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewRuleGroupPayload(t *testing.T) {
+	forDuration := monitoringv1.Duration("5m")
+	evaluationDelay := model.Duration(time.Minute)
+	group := &oskov1alpha1.RuleGroup{
+		Name:            "test-slo_slo_alert",
+		SourceTenants:   []string{"infra", "apps"},
+		Interval:        model.Duration(2 * time.Minute),
+		EvaluationDelay: &evaluationDelay,
+		Limit:           10,
+		Rules: []oskov1alpha1.Rule{
+			{
+				Record: "osko_sli_measurement",
+				Expr:   "vector(1)",
+				Labels: map[string]string{"window": "5m"},
+			},
+			{
+				Alert:         "test-slo_alert_page_critical",
+				Expr:          "vector(1)",
+				For:           &forDuration,
+				KeepFiringFor: model.Duration(15 * time.Minute),
+				Labels:        map[string]string{"severity": "P1"},
+				Annotations:   map[string]string{"summary": "SLO Burn Rate Alert"},
+			},
+		},
+	}
+
+	payload, err := newRuleGroupPayload(logr.Discard(), group)
+	require.NoError(t, err)
+
+	assert.Equal(t, group.Interval, payload.Interval)
+	assert.Equal(t, group.EvaluationDelay, payload.EvaluationDelay)
+	assert.Equal(t, 10, payload.Limit)
+	assert.Equal(t, group.SourceTenants, payload.SourceTenants)
+
+	require.Len(t, payload.Rules, 2)
+	assert.Equal(t, "osko_sli_measurement", payload.Rules[0].Record.Value)
+	alert := payload.Rules[1]
+	assert.Equal(t, "test-slo_alert_page_critical", alert.Alert.Value)
+	assert.Equal(t, "5m", alert.For.String())
+	assert.Equal(t, "15m", alert.KeepFiringFor.String())
+	assert.Equal(t, group.Rules[1].Annotations, alert.Annotations)
+	assert.Equal(t, group.Rules[1].Labels, alert.Labels)
+}
+
+func TestStaleGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		synced  []string
+		current []string
+		want    []string
+	}{
+		{name: "nothing synced yet", current: []string{"checkout_slo"}},
+		{name: "unchanged", synced: []string{"checkout_slo", "checkout_medium_slo"}, current: []string{"checkout_slo", "checkout_medium_slo"}},
+		{name: "window class dropped", synced: []string{"checkout_slo", "checkout_long_slo"}, current: []string{"checkout_slo"}, want: []string{"checkout_long_slo"}},
+		{name: "alerting turned off", synced: []string{"checkout_slo", "checkout_slo_alert"}, current: []string{"checkout_slo"}, want: []string{"checkout_slo_alert"}},
+		{name: "window class added", synced: []string{"checkout_slo"}, current: []string{"checkout_slo", "checkout_medium_slo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, staleGroups(tt.synced, tt.current))
+		})
+	}
+}
+
+func TestPreviousGroups(t *testing.T) {
+	legacy := []string{
+		"checkout_slo_target", "checkout_sli_good", "checkout_sli_total", "checkout_sli_measurement",
+		"checkout_error_budget_ratio", "checkout_burn_rate", "checkout_slo_alert",
+	}
+	tests := []struct {
+		name   string
+		status oskov1alpha1.MimirRuleStatus
+		want   []string
+	}{
+		{name: "never synced"},
+		{name: "synced groups recorded", status: oskov1alpha1.MimirRuleStatus{Ready: "True", SyncedGroups: []string{"checkout_slo"}}, want: []string{"checkout_slo"}},
+		{name: "synced before groups were recorded", status: oskov1alpha1.MimirRuleStatus{Ready: "True"}, want: legacy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimirRule := &oskov1alpha1.MimirRule{ObjectMeta: metav1.ObjectMeta{Name: "checkout"}, Status: tt.status}
+			assert.Equal(t, tt.want, previousGroups(mimirRule))
+		})
+	}
+}
+
+func newMimirRuleTestReconciler(t *testing.T, objs ...client.Object) *MimirRuleReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	require.NoError(t, monitoringv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&oskov1alpha1.MimirRule{}).
+		Build()
+	return &MimirRuleReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), Config: config.NewStore(config.Config{})}
+}
+
+func newTestDatasource(name, address string) *openslov1.Datasource {
+	return &openslov1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: openslov1.DatasourceSpec{
+			ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: address},
+		},
+	}
+}
+
+func TestMimirRuleDatasourceFor(t *testing.T) {
+	owner := []metav1.OwnerReference{{APIVersion: "openslo.com/v1", Kind: "SLO", Name: "checkout", UID: "uid"}}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		owners      []metav1.OwnerReference
+		objs        []client.Object
+		want        string
+	}{
+		{
+			name:        "annotation",
+			annotations: map[string]string{"osko.dev/datasourceRef": "mimir"},
+			objs:        []client.Object{newTestDatasource("mimir", "http://other:9009")},
+			want:        "mimir",
+		},
+		{
+			name:   "annotation of the SLO",
+			owners: owner,
+			objs: []client.Object{
+				newTestDatasource("mimir", "http://other:9009"),
+				&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", Annotations: map[string]string{"osko.dev/datasourceRef": "mimir"}}},
+			},
+			want: "mimir",
+		},
+		{
+			name:        "missing Datasource falls back to the address",
+			annotations: map[string]string{"osko.dev/datasourceRef": "gone"},
+			objs:        []client.Object{newTestDatasource("mimir-b", "http://mimir:9009"), newTestDatasource("mimir-a", "http://mimir:9009")},
+			want:        "mimir-a",
+		},
+		{
+			name: "no Datasource with the address",
+			objs: []client.Object{newTestDatasource("mimir", "http://other:9009")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimirRule := &oskov1alpha1.MimirRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", Annotations: tt.annotations, OwnerReferences: tt.owners},
+				Spec:       oskov1alpha1.MimirRuleSpec{ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: "http://mimir:9009"}},
+			}
+			r := newMimirRuleTestReconciler(t, tt.objs...)
+
+			datasource, err := r.datasourceFor(context.Background(), mimirRule)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, datasource.Name)
+		})
+	}
+}
+
+func TestMimirRuleReconcilerReportsMissingDatasource(t *testing.T) {
+	key := types.NamespacedName{Name: "checkout", Namespace: "default"}
+	r := newMimirRuleTestReconciler(t,
+		&monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}},
+		&oskov1alpha1.MimirRule{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       oskov1alpha1.MimirRuleSpec{ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: "http://mimir:9009"}},
+		},
+	)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+
+	var reconcileErr *errors.ReconcileError
+	require.ErrorAs(t, err, &reconcileErr)
+	assert.Equal(t, errors.ErrDependencyNotReady, reconcileErr.Type)
+	mimirRule := &oskov1alpha1.MimirRule{}
+	require.NoError(t, r.Get(context.Background(), key, mimirRule))
+	resolved := apimeta.FindStatusCondition(mimirRule.Status.Conditions, utils.ConditionDependenciesResolved)
+	require.NotNil(t, resolved)
+	assert.Equal(t, metav1.ConditionFalse, resolved.Status)
+	assert.Equal(t, utils.ReasonDatasourceNotFound, resolved.Reason)
+}
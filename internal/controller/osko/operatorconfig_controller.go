@@ -0,0 +1,111 @@
+package osko
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// DefaultOperatorConfigName is the name of the OperatorConfig the operator runs with unless configured otherwise
+const DefaultOperatorConfigName = "osko"
+
+// OperatorConfigReconciler applies the OperatorConfig of the operator to the configuration the other controllers read
+type OperatorConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Name of the OperatorConfig the operator runs with, OperatorConfigs with other names are ignored
+	Name string
+	// Base is the configuration from the environment, the OperatorConfig overrides it field by field
+	Base config.Config
+	// Config receives the effective configuration
+	Config *config.Store
+}
+
+// +kubebuilder:rbac:groups=osko.dev,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=osko.dev,resources=operatorconfigs/status,verbs=get;update;patch
+
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	operatorConfig := &oskov1alpha1.OperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, operatorConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OperatorConfig not found, running with the configuration from the environment")
+			r.Config.Set(r.Base)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get OperatorConfig")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	cfg, err := helpers.OperatorConfigFromSpec(r.Base, operatorConfig.Spec)
+	if err != nil {
+		// Keep running with the last valid configuration until the spec is fixed
+		active := "the configuration from the environment"
+		if operatorConfig.Status.ActiveGeneration > 0 {
+			active = fmt.Sprintf("generation %d", operatorConfig.Status.ActiveGeneration)
+		}
+		err = fmt.Errorf("invalid OperatorConfig, keeping %s active: %w", active, err)
+		condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInvalidSpec, err.Error())
+		if statusErr := r.updateStatus(ctx, operatorConfig, condition); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{}, errors.Permanent(err)
+	}
+
+	r.Config.Set(cfg)
+	if operatorConfig.Status.ActiveGeneration != operatorConfig.Generation {
+		log.Info("Applied OperatorConfig", "generation", operatorConfig.Generation)
+		operatorConfig.Status.ActiveGeneration = operatorConfig.Generation
+		operatorConfig.Status.LastAppliedTime = metav1.Now()
+		if r.Recorder != nil {
+			r.Recorder.Event(operatorConfig, "Normal", "ConfigApplied", fmt.Sprintf("Applied generation %d of the OperatorConfig", operatorConfig.Generation))
+		}
+	}
+	condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled,
+		fmt.Sprintf("Generation %d is active", operatorConfig.Generation))
+	return ctrl.Result{}, r.updateStatus(ctx, operatorConfig, condition)
+}
+
+// updateStatus records the condition and the active version in the status, writing it only when it changed
+func (r *OperatorConfigReconciler) updateStatus(ctx context.Context, operatorConfig *oskov1alpha1.OperatorConfig, condition metav1.Condition) error {
+	original := operatorConfig.Status.DeepCopy()
+	operatorConfig.Status.ObservedGeneration = operatorConfig.Generation
+	utils.SetCondition(operatorConfig, condition)
+	if reflect.DeepEqual(original, &operatorConfig.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, operatorConfig); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Failed to update OperatorConfig status")
+		return errors.Transient(err, 5*time.Second)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&oskov1alpha1.OperatorConfig{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(object client.Object) bool { return object.GetName() == r.Name }),
+			predicate.GenerationChangedPredicate{},
+		)).
+		Complete(reconciler.Wrap(mgr, "operatorconfig", &oskov1alpha1.OperatorConfig{}, r))
+}
@@ -0,0 +1,105 @@
+package osko
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOperatorConfigTestReconciler(t *testing.T, objs ...client.Object) (*OperatorConfigReconciler, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&oskov1alpha1.OperatorConfig{}).
+		Build()
+	return &OperatorConfigReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Name:     DefaultOperatorConfigName,
+		Base:     config.Default(),
+		Config:   config.NewStore(config.Default()),
+	}, c
+}
+
+func TestOperatorConfigReconcilerApplies(t *testing.T) {
+	operatorConfig := &oskov1alpha1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultOperatorConfigName, Generation: 2},
+		Spec: oskov1alpha1.OperatorConfigSpec{
+			AlertingTool:      "pagerduty",
+			DefaultBaseWindow: &metav1.Duration{Duration: 10 * time.Minute},
+			AlertingBurnRates: &oskov1alpha1.OperatorConfigBurnRates{PageShortWindow: "10"},
+		},
+	}
+	r, c := newOperatorConfigTestReconciler(t, operatorConfig)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: DefaultOperatorConfigName}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	cfg := r.Config.Get()
+	assert.Equal(t, "pagerduty", cfg.AlertingTool)
+	assert.Equal(t, 10*time.Minute, cfg.DefaultBaseWindow)
+	assert.Equal(t, 10.0, cfg.AlertingBurnRates.PageShortWindow)
+	assert.Equal(t, config.Default().AlertingBurnRates.PageLongWindow, cfg.AlertingBurnRates.PageLongWindow)
+
+	updated := &oskov1alpha1.OperatorConfig{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	assert.Equal(t, int64(2), updated.Status.ActiveGeneration)
+	assert.False(t, updated.Status.LastAppliedTime.IsZero())
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionReady))
+	assert.Len(t, r.Recorder.(*record.FakeRecorder).Events, 1)
+}
+
+func TestOperatorConfigReconcilerKeepsActiveConfigOnInvalidSpec(t *testing.T) {
+	operatorConfig := &oskov1alpha1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultOperatorConfigName, Generation: 3},
+		Spec:       oskov1alpha1.OperatorConfigSpec{MimirRuleRequeuePeriod: &metav1.Duration{}},
+		Status:     oskov1alpha1.OperatorConfigStatus{ActiveGeneration: 2},
+	}
+	r, c := newOperatorConfigTestReconciler(t, operatorConfig)
+	active := config.Default()
+	active.AlertingTool = "pagerduty"
+	r.Config.Set(active)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: DefaultOperatorConfigName}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.Error(t, err)
+
+	assert.Equal(t, active, r.Config.Get())
+	updated := &oskov1alpha1.OperatorConfig{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	ready := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, utils.ReasonInvalidSpec, ready.Reason)
+	assert.Contains(t, ready.Message, "keeping generation 2 active")
+	assert.Equal(t, int64(2), updated.Status.ActiveGeneration)
+}
+
+func TestOperatorConfigReconcilerRevertsOnDelete(t *testing.T) {
+	r, _ := newOperatorConfigTestReconciler(t)
+	applied := config.Default()
+	applied.AlertingTool = "pagerduty"
+	r.Config.Set(applied)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: DefaultOperatorConfigName}})
+	require.NoError(t, err)
+	assert.Equal(t, r.Base, r.Config.Get())
+}
@@ -0,0 +1,315 @@
+package osko
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/oskoperator/osko/internal/reconciler"
+	"github.com/oskoperator/osko/internal/utils"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reportManagedBy is the app.kubernetes.io/managed-by label of the report ConfigMaps, the controller does not take
+// over ConfigMaps of the output name without it
+const reportManagedBy = "osko-controller"
+
+var errReportNotManaged = stderrors.New("ConfigMap exists and is not managed by the report")
+
+const (
+	// sloReportQueryTimeout bounds the queries of a single SLO of a report run
+	sloReportQueryTimeout  = 30 * time.Second
+	defaultSLOReportPeriod = "30d"
+
+	reportMarkdownKey = "report.md"
+	reportCSVKey      = "report.csv"
+)
+
+// SLOReportReconciler reconciles a SLOReport object
+type SLOReportReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// NewDatasourceAPI builds the query client of a Datasource, helpers.NewDatasourceAPI when nil
+	NewDatasourceAPI func(ds *openslov1.Datasource) (v1.API, string, error)
+	// Config holds the operator settings, the default base window of the SLOs among them
+	Config *config.Store
+}
+
+// +kubebuilder:rbac:groups=osko.dev,resources=sloreports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=osko.dev,resources=sloreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=osko.dev,resources=sloreports/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get;list;watch
+
+func (r *SLOReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	report := &oskov1alpha1.SLOReport{}
+	if err := r.Get(ctx, req.NamespacedName, report); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(1).Info("SLOReport resource not found. Object must have been deleted.")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get SLOReport")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+
+	schedule, err := cronexpr.Parse(report.Spec.Schedule)
+	if err != nil {
+		return r.rejectSpec(ctx, report, fmt.Errorf("invalid schedule %q: %w", report.Spec.Schedule, err))
+	}
+	selector := labels.Everything()
+	if report.Spec.Selector != nil {
+		if selector, err = metav1.LabelSelectorAsSelector(report.Spec.Selector); err != nil {
+			return r.rejectSpec(ctx, report, fmt.Errorf("invalid selector: %w", err))
+		}
+	}
+
+	if report.Spec.Period == "" {
+		report.Spec.Period = defaultSLOReportPeriod
+	}
+	if err := r.validateNamespaces(report); err != nil {
+		return r.rejectSpec(ctx, report, err)
+	}
+
+	now := time.Now()
+	lastRun := report.Status.LastRunTime.Time
+	if lastRun.IsZero() {
+		lastRun = report.CreationTimestamp.Time
+	}
+	// A changed spec is reported on right away, after that the report follows its schedule
+	due := !schedule.Next(lastRun).After(now) || report.Status.ObservedGeneration != report.Generation
+	if report.Spec.Suspend || !due {
+		return r.scheduleNextRun(ctx, report, schedule, now)
+	}
+
+	log.Info("Running SLO report", "period", report.Spec.Period)
+	results, err := r.run(ctx, report, selector, now)
+	if err != nil {
+		log.Error(err, "Failed to select SLOs for the report")
+		return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+	}
+	report.Status.Results = results
+	report.Status.LastRunTime = metav1.NewTime(now)
+
+	if report.Spec.Output != nil {
+		if err := r.writeOutput(ctx, report); stderrors.Is(err, errReportNotManaged) {
+			log.Error(err, "Refusing to overwrite the ConfigMap of the SLOReport")
+			return r.rejectSpec(ctx, report, err)
+		} else if err != nil {
+			log.Error(err, "Failed to write the SLOReport ConfigMap")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	degraded := utils.NewCondition(utils.ConditionDegraded, metav1.ConditionFalse, utils.ReasonAsExpected, "")
+	if failed > 0 {
+		degraded = utils.NewCondition(utils.ConditionDegraded, metav1.ConditionTrue, utils.ReasonQueryFailed,
+			fmt.Sprintf("%d of %d SLOs could not be reported on", failed, len(results)))
+	}
+	utils.SetConditions(report, degraded,
+		utils.NewCondition(utils.ConditionReady, metav1.ConditionTrue, utils.ReasonReconciled, fmt.Sprintf("Reported on %d SLOs", len(results))))
+	if r.Recorder != nil {
+		r.Recorder.Event(report, "Normal", "ReportGenerated", fmt.Sprintf("Reported on %d SLOs over %s", len(results), report.Spec.Period))
+	}
+
+	return r.scheduleNextRun(ctx, report, schedule, now)
+}
+
+// rejectSpec marks a report with a spec that can never run as not ready, it waits for the spec to change
+func (r *SLOReportReconciler) rejectSpec(ctx context.Context, report *oskov1alpha1.SLOReport, err error) (ctrl.Result, error) {
+	condition := utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonInvalidSpec, err.Error())
+	if statusErr := utils.UpdateConditions(ctx, report, r.Client, condition); statusErr != nil {
+		ctrllog.FromContext(ctx).Error(statusErr, "Failed to update SLOReport status")
+		return ctrl.Result{}, errors.Transient(statusErr, 5*time.Second)
+	}
+	return ctrl.Result{}, errors.Permanent(err)
+}
+
+// scheduleNextRun records the next run of the report in its status and requeues it for that time
+func (r *SLOReportReconciler) scheduleNextRun(ctx context.Context, report *oskov1alpha1.SLOReport, schedule *cronexpr.Expression, now time.Time) (ctrl.Result, error) {
+	original := report.Status.DeepCopy()
+	if report.Spec.Suspend {
+		report.Status.NextRunTime = metav1.Time{}
+	} else {
+		report.Status.NextRunTime = metav1.NewTime(schedule.Next(now))
+	}
+	report.Status.ObservedGeneration = report.Generation
+	if utils.FindCondition(report, utils.ConditionReady) == nil {
+		utils.SetCondition(report, utils.NewCondition(utils.ConditionReady, metav1.ConditionUnknown, utils.ReasonAccepted, "Waiting for the first run"))
+	}
+	if !reflect.DeepEqual(original, &report.Status) {
+		if err := r.Status().Update(ctx, report); err != nil {
+			ctrllog.FromContext(ctx).Error(err, "Failed to update SLOReport status")
+			return ctrl.Result{}, errors.Transient(err, 5*time.Second)
+		}
+	}
+	if report.Spec.Suspend || report.Status.NextRunTime.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: report.Status.NextRunTime.Sub(now)}, nil
+}
+
+// run queries every selected SLO over the report period ending at now. Failures are recorded per SLO.
+func (r *SLOReportReconciler) run(ctx context.Context, report *oskov1alpha1.SLOReport, selector labels.Selector, now time.Time) ([]oskov1alpha1.SLOReportResult, error) {
+	slos, err := r.selectSLOs(ctx, report, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	newAPI := r.NewDatasourceAPI
+	if newAPI == nil {
+		newAPI = helpers.NewDatasourceAPI
+	}
+	apis := map[types.NamespacedName]v1.API{}
+	cfg := r.Config.Get()
+
+	defaults := map[string]*oskov1alpha1.SLODefaults{}
+	results := make([]oskov1alpha1.SLOReportResult, 0, len(slos))
+	for i := range slos {
+		namespaceDefaults, ok := defaults[slos[i].Namespace]
+		if !ok {
+			if namespaceDefaults, err = helpers.GetSLODefaults(ctx, r.Client, slos[i].Namespace); err != nil {
+				return nil, fmt.Errorf("failed to get SLODefaults of namespace %s: %w", slos[i].Namespace, err)
+			}
+			defaults[slos[i].Namespace] = namespaceDefaults
+		}
+		slo := helpers.WithSLODefaults(&slos[i], namespaceDefaults)
+		dsKey := types.NamespacedName{Name: slo.Annotations["osko.dev/datasourceRef"], Namespace: slo.Namespace}
+		dsAPI, ok := apis[dsKey]
+		if !ok {
+			ds := &openslov1.Datasource{}
+			if err := r.Get(ctx, dsKey, ds); err != nil {
+				results = append(results, helpers.NewSLOReportResult(slo, nil, fmt.Errorf("failed to get Datasource %s: %w", dsKey.Name, err)))
+				continue
+			}
+			if dsAPI, _, err = newAPI(ds); err != nil {
+				results = append(results, helpers.NewSLOReportResult(slo, nil, err))
+				continue
+			}
+			apis[dsKey] = dsAPI
+		}
+		queryCtx, cancel := context.WithTimeout(ctx, sloReportQueryTimeout)
+		values, err := helpers.QuerySLOReport(queryCtx, dsAPI, slo, report.Spec.Period, now, cfg)
+		cancel()
+		results = append(results, helpers.NewSLOReportResult(slo, values, err))
+	}
+	return results, nil
+}
+
+// validateNamespaces rejects reports selecting SLOs of other namespaces unless the operator allows it. The
+// controller reads SLOs with its own permissions, a report could otherwise copy the budgets of any namespace into
+// a ConfigMap of its own.
+func (r *SLOReportReconciler) validateNamespaces(report *oskov1alpha1.SLOReport) error {
+	if r.Config.Get().SLOReportCrossNamespace {
+		return nil
+	}
+	for _, namespace := range report.Spec.Namespaces {
+		if namespace != report.Namespace {
+			return fmt.Errorf("SLOs of namespace %s can not be selected, reports are limited to their own namespace unless SLO_REPORT_CROSS_NAMESPACE is enabled", namespace)
+		}
+	}
+	return nil
+}
+
+// selectSLOs lists the SLOs with an objective in the namespaces of the report matching its selector
+func (r *SLOReportReconciler) selectSLOs(ctx context.Context, report *oskov1alpha1.SLOReport, selector labels.Selector) ([]openslov1.SLO, error) {
+	namespaces := report.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{report.Namespace}
+	}
+
+	var slos []openslov1.SLO
+	for _, namespace := range namespaces {
+		list := &openslov1.SLOList{}
+		if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, slo := range list.Items {
+			if len(slo.Spec.Objectives) > 0 {
+				slos = append(slos, slo)
+			}
+		}
+	}
+	return slos, nil
+}
+
+// writeOutput renders the results of the report into its ConfigMap, owned by the report
+func (r *SLOReportReconciler) writeOutput(ctx context.Context, report *oskov1alpha1.SLOReport) error {
+	name := report.Spec.Output.ConfigMapName
+	if name == "" {
+		name = report.Name
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: report.Namespace}}
+
+	data := map[string]string{}
+	formats := report.Spec.Output.Formats
+	if len(formats) == 0 {
+		formats = []oskov1alpha1.SLOReportFormat{oskov1alpha1.SLOReportFormatMarkdown}
+	}
+	for _, format := range formats {
+		switch format {
+		case oskov1alpha1.SLOReportFormatMarkdown:
+			data[reportMarkdownKey] = helpers.RenderSLOReportMarkdown(report)
+		case oskov1alpha1.SLOReportFormatCSV:
+			rendered, err := helpers.RenderSLOReportCSV(report)
+			if err != nil {
+				return err
+			}
+			data[reportCSVKey] = rendered
+		}
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.ResourceVersion != "" && !reportManaged(configMap, report) {
+			return fmt.Errorf("%w, remove it or set another output.configMapName", errReportNotManaged)
+		}
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels["app.kubernetes.io/managed-by"] = reportManagedBy
+		configMap.Labels["osko.dev/sloreport"] = report.Name
+		configMap.Data = data
+		return controllerutil.SetControllerReference(report, configMap, r.Scheme)
+	})
+	return err
+}
+
+// reportManaged reports whether the ConfigMap was written by the controller for this report
+func reportManaged(configMap *corev1.ConfigMap, report *oskov1alpha1.SLOReport) bool {
+	return configMap.Labels["app.kubernetes.io/managed-by"] == reportManagedBy &&
+		configMap.Labels["osko.dev/sloreport"] == report.Name
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SLOReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&oskov1alpha1.SLOReport{}).
+		Complete(reconciler.Wrap(mgr, "sloreport", &oskov1alpha1.SLOReport{}, r))
+}
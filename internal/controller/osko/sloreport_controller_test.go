@@ -0,0 +1,218 @@
+package osko
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSLOReportTestClient(t *testing.T, objs ...client.Object) (client.Client, *runtime.Scheme) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&oskov1alpha1.SLOReport{}).
+		Build()
+	return c, scheme
+}
+
+func newReportTestSLO(name, target string, labels map[string]string) *openslov1.SLO {
+	return &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: map[string]string{"osko.dev/datasourceRef": "mimir"},
+		},
+		Spec: openslov1.SLOSpec{
+			Service:    "shop",
+			Objectives: []openslov1.ObjectivesSpec{{Target: target}},
+		},
+	}
+}
+
+func TestSLOReportReconcilerRunsReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.9995"]}]}}`)
+	}))
+	defer server.Close()
+
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default", Generation: 1, CreationTimestamp: metav1.Now()},
+		Spec: oskov1alpha1.SLOReportSpec{
+			Schedule: "0 6 1 * *",
+			Period:   "30d",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}},
+			Output:   &oskov1alpha1.SLOReportOutput{Formats: []oskov1alpha1.SLOReportFormat{oskov1alpha1.SLOReportFormatMarkdown, oskov1alpha1.SLOReportFormatCSV}},
+		},
+	}
+	ds := &openslov1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "default"},
+		Spec: openslov1.DatasourceSpec{
+			Type:              "mimir",
+			ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL},
+		},
+	}
+	c, scheme := newSLOReportTestClient(t,
+		report, ds,
+		newReportTestSLO("checkout", "0.999", map[string]string{"team": "shop"}),
+		newReportTestSLO("search", "0.9999", map[string]string{"team": "shop"}),
+		newReportTestSLO("unselected", "0.99", map[string]string{"team": "other"}),
+	)
+	recorder := record.NewFakeRecorder(10)
+	r := &SLOReportReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}}
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &oskov1alpha1.SLOReport{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	require.Len(t, updated.Status.Results, 2)
+	assert.Equal(t, "checkout", updated.Status.Results[0].Name)
+	assert.True(t, updated.Status.Results[0].Met)
+	assert.Equal(t, "0.5000", updated.Status.Results[0].ErrorBudgetConsumed)
+	assert.Equal(t, "search", updated.Status.Results[1].Name)
+	assert.False(t, updated.Status.Results[1].Met)
+	assert.False(t, updated.Status.LastRunTime.IsZero())
+	assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+	assert.True(t, apimeta.IsStatusConditionTrue(updated.Status.Conditions, utils.ConditionReady))
+	assert.True(t, apimeta.IsStatusConditionFalse(updated.Status.Conditions, utils.ConditionDegraded))
+	assert.Contains(t, <-recorder.Events, "Normal ReportGenerated Reported on 2 SLOs over 30d")
+
+	require.False(t, updated.Status.NextRunTime.IsZero())
+	assert.InDelta(t, time.Until(updated.Status.NextRunTime.Time).Seconds(), result.RequeueAfter.Seconds(), 1)
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, configMap))
+	assert.Contains(t, configMap.Data[reportMarkdownKey], "| default | checkout | shop | 0.999 | 0.999500 | 0.5000 | yes |")
+	assert.Contains(t, configMap.Data[reportCSVKey], "default,search,shop,0.9999,0.999500,5.0000,false,")
+	assert.Equal(t, "monthly", configMap.Labels["osko.dev/sloreport"])
+	require.Len(t, configMap.OwnerReferences, 1)
+	assert.Equal(t, "SLOReport", configMap.OwnerReferences[0].Kind)
+
+	// Not due again until the next scheduled run
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	rerun := &oskov1alpha1.SLOReport{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, rerun))
+	assert.Equal(t, updated.Status.LastRunTime.Unix(), rerun.Status.LastRunTime.Unix())
+	assert.Empty(t, recorder.Events)
+}
+
+func TestSLOReportReconcilerRecordsFailedSLOs(t *testing.T) {
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default", Generation: 1},
+		Spec:       oskov1alpha1.SLOReportSpec{Schedule: "@daily"},
+	}
+	c, scheme := newSLOReportTestClient(t, report, newReportTestSLO("checkout", "0.999", nil))
+	r := &SLOReportReconciler{Client: c, Scheme: scheme}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	updated := &oskov1alpha1.SLOReport{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	require.Len(t, updated.Status.Results, 1)
+	assert.Contains(t, updated.Status.Results[0].Error, "failed to get Datasource mimir")
+	degraded := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, utils.ReasonQueryFailed, degraded.Reason)
+	assert.Equal(t, "1 of 1 SLOs could not be reported on", degraded.Message)
+}
+
+func TestSLOReportReconcilerRejectsInvalidSchedule(t *testing.T) {
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default", Generation: 1},
+		Spec:       oskov1alpha1.SLOReportSpec{Schedule: "every month"},
+	}
+	c, scheme := newSLOReportTestClient(t, report)
+	r := &SLOReportReconciler{Client: c, Scheme: scheme}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.Error(t, err)
+
+	updated := &oskov1alpha1.SLOReport{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	ready := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, utils.ReasonInvalidSpec, ready.Reason)
+	assert.Empty(t, updated.Status.Results)
+}
+
+func TestSLOReportReconcilerRefusesUnmanagedConfigMap(t *testing.T) {
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default", Generation: 1},
+		Spec:       oskov1alpha1.SLOReportSpec{Schedule: "@daily", Output: &oskov1alpha1.SLOReportOutput{}},
+	}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default"},
+		Data:       map[string]string{"settings": "keep"},
+	}
+	c, scheme := newSLOReportTestClient(t, report, existing)
+	r := &SLOReportReconciler{Client: c, Scheme: scheme}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	var reconcileErr *errors.ReconcileError
+	require.ErrorAs(t, err, &reconcileErr)
+	assert.Equal(t, errors.ErrPermanent, reconcileErr.Type)
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, configMap))
+	assert.Equal(t, map[string]string{"settings": "keep"}, configMap.Data)
+	assert.Empty(t, configMap.OwnerReferences)
+}
+
+func TestSLOReportReconcilerLimitsNamespaces(t *testing.T) {
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: "default", Generation: 1},
+		Spec:       oskov1alpha1.SLOReportSpec{Schedule: "@daily", Namespaces: []string{"default", "payments"}},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}}
+
+	c, scheme := newSLOReportTestClient(t, report)
+	r := &SLOReportReconciler{Client: c, Scheme: scheme}
+	_, err := r.Reconcile(context.Background(), req)
+	require.Error(t, err)
+	updated := &oskov1alpha1.SLOReport{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, updated))
+	ready := apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, utils.ReasonInvalidSpec, ready.Reason)
+	assert.Contains(t, ready.Message, "payments")
+
+	cfg := config.Default()
+	cfg.SLOReportCrossNamespace = true
+	c, scheme = newSLOReportTestClient(t, report.DeepCopy())
+	r = &SLOReportReconciler{Client: c, Scheme: scheme, Config: config.NewStore(cfg)}
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+}
@@ -10,6 +10,7 @@ var (
 	ErrPermanent          = errors.New("permanent error")
 	ErrDependencyNotReady = errors.New("dependency not ready")
 	ErrInvalidTarget      = errors.New("invalid SLO target")
+	ErrInvalidRule        = errors.New("invalid rule")
 )
 
 type ReconcileError struct {
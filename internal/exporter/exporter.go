@@ -0,0 +1,333 @@
+// Package exporter renders the OpenSLO objects of a namespace as a bundle of OpenSLO specification documents,
+// with the openslo/v1 apiVersion and without the metadata and fields that only make sense in Kubernetes.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/helpers"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// APIVersion is the apiVersion of the OpenSLO specification
+const APIVersion = "openslo/v1"
+
+// How the indicators of the SLOs are exported
+const (
+	// IndicatorsReference exports every indicator as an SLI the SLO references
+	IndicatorsReference = "reference"
+	// IndicatorsInline inlines the SLIs into the SLOs referencing them
+	IndicatorsInline = "inline"
+)
+
+// Indicators are the valid ways to export indicators
+var Indicators = []string{IndicatorsReference, IndicatorsInline}
+
+// Objects are the objects of a namespace to export
+type Objects struct {
+	SLOs          []openslov1.SLO
+	SLIs          []openslov1.SLI
+	Datasources   []openslov1.Datasource
+	AlertPolicies []openslov1.AlertPolicy
+}
+
+// List lists the objects to export of a namespace, of all namespaces for metav1.NamespaceAll
+func List(ctx context.Context, c client.Reader, namespace string) (*Objects, error) {
+	var (
+		slos          openslov1.SLOList
+		slis          openslov1.SLIList
+		datasources   openslov1.DatasourceList
+		alertPolicies openslov1.AlertPolicyList
+	)
+	for _, list := range []client.ObjectList{&slos, &slis, &datasources, &alertPolicies} {
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+	}
+	return &Objects{SLOs: slos.Items, SLIs: slis.Items, Datasources: datasources.Items, AlertPolicies: alertPolicies.Items}, nil
+}
+
+// Namespaces returns the sorted namespaces of the objects
+func (o *Objects) Namespaces() []string {
+	var namespaces []string
+	add := func(namespace string) {
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	for _, slo := range o.SLOs {
+		add(slo.Namespace)
+	}
+	for _, sli := range o.SLIs {
+		add(sli.Namespace)
+	}
+	for _, ds := range o.Datasources {
+		add(ds.Namespace)
+	}
+	for _, policy := range o.AlertPolicies {
+		add(policy.Namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// InNamespace returns the objects of a namespace
+func (o *Objects) InNamespace(namespace string) *Objects {
+	in := &Objects{}
+	for _, slo := range o.SLOs {
+		if slo.Namespace == namespace {
+			in.SLOs = append(in.SLOs, slo)
+		}
+	}
+	for _, sli := range o.SLIs {
+		if sli.Namespace == namespace {
+			in.SLIs = append(in.SLIs, sli)
+		}
+	}
+	for _, ds := range o.Datasources {
+		if ds.Namespace == namespace {
+			in.Datasources = append(in.Datasources, ds)
+		}
+	}
+	for _, policy := range o.AlertPolicies {
+		if policy.Namespace == namespace {
+			in.AlertPolicies = append(in.AlertPolicies, policy)
+		}
+	}
+	return in
+}
+
+// Document is an OpenSLO document of a bundle
+type Document map[string]any
+
+// Bundle converts the objects into OpenSLO documents, ordered by kind and name.
+// SLIs the operator created for inline indicators are not exported themselves, the indicators of the SLOs are
+// either all exported as SLIs the SLOs reference or the SLIs an SLO references are inlined into it.
+func Bundle(objs *Objects, indicators string) ([]Document, error) {
+	if !slices.Contains(Indicators, indicators) {
+		return nil, fmt.Errorf("indicators must be one of %v, got %q", Indicators, indicators)
+	}
+
+	var (
+		docs     []Document
+		slis     []openslov1.SLI
+		sliNames = map[string]bool{}
+		inlined  = map[string]bool{}
+	)
+	for _, sli := range objs.SLIs {
+		if ownedBySLO(&sli) {
+			continue
+		}
+		slis = append(slis, sli)
+		sliNames[sli.Name] = true
+	}
+
+	for _, ds := range sortByName(objs.Datasources, func(ds openslov1.Datasource) string { return ds.Name }) {
+		doc, err := newDocument("DataSource", ds.Name, ds.Labels, ds.Annotations, &ds.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("Datasource %s: %w", ds.Name, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	var slos []openslov1.SLO
+	for _, slo := range sortByName(objs.SLOs, func(slo openslov1.SLO) string { return slo.Name }) {
+		slo := *slo.DeepCopy()
+		switch {
+		case indicators == IndicatorsReference && slo.Spec.Indicator != nil:
+			sli := helpers.NewInlineSLI(&slo)
+			if !sliNames[sli.Name] {
+				slis = append(slis, *sli)
+				sliNames[sli.Name] = true
+			}
+			slo.Spec.Indicator = nil
+			slo.Spec.IndicatorRef = &sli.Name
+		case indicators == IndicatorsInline && slo.Spec.IndicatorRef != nil:
+			for _, sli := range slis {
+				if sli.Name == *slo.Spec.IndicatorRef {
+					slo.Spec.Indicator = &openslov1.Indicator{Spec: sli.Spec}
+					slo.Spec.Indicator.Metadata.Name = sli.Name
+					slo.Spec.IndicatorRef = nil
+					inlined[sli.Name] = true
+					break
+				}
+			}
+		}
+		slos = append(slos, slo)
+	}
+
+	for _, sli := range sortByName(slis, func(sli openslov1.SLI) string { return sli.Name }) {
+		if inlined[sli.Name] {
+			continue
+		}
+		doc, err := newDocument("SLI", sli.Name, sli.Labels, sli.Annotations, &sli.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("SLI %s: %w", sli.Name, err)
+		}
+		docs = append(docs, doc)
+	}
+	for _, slo := range slos {
+		doc, err := newDocument("SLO", slo.Name, slo.Labels, slo.Annotations, &slo.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("SLO %s: %w", slo.Name, err)
+		}
+		docs = append(docs, doc)
+	}
+	for _, policy := range sortByName(objs.AlertPolicies, func(p openslov1.AlertPolicy) string { return p.Name }) {
+		doc, err := newDocument("AlertPolicy", policy.Name, policy.Labels, policy.Annotations, &policy.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("AlertPolicy %s: %w", policy.Name, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Write writes the documents as a YAML stream
+func Write(w io.Writer, docs []Document) error {
+	for i, doc := range docs {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render renders the bundle of the objects as a YAML stream
+func Render(objs *Objects, indicators string) ([]byte, error) {
+	docs, err := Bundle(objs, indicators)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, docs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newDocument builds the OpenSLO document of an object, keeping the labels and annotations that are not Kubernetes ones
+func newDocument(kind, name string, labels, annotations map[string]string, spec any) (Document, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]any{"name": name}
+	if labels := withoutKubernetesKeys(labels); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if annotations := withoutKubernetesKeys(annotations); len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+
+	prune(content)
+	normalizeSpec(content)
+	return Document{
+		"apiVersion": APIVersion,
+		"kind":       kind,
+		"metadata":   metadata,
+		"spec":       content,
+	}, nil
+}
+
+// normalizeSpec removes the fields the CRDs default that the specification has no use for, and sets the
+// counter of ratio metrics the specification requires
+func normalizeSpec(spec map[string]any) {
+	if objectives, ok := spec["objectives"].([]any); ok {
+		for _, objective := range objectives {
+			if objective, ok := objective.(map[string]any); ok && objective["compositeWeight"] == "0" {
+				delete(objective, "compositeWeight")
+			}
+		}
+	}
+	if indicator, ok := spec["indicator"].(map[string]any); ok {
+		if metadata, ok := indicator["metadata"].(map[string]any); ok {
+			indicator["metadata"] = map[string]any{"name": metadata["name"]}
+		}
+		if indicatorSpec, ok := indicator["spec"].(map[string]any); ok {
+			normalizeSpec(indicatorSpec)
+		}
+	}
+	if ratioMetric, ok := spec["ratioMetric"].(map[string]any); ok {
+		if _, ok := ratioMetric["counter"]; !ok {
+			ratioMetric["counter"] = false
+		}
+	}
+}
+
+// prune removes the empty values of the CRD types from a spec, which the specification does not allow
+func prune(m map[string]any) {
+	for key, value := range m {
+		if isEmpty(value) {
+			delete(m, key)
+		}
+	}
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]any:
+		prune(v)
+		return len(v) == 0
+	case []any:
+		for _, item := range v {
+			if item, ok := item.(map[string]any); ok {
+				prune(item)
+			}
+		}
+		return len(v) == 0
+	}
+	return false
+}
+
+// withoutKubernetesKeys returns the labels or annotations without the ones of Kubernetes and its tools,
+// like kubectl.kubernetes.io/last-applied-configuration
+func withoutKubernetesKeys(values map[string]string) map[string]string {
+	kept := map[string]string{}
+	for key, value := range values {
+		prefix, _, found := strings.Cut(key, "/")
+		if found && (strings.HasSuffix(prefix, "kubernetes.io") || strings.HasSuffix(prefix, "k8s.io")) {
+			continue
+		}
+		kept[key] = value
+	}
+	return kept
+}
+
+// ownedBySLO reports whether the SLI was created by the operator for the inline indicator of an SLO
+func ownedBySLO(sli *openslov1.SLI) bool {
+	for _, ref := range sli.OwnerReferences {
+		if ref.Kind == "SLO" && ref.APIVersion == openslov1.GroupVersion.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByName[T any](items []T, name func(T) string) []T {
+	sorted := slices.Clone(items)
+	sort.SliceStable(sorted, func(i, j int) bool { return name(sorted[i]) < name(sorted[j]) })
+	return sorted
+}
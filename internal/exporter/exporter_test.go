@@ -0,0 +1,155 @@
+package exporter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testObjects() *Objects {
+	sliName := "latency"
+	inline := &openslov1.Indicator{}
+	inline.Spec.RatioMetric.Counter = true
+	inline.Spec.RatioMetric.Good.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_requests_total{code="200"}`}}
+	inline.Spec.RatioMetric.Total.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_requests_total`}}
+
+	latency := openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "shop"}}
+	latency.Spec.RatioMetric.Good.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_request_duration_seconds_bucket{le="0.5"}`}}
+	latency.Spec.RatioMetric.Total.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_request_duration_seconds_count`}}
+
+	return &Objects{
+		SLOs: []openslov1.SLO{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "availability",
+					Namespace:         "shop",
+					UID:               "1234",
+					ResourceVersion:   "42",
+					CreationTimestamp: metav1.Now(),
+					Labels:            map[string]string{"label.osko.dev/team": "shop", "app.kubernetes.io/part-of": "shop"},
+					Annotations: map[string]string{
+						"osko.dev/datasourceRef":                           "mimir",
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+					},
+				},
+				Spec: openslov1.SLOSpec{
+					Service:         "checkout",
+					Indicator:       inline,
+					BudgetingMethod: "Occurrences",
+					Objectives:      []openslov1.ObjectivesSpec{{Target: "0.99"}},
+					TimeWindow:      []openslov1.TimeWindowSpec{{Duration: "28d", IsRolling: true}},
+				},
+				Status: openslov1.SLOStatus{CurrentSLO: "0.995"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "shop"},
+				Spec: openslov1.SLOSpec{
+					Service:         "checkout",
+					IndicatorRef:    &sliName,
+					BudgetingMethod: "Occurrences",
+					Objectives:      []openslov1.ObjectivesSpec{{Target: "0.95"}},
+					TimeWindow:      []openslov1.TimeWindowSpec{{Duration: "28d", IsRolling: true}},
+				},
+			},
+		},
+		SLIs: []openslov1.SLI{
+			latency,
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "availability-sli",
+					Namespace:       "shop",
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: openslov1.GroupVersion.String(), Kind: "SLO", Name: "availability"}},
+				},
+			},
+		},
+		Datasources: []openslov1.Datasource{{
+			ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "shop"},
+			Spec: openslov1.DatasourceSpec{
+				Type:              "mimir",
+				ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: "http://mimir:9009/", TargetTenant: "shop"},
+			},
+		}},
+		AlertPolicies: []openslov1.AlertPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "page", Namespace: "shop"},
+			Spec:       openslov1.AlertPolicySpec{Description: "Pages the on-call", AlertWhenBreaching: true},
+		}},
+	}
+}
+
+func TestBundle(t *testing.T) {
+	tests := []struct {
+		name       string
+		indicators string
+		want       []string
+	}{
+		{
+			name:       "reference",
+			indicators: IndicatorsReference,
+			want:       []string{"DataSource mimir", "SLI availability-sli", "SLI latency", "SLO availability", "SLO latency", "AlertPolicy page"},
+		},
+		{
+			name:       "inline",
+			indicators: IndicatorsInline,
+			want:       []string{"DataSource mimir", "SLO availability", "SLO latency", "AlertPolicy page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := Bundle(testObjects(), tt.indicators)
+			if err != nil {
+				t.Fatalf("Bundle() error = %v", err)
+			}
+			var got []string
+			for _, doc := range docs {
+				if doc["apiVersion"] != APIVersion {
+					t.Errorf("apiVersion = %v, want %s", doc["apiVersion"], APIVersion)
+				}
+				got = append(got, doc["kind"].(string)+" "+doc["metadata"].(map[string]any)["name"].(string))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bundle() = %v, want %v", got, tt.want)
+			}
+
+			for _, doc := range docs {
+				if doc["kind"] != "SLO" {
+					continue
+				}
+				spec := doc["spec"].(map[string]any)
+				_, hasIndicator := spec["indicator"]
+				_, hasRef := spec["indicatorRef"]
+				if hasIndicator == (tt.indicators == IndicatorsReference) || hasRef == (tt.indicators == IndicatorsInline) {
+					t.Errorf("SLO %v has indicator %v and indicatorRef %v", doc["metadata"], hasIndicator, hasRef)
+				}
+			}
+		})
+	}
+}
+
+func TestBundleStripsKubernetesMetadata(t *testing.T) {
+	bundle, err := Render(testObjects(), IndicatorsInline)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	out := string(bundle)
+	for _, unwanted := range []string{"namespace:", "uid:", "resourceVersion:", "creationTimestamp:", "status:", "kubernetes.io", "compositeWeight", "calendar:", "{}", "currentSLO"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("bundle contains %q:\n%s", unwanted, out)
+		}
+	}
+	for _, wanted := range []string{"label.osko.dev/team: shop", "osko.dev/datasourceRef: mimir", "counter: false", "name: latency", "targetTenant: shop"} {
+		if !strings.Contains(out, wanted) {
+			t.Errorf("bundle lacks %q:\n%s", wanted, out)
+		}
+	}
+}
+
+func TestBundleRejectsUnknownIndicators(t *testing.T) {
+	if _, err := Bundle(testObjects(), "embedded"); err == nil {
+		t.Error("expected an error for unknown indicators")
+	}
+}
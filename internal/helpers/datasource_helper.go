@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// ErrUnsupportedDatasource is returned for Datasource types osko cannot query
+var ErrUnsupportedDatasource = errors.New("unsupported datasource type")
+
+// SupportedDatasourceTypes are the Datasource types osko can query and write rules to
+var SupportedDatasourceTypes = []string{"mimir"}
+
+// CustomRoundTripper sets the tenant header on every request sent to a datasource
+type CustomRoundTripper struct {
+	Transport http.RoundTripper
+	TenantID  string
+}
+
+func (c *CustomRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Add("X-Scope-OrgId", c.TenantID)
+	return c.Transport.RoundTrip(req)
+}
+
+// NewDatasourceAPI builds a Prometheus API client for the query endpoint of the Datasource, scoped to its target tenant.
+// The address the client talks to is returned alongside for logging and events.
+func NewDatasourceAPI(ds *openslov1.Datasource) (v1.API, string, error) {
+	if !slices.Contains(SupportedDatasourceTypes, ds.Spec.Type) {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedDatasource, ds.Spec.Type)
+	}
+	datasourceAddress := ds.Spec.ConnectionDetails.Address + "/prometheus"
+
+	customRoundtripper := &CustomRoundTripper{
+		Transport: api.DefaultRoundTripper,
+		TenantID:  ds.Spec.ConnectionDetails.TargetTenant,
+	}
+
+	newDsClient, err := api.NewClient(api.Config{
+		Address:      datasourceAddress,
+		RoundTripper: customRoundtripper,
+	})
+	if err != nil {
+		return nil, datasourceAddress, err
+	}
+	return v1.NewAPI(newDsClient), datasourceAddress, nil
+}
@@ -2,19 +2,36 @@ package helpers
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/grafana/dskit/tenant"
 	mimirclient "github.com/grafana/mimir/pkg/mimirtool/client"
 	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
 	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	mimirRuleNamespace = "osko"
+	mimirRuleNamespace      = "osko"
+	sourceTenantsAnnotation = "osko.dev/sourceTenants"
+
+	// RulerFederationCondition is the Datasource condition reporting whether its ruler accepts federated rule groups
+	RulerFederationCondition = "RulerFederation"
+	// ErrFederationDisabled is the message the Mimir ruler rejects federated rule groups with when federation is off
+	ErrFederationDisabled = "rules federation is disabled"
 )
 
 type MimirClientConfig struct {
@@ -31,7 +48,7 @@ func (m *MimirClientConfig) NewMimirClient() (*mimirclient.MimirClient, error) {
 	)
 }
 
-func NewMimirRule(slo *openslov1.SLO, rule *monitoringv1.PrometheusRule, connectionDetails *oskov1alpha1.ConnectionDetails) (mimirRule *oskov1alpha1.MimirRule, err error) {
+func NewMimirRule(slo *openslov1.SLO, rule *monitoringv1.PrometheusRule, connectionDetails *oskov1alpha1.ConnectionDetails, cfg config.Config) (mimirRule *oskov1alpha1.MimirRule, err error) {
 	ownerRef := []metav1.OwnerReference{
 		*metav1.NewControllerRef(
 			slo,
@@ -47,7 +64,12 @@ func NewMimirRule(slo *openslov1.SLO, rule *monitoringv1.PrometheusRule, connect
 		OwnerReferences: ownerRef,
 	}
 
-	ruleGroups, err := NewMimirRuleGroups(rule, connectionDetails)
+	federated, err := FederatedConnectionDetails(slo, connectionDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleGroups, err := NewMimirRuleGroups(rule, federated, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +83,117 @@ func NewMimirRule(slo *openslov1.SLO, rule *monitoringv1.PrometheusRule, connect
 	return mimirRule, nil
 }
 
-func NewMimirRuleGroups(rule *monitoringv1.PrometheusRule, connectionDetails *oskov1alpha1.ConnectionDetails) ([]oskov1alpha1.RuleGroup, error) {
+// SourceTenants returns the tenants the SLI queries of an SLO span. The osko.dev/sourceTenants
+// annotation on the SLO takes precedence over the source tenants of the Datasource, an empty
+// annotation opts the SLO out of federation.
+func SourceTenants(slo *openslov1.SLO, connectionDetails *oskov1alpha1.ConnectionDetails) []string {
+	value, ok := slo.ObjectMeta.Annotations[sourceTenantsAnnotation]
+	if !ok {
+		return connectionDetails.SourceTenants
+	}
+	var tenants []string
+	for _, t := range strings.Split(value, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tenants = append(tenants, t)
+		}
+	}
+	return tenants
+}
+
+// FederatedConnectionDetails returns a copy of the connection details with the source tenants of the SLO applied
+func FederatedConnectionDetails(slo *openslov1.SLO, connectionDetails *oskov1alpha1.ConnectionDetails) (*oskov1alpha1.ConnectionDetails, error) {
+	federated := connectionDetails.DeepCopy()
+	federated.SourceTenants = SourceTenants(slo, connectionDetails)
+	if err := ValidateTenants(federated.TargetTenant, federated.SourceTenants); err != nil {
+		return nil, err
+	}
+	return federated, nil
+}
+
+// ValidateTenants checks that the tenants of a rule group would be accepted by the Mimir ruler.
+// Federated rule groups are stored under the target tenant, so one is required as soon as source tenants are set.
+func ValidateTenants(targetTenant string, sourceTenants []string) error {
+	var errs []error
+	if targetTenant != "" {
+		if err := validTenantID(targetTenant); err != nil {
+			errs = append(errs, fmt.Errorf("targetTenant: %w", err))
+		}
+	} else if len(sourceTenants) > 0 {
+		errs = append(errs, fmt.Errorf("targetTenant: required when sourceTenants are set"))
+	}
+
+	seen := make(map[string]bool, len(sourceTenants))
+	for i, t := range sourceTenants {
+		if err := validTenantID(t); err != nil {
+			errs = append(errs, fmt.Errorf("sourceTenants[%d]: %w", i, err))
+		}
+		if seen[t] {
+			errs = append(errs, fmt.Errorf("sourceTenants[%d]: duplicate tenant %q", i, t))
+		}
+		seen[t] = true
+	}
+	return stderrors.Join(errs...)
+}
+
+func validTenantID(id string) error {
+	switch id {
+	case "":
+		return fmt.Errorf("tenant ID must not be empty")
+	case ".", "..":
+		return fmt.Errorf("tenant ID must not be %q", id)
+	}
+	return tenant.ValidTenantID(id)
+}
+
+// mimirConfigClient reads the configuration of Mimir, a ruler that does not answer must not hold up a reconcile
+var mimirConfigClient = &http.Client{Timeout: 10 * time.Second}
+
+// RulerFederationEnabled reads the ruler configuration exposed on the /config endpoint of Mimir
+// and reports whether tenant federation is enabled for rule groups
+func RulerFederationEnabled(ctx context.Context, address, tenantID string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+"/config", nil)
+	if err != nil {
+		return false, err
+	}
+	if tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", tenantID)
+	}
+
+	resp, err := mimirConfigClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status reading Mimir config: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	var cfg struct {
+		Ruler struct {
+			TenantFederation struct {
+				Enabled bool `yaml:"enabled"`
+			} `yaml:"tenant_federation"`
+		} `yaml:"ruler"`
+	}
+	if err := yaml.Unmarshal(body, &cfg); err != nil {
+		return false, fmt.Errorf("could not parse Mimir config: %w", err)
+	}
+	return cfg.Ruler.TenantFederation.Enabled, nil
+}
+
+// NewMimirRuleGroups converts the groups of a PrometheusRule into Mimir rule groups. The evaluation delay,
+// which PrometheusRules cannot express, is resolved from the SLO annotations carried by the PrometheusRule.
+func NewMimirRuleGroups(rule *monitoringv1.PrometheusRule, connectionDetails *oskov1alpha1.ConnectionDetails, cfg config.Config) ([]oskov1alpha1.RuleGroup, error) {
+	settings, err := ruleGroupSettingsFor(rule.Annotations, cfg.RuleGroups)
+	if err != nil {
+		return nil, err
+	}
+
 	var ruleGroups []oskov1alpha1.RuleGroup
 	for _, group := range rule.Spec.Groups {
 		var mimirRules []oskov1alpha1.Rule
@@ -69,15 +201,36 @@ func NewMimirRuleGroups(rule *monitoringv1.PrometheusRule, connectionDetails *os
 			Name:          group.Name,
 			SourceTenants: connectionDetails.SourceTenants,
 		}
+		if group.Interval != nil {
+			interval, err := model.ParseDuration(string(*group.Interval))
+			if err != nil {
+				return nil, fmt.Errorf("invalid interval of rule group %s: %w", group.Name, err)
+			}
+			rg.Interval = interval
+		}
+		if group.Limit != nil {
+			rg.Limit = *group.Limit
+		}
+		if delay := settings[groupWindowClass(group)].EvaluationDelay; delay != 0 {
+			rg.EvaluationDelay = &delay
+		}
 		mimirRuleNode := oskov1alpha1.Rule{}
 
 		for _, r := range group.Rules {
 			if r.Record == "" && r.Alert != "" {
 				mimirRuleNode = oskov1alpha1.Rule{
-					Alert:  r.Alert,
-					Expr:   r.Expr.String(),
-					For:    r.For,
-					Labels: r.Labels,
+					Alert:       r.Alert,
+					Expr:        r.Expr.String(),
+					For:         r.For,
+					Labels:      r.Labels,
+					Annotations: r.Annotations,
+				}
+				if r.KeepFiringFor != nil {
+					keepFiringFor, err := model.ParseDuration(string(*r.KeepFiringFor))
+					if err != nil {
+						return nil, fmt.Errorf("invalid keep_firing_for of alert %s: %w", r.Alert, err)
+					}
+					mimirRuleNode.KeepFiringFor = keepFiringFor
 				}
 			} else {
 				mimirRuleNode = oskov1alpha1.Rule{
@@ -94,6 +247,42 @@ func NewMimirRuleGroups(rule *monitoringv1.PrometheusRule, connectionDetails *os
 	return ruleGroups, nil
 }
 
+// NewRuleFileGroup converts a MimirRule group into its rule file representation, as read by the Mimir ruler and promtool
+func NewRuleFileGroup(rule *oskov1alpha1.RuleGroup) (rulefmt.RuleGroup, error) {
+	var ruleNodes []rulefmt.RuleNode
+	for _, r := range rule.Rules {
+		node := rulefmt.RuleNode{
+			Expr:   yaml.Node{Kind: yaml.ScalarNode, Value: r.Expr},
+			Labels: r.Labels,
+		}
+		if r.Alert == "" {
+			node.Record = yaml.Node{Kind: yaml.ScalarNode, Value: r.Record}
+		} else {
+			node.Alert = yaml.Node{Kind: yaml.ScalarNode, Value: r.Alert}
+			if r.For != nil {
+				forDuration, err := model.ParseDuration(string(*r.For))
+				if err != nil {
+					return rulefmt.RuleGroup{}, fmt.Errorf("invalid for of alert %s: %w", r.Alert, err)
+				}
+				node.For = forDuration
+			}
+			node.KeepFiringFor = r.KeepFiringFor
+			node.Annotations = r.Annotations
+		}
+		ruleNodes = append(ruleNodes, node)
+	}
+
+	return rulefmt.RuleGroup{
+		Name:                          rule.Name,
+		Interval:                      rule.Interval,
+		EvaluationDelay:               rule.EvaluationDelay,
+		Limit:                         rule.Limit,
+		Rules:                         ruleNodes,
+		SourceTenants:                 rule.SourceTenants,
+		AlignEvaluationTimeOnInterval: rule.AlignEvaluationTimeOnInterval,
+	}, nil
+}
+
 func GetMimirRuleGroup(log logr.Logger, mimirClient *mimirclient.MimirClient, rule *monitoringv1.PrometheusRule) *rwrulefmt.RuleGroup {
 	mimirRuleGroup, err := mimirClient.GetRuleGroup(context.Background(), mimirRuleNamespace, rule.Name)
 	if err != nil {
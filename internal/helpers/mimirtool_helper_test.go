@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestSourceTenants(t *testing.T) {
+	connectionDetails := &oskov1alpha1.ConnectionDetails{
+		TargetTenant:  "infra",
+		SourceTenants: []string{"infra", "apps"},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{"datasource tenants", nil, []string{"infra", "apps"}},
+		{"slo override", map[string]string{sourceTenantsAnnotation: "billing, payments"}, []string{"billing", "payments"}},
+		{"empty override opts out", map[string]string{sourceTenantsAnnotation: ""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got := SourceTenants(slo, connectionDetails)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SourceTenants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTenants(t *testing.T) {
+	tests := []struct {
+		name          string
+		targetTenant  string
+		sourceTenants []string
+		wantErr       bool
+	}{
+		{"no federation", "infra", nil, false},
+		{"no tenants at all", "", nil, false},
+		{"federated", "infra", []string{"infra", "apps"}, false},
+		{"missing target tenant", "", []string{"apps"}, true},
+		{"invalid target tenant", "infra|apps", nil, true},
+		{"invalid source tenant", "infra", []string{"apps/prod"}, true},
+		{"empty source tenant", "infra", []string{""}, true},
+		{"dot source tenant", "infra", []string{".."}, true},
+		{"duplicate source tenant", "infra", []string{"apps", "apps"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTenants(tt.targetTenant, tt.sourceTenants)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTenants(%q, %v) error = %v, wantErr %v", tt.targetTenant, tt.sourceTenants, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMimirRuleAppliesSourceTenantOverride(t *testing.T) {
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-slo",
+			Namespace:   "default",
+			Annotations: map[string]string{sourceTenantsAnnotation: "billing,payments"},
+		},
+	}
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-slo", Namespace: "default"},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{{
+				Name:  "test-slo",
+				Rules: []monitoringv1.Rule{{Record: "osko_sli_total", Expr: intstr.FromString("vector(1)")}},
+			}},
+		},
+	}
+	connectionDetails := &oskov1alpha1.ConnectionDetails{
+		Address:       "http://mimir",
+		TargetTenant:  "infra",
+		SourceTenants: []string{"infra"},
+	}
+
+	mimirRule, err := NewMimirRule(slo, rule, connectionDetails, config.Default())
+	if err != nil {
+		t.Fatalf("NewMimirRule() error = %v", err)
+	}
+	if got := mimirRule.Spec.Groups[0].SourceTenants; !reflect.DeepEqual(got, []string{"billing", "payments"}) {
+		t.Errorf("group source tenants = %v, want the SLO override", got)
+	}
+	if got := mimirRule.Spec.ConnectionDetails.SourceTenants; !reflect.DeepEqual(got, []string{"infra"}) {
+		t.Errorf("connection details source tenants = %v, want the Datasource tenants", got)
+	}
+
+	slo.Annotations[sourceTenantsAnnotation] = "billing,billing"
+	if _, err := NewMimirRule(slo, rule, connectionDetails, config.Default()); err == nil {
+		t.Error("NewMimirRule() expected an error for duplicate source tenants")
+	}
+}
+
+func TestRulerFederationEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    bool
+		wantErr bool
+	}{
+		{"enabled", http.StatusOK, "ruler:\n  tenant_federation:\n    enabled: true\n", true, false},
+		{"disabled", http.StatusOK, "ruler:\n  tenant_federation:\n    enabled: false\n", false, false},
+		{"not configured", http.StatusOK, "server:\n  http_listen_port: 8080\n", false, false},
+		{"endpoint blocked", http.StatusForbidden, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/config" {
+					t.Errorf("unexpected request path %q", r.URL.Path)
+				}
+				if r.Header.Get("X-Scope-OrgID") != "infra" {
+					t.Errorf("expected the tenant header to be set")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := RulerFederationEnabled(context.Background(), server.URL+"/", "infra")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RulerFederationEnabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RulerFederationEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMimirRuleGroupsPreservesAlertFields(t *testing.T) {
+	forDuration := monitoringv1.Duration("5m")
+	keepFiringFor := monitoringv1.NonEmptyDuration("15m")
+	rule := &monitoringv1.PrometheusRule{
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{{
+				Name: "test-slo_slo_alert",
+				Rules: []monitoringv1.Rule{{
+					Alert:         "test-slo_alert_page_critical",
+					Expr:          intstr.FromString("osko_error_budget_burn_rate > 14.4"),
+					For:           &forDuration,
+					KeepFiringFor: &keepFiringFor,
+					Labels:        map[string]string{"severity": "P1"},
+					Annotations: map[string]string{
+						"summary":     "SLO Burn Rate Alert",
+						"description": "The burn rate of SLO test-slo is consuming error budget faster than acceptable.",
+					},
+				}},
+			}},
+		},
+	}
+
+	groups, err := NewMimirRuleGroups(rule, &oskov1alpha1.ConnectionDetails{}, config.Default())
+	if err != nil {
+		t.Fatalf("NewMimirRuleGroups() error = %v", err)
+	}
+
+	got := groups[0].Rules[0]
+	if !reflect.DeepEqual(got.Annotations, rule.Spec.Groups[0].Rules[0].Annotations) {
+		t.Errorf("annotations = %v, want %v", got.Annotations, rule.Spec.Groups[0].Rules[0].Annotations)
+	}
+	if got.KeepFiringFor.String() != "15m" {
+		t.Errorf("keep_firing_for = %s, want 15m", got.KeepFiringFor)
+	}
+	if got.For == nil || *got.For != forDuration {
+		t.Errorf("for = %v, want %s", got.For, forDuration)
+	}
+
+	invalid := monitoringv1.NonEmptyDuration("soon")
+	rule.Spec.Groups[0].Rules[0].KeepFiringFor = &invalid
+	if _, err := NewMimirRuleGroups(rule, &oskov1alpha1.ConnectionDetails{}, config.Default()); err == nil {
+		t.Error("NewMimirRuleGroups() expected an error for an invalid keep_firing_for")
+	}
+}
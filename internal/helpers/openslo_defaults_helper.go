@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/prometheus/common/model"
+)
+
+// SetSLODefaults materializes the settings an SLO runs with into the SLO: the time window, both target and
+// targetPercent of every objective, and the base window annotation. Durations are normalized to their largest
+// whole unit. Values that do not parse are left as they are for validation to reject.
+func SetSLODefaults(slo *openslov1.SLO, cfg config.Config) {
+	if len(slo.Spec.TimeWindow) == 0 {
+		slo.Spec.TimeWindow = []openslov1.TimeWindowSpec{{Duration: defaultSLOWindow, IsRolling: true}}
+	}
+	for i := range slo.Spec.TimeWindow {
+		tw := &slo.Spec.TimeWindow[i]
+		if tw.Duration == "" {
+			tw.Duration = defaultSLOWindow
+		}
+		tw.Duration = openslov1.Duration(NormalizeDuration(string(tw.Duration)))
+	}
+
+	for i := range slo.Spec.Objectives {
+		objective := &slo.Spec.Objectives[i]
+		switch {
+		case objective.Target == "" && objective.TargetPercent != "":
+			if target, ok := scaleDecimal(objective.TargetPercent, big.NewRat(1, 100)); ok {
+				objective.Target = target
+			}
+		case objective.TargetPercent == "" && objective.Target != "":
+			if percent, ok := scaleDecimal(objective.Target, big.NewRat(100, 1)); ok {
+				objective.TargetPercent = percent
+			}
+		}
+		if objective.TimeSliceWindow != "" {
+			objective.TimeSliceWindow = openslov1.Duration(NormalizeDuration(string(objective.TimeSliceWindow)))
+		}
+	}
+
+	if slo.Annotations == nil {
+		slo.Annotations = map[string]string{}
+	}
+	baseWindow := slo.Annotations["osko.dev/baseWindow"]
+	if baseWindow == "" {
+		baseWindow = model.Duration(cfg.DefaultBaseWindow).String()
+	} else if d, err := model.ParseDuration(baseWindow); err == nil {
+		baseWindow = d.String()
+	} else if d, err := time.ParseDuration(baseWindow); err == nil {
+		baseWindow = model.Duration(d).String()
+	}
+	slo.Annotations["osko.dev/baseWindow"] = baseWindow
+}
+
+// NormalizeDuration rewrites a duration in the largest unit out of days, hours, minutes and seconds that
+// expresses it exactly, for example 60m as 1h and 48h as 2d. Durations that do not parse are returned as they are.
+func NormalizeDuration(value string) string {
+	d, err := model.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return value
+	}
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	} {
+		if time.Duration(d)%unit.length == 0 {
+			return fmt.Sprintf("%d%s", time.Duration(d)/unit.length, unit.suffix)
+		}
+	}
+	return value
+}
+
+// scaleDecimal multiplies a decimal string by factor without floating point rounding
+func scaleDecimal(value string, factor *big.Rat) (string, bool) {
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return "", false
+	}
+	scaled := r.Mul(r, factor).FloatString(12)
+	scaled = strings.TrimRight(strings.TrimRight(scaled, "0"), ".")
+	return scaled, true
+}
@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+)
+
+func TestNormalizeDuration(t *testing.T) {
+	tests := map[string]string{
+		"28d":     "28d",
+		"672h":    "28d",
+		"60m":     "1h",
+		"90m":     "90m",
+		"3600s":   "1h",
+		"1h30m":   "90m",
+		"1w":      "7d",
+		"invalid": "invalid",
+	}
+	for value, want := range tests {
+		if got := NormalizeDuration(value); got != want {
+			t.Errorf("NormalizeDuration(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestSetSLODefaults(t *testing.T) {
+	t.Run("empty SLO", func(t *testing.T) {
+		slo := createTestSLO("0.999")
+		SetSLODefaults(slo, config.Default())
+
+		if len(slo.Spec.TimeWindow) != 1 || slo.Spec.TimeWindow[0].Duration != "28d" || !slo.Spec.TimeWindow[0].IsRolling {
+			t.Errorf("TimeWindow = %+v, want a rolling 28d window", slo.Spec.TimeWindow)
+		}
+		if got := slo.Spec.Objectives[0].TargetPercent; got != "99.9" {
+			t.Errorf("TargetPercent = %q, want 99.9", got)
+		}
+		if got := slo.Annotations["osko.dev/baseWindow"]; got != "5m" {
+			t.Errorf("base window = %q, want 5m", got)
+		}
+	})
+
+	t.Run("explicit settings", func(t *testing.T) {
+		slo := createTestSLO("")
+		slo.Annotations = map[string]string{"osko.dev/baseWindow": "1h0m0s"}
+		slo.Spec.TimeWindow = []openslov1.TimeWindowSpec{{Duration: "168h"}}
+		slo.Spec.Objectives[0].TargetPercent = "99.95"
+		slo.Spec.Objectives[0].TimeSliceWindow = "300s"
+		SetSLODefaults(slo, config.Default())
+
+		if got := slo.Spec.TimeWindow[0]; got.Duration != "7d" || got.IsRolling {
+			t.Errorf("TimeWindow = %+v, want the 7d window as it was", got)
+		}
+		if got := slo.Spec.Objectives[0].Target; got != "0.9995" {
+			t.Errorf("Target = %q, want 0.9995", got)
+		}
+		if got := slo.Spec.Objectives[0].TimeSliceWindow; got != "5m" {
+			t.Errorf("TimeSliceWindow = %q, want 5m", got)
+		}
+		if got := slo.Annotations["osko.dev/baseWindow"]; got != "1h" {
+			t.Errorf("base window = %q, want 1h", got)
+		}
+	})
+}
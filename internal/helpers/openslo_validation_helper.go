@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// SupportedBudgetingMethods are the budgeting methods the generated rules implement
+var SupportedBudgetingMethods = []string{"Occurrences"}
+
+// ValidateSLO checks an SLO for everything that would make its reconciliation fail
+func ValidateSLO(slo *openslov1.SLO) field.ErrorList {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+
+	errs = append(errs, validateIndicator(spec, slo.Spec.Indicator, slo.Spec.IndicatorRef, true)...)
+
+	if slo.Spec.BudgetingMethod != "" && !slices.Contains(SupportedBudgetingMethods, slo.Spec.BudgetingMethod) {
+		errs = append(errs, field.NotSupported(spec.Child("budgetingMethod"), slo.Spec.BudgetingMethod, SupportedBudgetingMethods))
+	}
+
+	for i, tw := range slo.Spec.TimeWindow {
+		if tw.Duration == "" {
+			continue
+		}
+		if _, err := model.ParseDuration(string(tw.Duration)); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("timeWindow").Index(i).Child("duration"), tw.Duration, err.Error()))
+		}
+	}
+
+	if len(slo.Spec.Objectives) == 0 {
+		errs = append(errs, field.Required(spec.Child("objectives"), "at least one objective is required"))
+	}
+	names := map[string]bool{}
+	for i, objective := range slo.Spec.Objectives {
+		path := spec.Child("objectives").Index(i)
+		if objective.DisplayName != "" {
+			if names[objective.DisplayName] {
+				errs = append(errs, field.Duplicate(path.Child("displayName"), objective.DisplayName))
+			}
+			names[objective.DisplayName] = true
+		}
+		errs = append(errs, validateObjectiveTarget(path, objective)...)
+		if objective.Indicator != nil || objective.IndicatorRef != nil {
+			errs = append(errs, validateIndicator(path, objective.Indicator, objective.IndicatorRef, false)...)
+		}
+	}
+	return errs
+}
+
+// ValidateSLI checks that every query of the SLI parses as PromQL
+func ValidateSLI(sli *openslov1.SLI) field.ErrorList {
+	return validateSLISpec(field.NewPath("spec"), &sli.Spec)
+}
+
+// ValidateDatasource checks that OSKO can connect to the Datasource
+func ValidateDatasource(ds *openslov1.Datasource) field.ErrorList {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	if !slices.Contains(SupportedDatasourceTypes, ds.Spec.Type) {
+		errs = append(errs, field.NotSupported(spec.Child("type"), ds.Spec.Type, SupportedDatasourceTypes))
+	}
+	if ds.Spec.ConnectionDetails.Address == "" {
+		errs = append(errs, field.Required(spec.Child("connectionDetails", "address"), "the address of the Datasource is required"))
+	}
+	return errs
+}
+
+// validateIndicator checks that exactly one of an inline indicator and an indicatorRef is set
+func validateIndicator(path *field.Path, indicator *openslov1.Indicator, indicatorRef *string, required bool) field.ErrorList {
+	var errs field.ErrorList
+	switch {
+	case indicator != nil && indicatorRef != nil:
+		errs = append(errs, field.Invalid(path.Child("indicatorRef"), *indicatorRef, "may not be set together with indicator"))
+	case indicator == nil && indicatorRef == nil:
+		if required {
+			errs = append(errs, field.Required(path.Child("indicatorRef"), "either indicator or indicatorRef is required"))
+		}
+	case indicatorRef != nil && *indicatorRef == "":
+		errs = append(errs, field.Required(path.Child("indicatorRef"), "the name of an SLI is required"))
+	}
+	if indicator != nil {
+		errs = append(errs, validateSLISpec(path.Child("indicator", "spec"), &indicator.Spec)...)
+	}
+	return errs
+}
+
+// validateObjectiveTarget checks that the target of the objective is in (0, 1) and agrees with its targetPercent
+func validateObjectiveTarget(path *field.Path, objective openslov1.ObjectivesSpec) field.ErrorList {
+	if objective.Target == "" && objective.TargetPercent == "" {
+		return field.ErrorList{field.Required(path.Child("target"), "either target or targetPercent is required")}
+	}
+
+	var errs field.ErrorList
+	target, percent := math.NaN(), math.NaN()
+	if objective.Target != "" {
+		value, err := parseTarget(objective.Target)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Child("target"), objective.Target, "must be a number"))
+		} else if err := validateTarget(value); err != nil {
+			errs = append(errs, field.Invalid(path.Child("target"), objective.Target, "must be greater than 0 and less than 1"))
+		} else {
+			target = value
+		}
+	}
+	if objective.TargetPercent != "" {
+		value, err := strconv.ParseFloat(objective.TargetPercent, 64)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Child("targetPercent"), objective.TargetPercent, "must be a number"))
+		} else if value <= 0 || value >= 100 {
+			errs = append(errs, field.Invalid(path.Child("targetPercent"), objective.TargetPercent, "must be greater than 0 and less than 100"))
+		} else {
+			percent = value
+		}
+	}
+	if !math.IsNaN(target) && !math.IsNaN(percent) && math.Abs(target*100-percent) > 1e-9 {
+		errs = append(errs, field.Invalid(path.Child("targetPercent"), objective.TargetPercent, "does not match target "+objective.Target))
+	}
+	return errs
+}
+
+// validateSLISpec parses every query of the SLI with the PromQL parser
+func validateSLISpec(path *field.Path, spec *openslov1.SLISpec) field.ErrorList {
+	queries := []struct {
+		path  *field.Path
+		query string
+	}{
+		{path.Child("ratioMetric", "good"), spec.RatioMetric.Good.MetricSource.Spec.Query},
+		{path.Child("ratioMetric", "bad"), spec.RatioMetric.Bad.MetricSource.Spec.Query},
+		{path.Child("ratioMetric", "total"), spec.RatioMetric.Total.MetricSource.Spec.Query},
+		{path.Child("ratioMetric", "raw"), spec.RatioMetric.Raw.MetricSource.Spec.Query},
+		{path.Child("thresholdMetric"), spec.ThresholdMetric.MetricSource.Spec.Query},
+	}
+
+	var errs field.ErrorList
+	for _, q := range queries {
+		if q.query == "" {
+			continue
+		}
+		if _, err := parser.ParseExpr(q.query); err != nil {
+			errs = append(errs, field.Invalid(q.path.Child("metricSource", "spec", "query"), q.query, err.Error()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,167 @@
+package helpers
+
+import (
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func errorFields(errs field.ErrorList) []string {
+	fields := make([]string, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func assertErrorFields(t *testing.T, errs field.ErrorList, want []string) {
+	t.Helper()
+	got := errorFields(errs)
+	if len(got) != len(want) {
+		t.Fatalf("errors = %v, want errors on %v", errs, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("error %d on %s, want %s: %v", i, got[i], want[i], errs[i])
+		}
+	}
+}
+
+func TestValidateSLO(t *testing.T) {
+	ref := "test-sli"
+	empty := ""
+	tests := []struct {
+		name   string
+		modify func(slo *openslov1.SLO)
+		want   []string
+	}{
+		{name: "valid", modify: func(slo *openslov1.SLO) {}},
+		{
+			name:   "target of 1",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Objectives[0].Target = "1.0" },
+			want:   []string{"spec.objectives[0].target"},
+		},
+		{
+			name:   "target not a number",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Objectives[0].Target = "99.9%" },
+			want:   []string{"spec.objectives[0].target"},
+		},
+		{
+			name: "target percent only",
+			modify: func(slo *openslov1.SLO) {
+				slo.Spec.Objectives[0].Target = ""
+				slo.Spec.Objectives[0].TargetPercent = "99.9"
+			},
+		},
+		{
+			name:   "mismatching target percent",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Objectives[0].TargetPercent = "99" },
+			want:   []string{"spec.objectives[0].targetPercent"},
+		},
+		{
+			name:   "no target",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Objectives[0].Target = "" },
+			want:   []string{"spec.objectives[0].target"},
+		},
+		{
+			name:   "no objectives",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Objectives = nil },
+			want:   []string{"spec.objectives"},
+		},
+		{
+			name: "duplicate objective names",
+			modify: func(slo *openslov1.SLO) {
+				slo.Spec.Objectives = []openslov1.ObjectivesSpec{
+					{DisplayName: "fast", Target: "0.99"},
+					{DisplayName: "fast", Target: "0.999"},
+				}
+			},
+			want: []string{"spec.objectives[1].displayName"},
+		},
+		{
+			name:   "missing indicator",
+			modify: func(slo *openslov1.SLO) { slo.Spec.IndicatorRef = nil },
+			want:   []string{"spec.indicatorRef"},
+		},
+		{
+			name:   "empty indicatorRef",
+			modify: func(slo *openslov1.SLO) { slo.Spec.IndicatorRef = &empty },
+			want:   []string{"spec.indicatorRef"},
+		},
+		{
+			name:   "indicator and indicatorRef",
+			modify: func(slo *openslov1.SLO) { slo.Spec.Indicator = &openslov1.Indicator{Spec: createTestSLI().Spec} },
+			want:   []string{"spec.indicatorRef"},
+		},
+		{
+			name: "inline indicator with invalid query",
+			modify: func(slo *openslov1.SLO) {
+				slo.Spec.IndicatorRef = nil
+				slo.Spec.Indicator = &openslov1.Indicator{Spec: createTestSLI().Spec}
+				slo.Spec.Indicator.Spec.RatioMetric.Good.MetricSource.Spec.Query = `sum(rate(http_requests_total[5m])`
+			},
+			want: []string{"spec.indicator.spec.ratioMetric.good.metricSource.spec.query"},
+		},
+		{
+			name:   "unsupported budgeting method",
+			modify: func(slo *openslov1.SLO) { slo.Spec.BudgetingMethod = "Timeslices" },
+			want:   []string{"spec.budgetingMethod"},
+		},
+		{
+			name:   "invalid time window",
+			modify: func(slo *openslov1.SLO) { slo.Spec.TimeWindow = []openslov1.TimeWindowSpec{{Duration: "4x"}} },
+			want:   []string{"spec.timeWindow[0].duration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := createTestSLO("0.999")
+			slo.Spec.IndicatorRef = &ref
+			slo.Spec.BudgetingMethod = "Occurrences"
+			tt.modify(slo)
+			assertErrorFields(t, ValidateSLO(slo), tt.want)
+		})
+	}
+}
+
+func TestValidateSLI(t *testing.T) {
+	sli := createTestSLI()
+	assertErrorFields(t, ValidateSLI(sli), nil)
+
+	sli.Spec.RatioMetric.Total.MetricSource.Spec.Query = `http_requests_total{`
+	sli.Spec.ThresholdMetric.MetricSource.Spec.Query = `rate(x[5m]) by (job)`
+	assertErrorFields(t, ValidateSLI(sli), []string{
+		"spec.ratioMetric.total.metricSource.spec.query",
+		"spec.thresholdMetric.metricSource.spec.query",
+	})
+}
+
+func TestValidateDatasource(t *testing.T) {
+	tests := []struct {
+		name string
+		spec openslov1.DatasourceSpec
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: openslov1.DatasourceSpec{Type: "mimir", ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: "http://mimir:9009"}},
+		},
+		{
+			name: "unknown type",
+			spec: openslov1.DatasourceSpec{Type: "graphite", ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: "http://graphite"}},
+			want: []string{"spec.type"},
+		},
+		{
+			name: "no address",
+			spec: openslov1.DatasourceSpec{Type: "mimir"},
+			want: []string{"spec.connectionDetails.address"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrorFields(t, ValidateDatasource(&openslov1.Datasource{Spec: tt.spec}), tt.want)
+		})
+	}
+}
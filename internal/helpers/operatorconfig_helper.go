@@ -0,0 +1,94 @@
+package helpers
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigFromSpec overlays the fields set in an OperatorConfig on the base configuration and validates the result
+func OperatorConfigFromSpec(base config.Config, spec oskov1alpha1.OperatorConfigSpec) (config.Config, error) {
+	cfg := base
+	setDuration(&cfg.MimirRuleRequeuePeriod, spec.MimirRuleRequeuePeriod)
+	setDuration(&cfg.DefaultBaseWindow, spec.DefaultBaseWindow)
+	setDuration(&cfg.AlertKeepFiringFor, spec.AlertKeepFiringFor)
+	setDuration(&cfg.SLOStatusRefreshPeriod, spec.SLOStatusRefreshPeriod)
+	setDuration(&cfg.SLIValidationPeriod, spec.SLIValidationPeriod)
+	if spec.AlertingTool != "" {
+		cfg.AlertingTool = spec.AlertingTool
+	}
+	if len(spec.SeverityMaps) > 0 {
+		// The maps of the base configuration are shared, never modify them in place
+		cfg.SeverityMaps = maps.Clone(base.SeverityMaps)
+		for tool, severities := range spec.SeverityMaps {
+			cfg.SeverityMaps[tool] = config.AlertToolSeverityMap{
+				config.PageCritical: alertSeverity(severities.PageCritical),
+				config.PageHigh:     alertSeverity(severities.PageHigh),
+				config.TicketHigh:   alertSeverity(severities.TicketHigh),
+				config.TicketMedium: alertSeverity(severities.TicketMedium),
+			}
+		}
+	}
+
+	numbers := []decimalSetting{
+		{"budgetExhaustedThreshold", spec.BudgetExhaustedThreshold, &cfg.BudgetExhaustedThreshold},
+	}
+	if rates := spec.AlertingBurnRates; rates != nil {
+		numbers = append(numbers,
+			decimalSetting{"alertingBurnRates.pageShortWindow", rates.PageShortWindow, &cfg.AlertingBurnRates.PageShortWindow},
+			decimalSetting{"alertingBurnRates.pageLongWindow", rates.PageLongWindow, &cfg.AlertingBurnRates.PageLongWindow},
+			decimalSetting{"alertingBurnRates.ticketShortWindow", rates.TicketShortWindow, &cfg.AlertingBurnRates.TicketShortWindow},
+			decimalSetting{"alertingBurnRates.ticketLongWindow", rates.TicketLongWindow, &cfg.AlertingBurnRates.TicketLongWindow},
+		)
+	}
+	for _, n := range numbers {
+		if n.value == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(n.value, 64)
+		if err != nil {
+			return base, fmt.Errorf("invalid %s %q: %w", n.name, n.value, err)
+		}
+		*n.target = value
+	}
+
+	if groups := spec.RuleGroups; groups != nil {
+		setRuleGroup(&cfg.RuleGroups.ShortWindow, groups.ShortWindow)
+		setRuleGroup(&cfg.RuleGroups.MediumWindow, groups.MediumWindow)
+		setRuleGroup(&cfg.RuleGroups.LongWindow, groups.LongWindow)
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		return base, err
+	}
+	return cfg, nil
+}
+
+// decimalSetting is a setting stored as a decimal string in the OperatorConfig
+type decimalSetting struct {
+	name   string
+	value  string
+	target *float64
+}
+
+func setDuration(target *time.Duration, value *metav1.Duration) {
+	if value != nil {
+		*target = value.Duration
+	}
+}
+
+func alertSeverity(value oskov1alpha1.OperatorConfigAlertSeverity) config.AlertSeverity {
+	return config.AlertSeverity{Severity: value.Severity, Labels: value.Labels, Annotations: value.Annotations}
+}
+
+func setRuleGroup(target *config.RuleGroupDefaults, value *oskov1alpha1.OperatorConfigRuleGroup) {
+	if value != nil {
+		setDuration(&target.Interval, value.Interval)
+		setDuration(&target.EvaluationDelay, value.EvaluationDelay)
+	}
+}
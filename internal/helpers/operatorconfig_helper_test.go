@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOperatorConfigFromSpec(t *testing.T) {
+	base := config.Default()
+	spec := oskov1alpha1.OperatorConfigSpec{
+		SLIValidationPeriod:      &metav1.Duration{Duration: 0},
+		BudgetExhaustedThreshold: "0.1",
+		AlertingBurnRates:        &oskov1alpha1.OperatorConfigBurnRates{TicketLongWindow: "1.5"},
+		RuleGroups: &oskov1alpha1.OperatorConfigRuleGroups{
+			LongWindow: &oskov1alpha1.OperatorConfigRuleGroup{EvaluationDelay: &metav1.Duration{Duration: time.Minute}},
+		},
+		SeverityMaps: map[string]oskov1alpha1.OperatorConfigSeverityMap{
+			"incidentio": {
+				PageCritical: oskov1alpha1.OperatorConfigAlertSeverity{Severity: "critical", Labels: map[string]string{"priority": "P0"}},
+				PageHigh:     oskov1alpha1.OperatorConfigAlertSeverity{Severity: "major"},
+				TicketHigh:   oskov1alpha1.OperatorConfigAlertSeverity{Severity: "minor"},
+				TicketMedium: oskov1alpha1.OperatorConfigAlertSeverity{Severity: "minor"},
+			},
+		},
+	}
+
+	cfg, err := OperatorConfigFromSpec(base, spec)
+	if err != nil {
+		t.Fatalf("OperatorConfigFromSpec() error = %v", err)
+	}
+	if cfg.SLIValidationPeriod != 0 {
+		t.Errorf("SLIValidationPeriod = %s, want 0s", cfg.SLIValidationPeriod)
+	}
+	if cfg.BudgetExhaustedThreshold != 0.1 {
+		t.Errorf("BudgetExhaustedThreshold = %v, want 0.1", cfg.BudgetExhaustedThreshold)
+	}
+	if cfg.AlertingBurnRates.TicketLongWindow != 1.5 || cfg.AlertingBurnRates.PageShortWindow != base.AlertingBurnRates.PageShortWindow {
+		t.Errorf("AlertingBurnRates = %+v, want only ticketLongWindow overridden", cfg.AlertingBurnRates)
+	}
+	if cfg.RuleGroups.LongWindow.EvaluationDelay != time.Minute || cfg.RuleGroups.LongWindow.Interval != base.RuleGroups.LongWindow.Interval {
+		t.Errorf("RuleGroups.LongWindow = %+v, want only the evaluation delay overridden", cfg.RuleGroups.LongWindow)
+	}
+	if got := cfg.SeveritiesFor("incidentio").GetSeverity(config.PageCritical); got.Severity != "critical" || got.Labels["priority"] != "P0" {
+		t.Errorf("incidentio page_critical = %+v, want critical with priority P0", got)
+	}
+	if _, exists := cfg.SeverityMaps["opsgenie"]; !exists {
+		t.Error("SeverityMaps should keep the built-in maps")
+	}
+	if _, exists := base.SeverityMaps["incidentio"]; exists {
+		t.Error("OperatorConfigFromSpec() modified the severity maps of the base configuration")
+	}
+}
+
+func TestOperatorConfigFromSpecRejectsInvalidValues(t *testing.T) {
+	tests := map[string]oskov1alpha1.OperatorConfigSpec{
+		"zero base window":     {DefaultBaseWindow: &metav1.Duration{}},
+		"threshold of one":     {BudgetExhaustedThreshold: "1"},
+		"unparsable burn rate": {AlertingBurnRates: &oskov1alpha1.OperatorConfigBurnRates{PageLongWindow: "six"}},
+		"zero burn rate":       {AlertingBurnRates: &oskov1alpha1.OperatorConfigBurnRates{PageLongWindow: "0"}},
+		"empty severity":       {SeverityMaps: map[string]oskov1alpha1.OperatorConfigSeverityMap{"incidentio": {}}},
+		"negative evaluation delay": {RuleGroups: &oskov1alpha1.OperatorConfigRuleGroups{
+			ShortWindow: &oskov1alpha1.OperatorConfigRuleGroup{EvaluationDelay: &metav1.Duration{Duration: -time.Minute}},
+		}},
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			base := config.Default()
+			cfg, err := OperatorConfigFromSpec(base, spec)
+			if err == nil {
+				t.Fatal("OperatorConfigFromSpec() expected an error")
+			}
+			if !reflect.DeepEqual(cfg, base) {
+				t.Error("OperatorConfigFromSpec() should return the base configuration on error")
+			}
+		})
+	}
+}
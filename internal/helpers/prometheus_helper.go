@@ -64,6 +64,8 @@ type MonitoringRuleSet struct {
 	GoodRule   monitoringv1.Rule
 	TotalRule  monitoringv1.Rule
 	BaseWindow string
+	// Config holds the operator settings the rules are generated with
+	Config config.Config
 }
 
 func mapToColonSeparatedString(labels map[string]string) string {
@@ -256,11 +258,7 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 
 	baseWindow := mrs.BaseWindow
 	log.V(1).Info("Starting SetupRules", "baseWindow", baseWindow)
-	extendedWindow := "28d"
-
-	if len(mrs.Slo.Spec.TimeWindow) > 0 && mrs.Slo.Spec.TimeWindow[0].Duration != "" {
-		extendedWindow = string(mrs.Slo.Spec.TimeWindow[0].Duration)
-	}
+	extendedWindow := SLOWindow(mrs.Slo)
 
 	if !mrs.isPrometheusSource() {
 		return []monitoringv1.RuleGroup{}, fmt.Errorf("unsupported metric source type")
@@ -275,6 +273,11 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 		return nil, err
 	}
 
+	settings, err := ruleGroupSettingsFor(mrs.Slo.ObjectMeta.Annotations, mrs.Config.RuleGroups)
+	if err != nil {
+		return nil, err
+	}
+
 	errorBudgetTarget := 1.0 - target
 	log.V(1).Info("SLO configuration", "target", target, "errorBudgetTarget", errorBudgetTarget)
 
@@ -354,17 +357,21 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 
 	sloName := mrs.Slo.Name
 	ruleGroups := []monitoringv1.RuleGroup{
-		{Name: fmt.Sprintf("%s_slo_target", sloName), Rules: rulesByType["targetRule"]},
-		{Name: fmt.Sprintf("%s_sli_good", sloName), Rules: rulesByType["goodRule"]},
-		{Name: fmt.Sprintf("%s_sli_total", sloName), Rules: rulesByType["totalRule"]},
-		{Name: fmt.Sprintf("%s_sli_measurement", sloName), Rules: rulesByType["sliMeasurement"]},
-		{Name: fmt.Sprintf("%s_error_budget_ratio", sloName), Rules: rulesByType["errorBudgetRatio"]},
-		{Name: fmt.Sprintf("%s_burn_rate", sloName), Rules: rulesByType["burnRate"]},
+		newRuleGroup(fmt.Sprintf("%s_slo_target", sloName), rulesByType["targetRule"], settings[classifyWindow(baseWindow)]),
 	}
+	ruleGroups = append(ruleGroups, splitByWindowClass(sloName, "sli_good", rulesByType["goodRule"], settings)...)
+	ruleGroups = append(ruleGroups, splitByWindowClass(sloName, "sli_total", rulesByType["totalRule"], settings)...)
+	ruleGroups = append(ruleGroups, splitByWindowClass(sloName, "sli_measurement", rulesByType["sliMeasurement"], settings)...)
+	ruleGroups = append(ruleGroups, splitByWindowClass(sloName, "error_budget_ratio", rulesByType["errorBudgetRatio"], settings)...)
+	ruleGroups = append(ruleGroups, splitByWindowClass(sloName, "burn_rate", rulesByType["burnRate"], settings)...)
 
 	log.V(1).Info("Magic alerting", "SLO", sloName, "enabled", mrs.Slo.ObjectMeta.Annotations["osko.dev/magicAlerting"])
 	if mrs.Slo.ObjectMeta.Annotations["osko.dev/magicAlerting"] == "true" {
 		duration := monitoringv1.Duration("5m")
+		keepFiringFor, err := mrs.keepFiringFor()
+		if err != nil {
+			return nil, err
+		}
 		var alertRules []monitoringv1.Rule
 
 		burnRateWindows := mrs.getBurnRateWindows(alertingBurnRates)
@@ -375,6 +382,7 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 					burnRateWindows,
 					errorBudgetTarget,
 					&duration,
+					keepFiringFor,
 					config.PageCritical,
 				),
 			)
@@ -386,6 +394,7 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 					burnRateWindows,
 					errorBudgetTarget,
 					&duration,
+					keepFiringFor,
 					config.PageHigh,
 				),
 			)
@@ -397,6 +406,7 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 					burnRateWindows,
 					errorBudgetTarget,
 					&duration,
+					keepFiringFor,
 					config.TicketHigh,
 				),
 			)
@@ -408,15 +418,13 @@ func (mrs *MonitoringRuleSet) SetupRules() ([]monitoringv1.RuleGroup, error) {
 					burnRateWindows,
 					errorBudgetTarget,
 					&duration,
+					keepFiringFor,
 					config.TicketMedium,
 				),
 			)
 		}
 
-		ruleGroups = append(ruleGroups, monitoringv1.RuleGroup{
-			Name:  fmt.Sprintf("%s_slo_alert", sloName),
-			Rules: alertRules,
-		})
+		ruleGroups = append(ruleGroups, newRuleGroup(fmt.Sprintf("%s_slo_alert", sloName), alertRules, settings[shortWindowClass]))
 	}
 	return ruleGroups, nil
 }
@@ -448,6 +456,24 @@ func (mrs *MonitoringRuleSet) getBurnRateWindows(burnRates []monitoringv1.Rule)
 	return &burnRateWindows{windows: windows}
 }
 
+// keepFiringFor resolves how long burn rate alerts keep firing after their condition cleared, from the
+// osko.dev/keepFiringFor annotation or the configured default. A zero duration disables it.
+func (mrs *MonitoringRuleSet) keepFiringFor() (*monitoringv1.NonEmptyDuration, error) {
+	keepFiringFor := model.Duration(mrs.Config.AlertKeepFiringFor)
+	if value, ok := mrs.Slo.ObjectMeta.Annotations["osko.dev/keepFiringFor"]; ok {
+		d, err := model.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid osko.dev/keepFiringFor annotation: %w", err)
+		}
+		keepFiringFor = d
+	}
+	if keepFiringFor == 0 {
+		return nil, nil
+	}
+	d := monitoringv1.NonEmptyDuration(keepFiringFor.String())
+	return &d, nil
+}
+
 func isValidRule(rule monitoringv1.Rule) bool {
 	return rule.Record != "" && rule.Expr.String() != ""
 }
@@ -456,6 +482,7 @@ func (mrs *MonitoringRuleSet) createMultiBurnRateAlert(
 	brw *burnRateWindows,
 	errorBudgetTarget float64,
 	duration *monitoringv1.Duration,
+	keepFiringFor *monitoringv1.NonEmptyDuration,
 	sreSeverity config.SREAlertSeverity,
 ) monitoringv1.Rule {
 	log := ctrllog.FromContext(context.Background())
@@ -467,23 +494,23 @@ func (mrs *MonitoringRuleSet) createMultiBurnRateAlert(
 	case config.PageCritical:
 		shortWindow = brw.get("5m")
 		longWindow = brw.get("1h")
-		shortThreshold = config.Cfg.AlertingBurnRates.PageShortWindow
-		longThreshold = config.Cfg.AlertingBurnRates.PageShortWindow
+		shortThreshold = mrs.Config.AlertingBurnRates.PageShortWindow
+		longThreshold = mrs.Config.AlertingBurnRates.PageShortWindow
 	case config.PageHigh:
 		shortWindow = brw.get("30m")
 		longWindow = brw.get("6h")
-		shortThreshold = config.Cfg.AlertingBurnRates.PageLongWindow
-		longThreshold = config.Cfg.AlertingBurnRates.PageLongWindow
+		shortThreshold = mrs.Config.AlertingBurnRates.PageLongWindow
+		longThreshold = mrs.Config.AlertingBurnRates.PageLongWindow
 	case config.TicketHigh:
 		shortWindow = brw.get("2h")
 		longWindow = brw.get("24h")
-		shortThreshold = config.Cfg.AlertingBurnRates.TicketShortWindow
-		longThreshold = config.Cfg.AlertingBurnRates.TicketShortWindow
+		shortThreshold = mrs.Config.AlertingBurnRates.TicketShortWindow
+		longThreshold = mrs.Config.AlertingBurnRates.TicketShortWindow
 	case config.TicketMedium:
 		shortWindow = brw.get("6h")
 		longWindow = brw.get("3d")
-		shortThreshold = config.Cfg.AlertingBurnRates.TicketLongWindow
-		longThreshold = config.Cfg.AlertingBurnRates.TicketLongWindow
+		shortThreshold = mrs.Config.AlertingBurnRates.TicketLongWindow
+		longThreshold = mrs.Config.AlertingBurnRates.TicketLongWindow
 	}
 
 	if !isValidRule(shortWindow) || !isValidRule(longWindow) {
@@ -497,37 +524,39 @@ func (mrs *MonitoringRuleSet) createMultiBurnRateAlert(
 	shortLabels := mapToColonSeparatedString(shortWindow.Labels)
 	longLabels := mapToColonSeparatedString(longWindow.Labels)
 
+	// Both burn rates differ in their window label only, which the and operator must not match on
 	alertExpression := fmt.Sprintf(
-		"(%s{%s} > %.1f and %s{%s} > %.1f)",
+		"(%s{%s} > %.1f and ignoring (window) %s{%s} > %.1f)",
 		shortWindow.Record, shortLabels, shortThreshold,
 		longWindow.Record, longLabels, longThreshold,
 	)
 
 	alertingTool := mrs.Slo.ObjectMeta.Annotations["osko.dev/alertingTool"]
 	if alertingTool == "" {
-		alertingTool = config.Cfg.AlertingTool
+		alertingTool = mrs.Config.AlertingTool
 	}
 
-	severities := config.AlertSeveritiesByTool(alertingTool)
-	toolSeverity := severities.GetSeverity(sreSeverity)
+	toolSeverity := mrs.Config.SeveritiesFor(alertingTool).GetSeverity(sreSeverity)
 
-	log.V(1).Info("Alerting rule", "sreSeverity", sreSeverity, "toolSeverity", toolSeverity)
+	log.V(1).Info("Alerting rule", "sreSeverity", sreSeverity, "toolSeverity", toolSeverity.Severity)
 
+	// The extra labels and annotations of the severity map cannot override the ones set here, see config.Validate
 	return monitoringv1.Rule{
-		Alert: fmt.Sprintf("%s_alert_%s", mrs.Slo.Name, sreSeverity),
-		Expr:  intstr.FromString(alertExpression),
-		For:   duration,
-		Labels: map[string]string{
-			"severity":     toolSeverity,
+		Alert:         fmt.Sprintf("%s_alert_%s", mrs.Slo.Name, sreSeverity),
+		Expr:          intstr.FromString(alertExpression),
+		For:           duration,
+		KeepFiringFor: keepFiringFor,
+		Labels: mergeLabels(toolSeverity.Labels, map[string]string{
+			"severity":     toolSeverity.Severity,
 			"slo_name":     mrs.Slo.Name,
 			"sli_name":     mrs.Sli.Name,
 			"short_window": shortWindow.Labels["window"],
 			"long_window":  longWindow.Labels["window"],
-		},
-		Annotations: map[string]string{
+		}),
+		Annotations: mergeLabels(toolSeverity.Annotations, map[string]string{
 			"summary":     "SLO Burn Rate Alert",
 			"description": fmt.Sprintf("The burn rate of SLO %s is consuming error budget faster than acceptable. Short window: %s, Long window: %s", mrs.Slo.Name, shortWindow.Labels["window"], longWindow.Labels["window"]),
-		},
+		}),
 	}
 }
 
@@ -535,16 +564,12 @@ func CreateAlertingRule() (*monitoringv1.PrometheusRule, error) {
 	return nil, nil
 }
 
-func CreatePrometheusRule(slo *openslov1.SLO, sli *openslov1.SLI) (*monitoringv1.PrometheusRule, error) {
-	baseWindow := model.Duration(config.Cfg.DefaultBaseWindow).String()
-	if slo.ObjectMeta.Annotations["osko.dev/baseWindow"] != "" {
-		baseWindow = slo.ObjectMeta.Annotations["osko.dev/baseWindow"]
-	}
-
+func CreatePrometheusRule(slo *openslov1.SLO, sli *openslov1.SLI, cfg config.Config) (*monitoringv1.PrometheusRule, error) {
 	mrs := &MonitoringRuleSet{
 		Slo:        slo,
 		Sli:        sli,
-		BaseWindow: baseWindow,
+		BaseWindow: SLOBaseWindow(slo, cfg),
+		Config:     cfg,
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -580,5 +605,9 @@ func CreatePrometheusRule(slo *openslov1.SLO, sli *openslov1.SLI) (*monitoringv1
 		Groups: ruleGroups,
 	}
 
+	if err := ValidatePrometheusRule(&prometheusRule); err != nil {
+		return nil, err
+	}
+
 	return &prometheusRule, nil
 }
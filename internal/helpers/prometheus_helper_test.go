@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -10,10 +11,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func init() {
-	config.NewConfig()
-}
-
 func TestValidateTarget(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -191,6 +188,7 @@ func TestSetupRules_TargetValidation(t *testing.T) {
 				Slo:        createTestSLO(tt.target),
 				Sli:        createTestSLI(),
 				BaseWindow: "5m",
+				Config:     config.Default(),
 			}
 
 			_, err := mrs.SetupRules()
@@ -206,6 +204,7 @@ func TestSetupRules_BurnRateFormula(t *testing.T) {
 		Slo:        createTestSLO("0.999"),
 		Sli:        createTestSLI(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -240,6 +239,7 @@ func TestSetupRules_ExtendedWindowUsesIndependentRate(t *testing.T) {
 		Slo:        createTestSLO("0.999"),
 		Sli:        createTestSLI(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -262,7 +262,7 @@ func TestSetupRules_ExtendedWindowUsesIndependentRate(t *testing.T) {
 }
 
 func TestCreatePrometheusRule(t *testing.T) {
-	rule, err := CreatePrometheusRule(createTestSLO("0.999"), createTestSLI())
+	rule, err := CreatePrometheusRule(createTestSLO("0.999"), createTestSLI(), config.Default())
 	if err != nil {
 		t.Fatalf("CreatePrometheusRule() error = %v", err)
 	}
@@ -323,6 +323,7 @@ func TestSetupRules_MagicAlerting_WindowPairs(t *testing.T) {
 		Slo:        slo,
 		Sli:        createTestSLI(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -376,6 +377,7 @@ func TestSetupRules_MagicAlerting_CorrectWindowPairs(t *testing.T) {
 		Slo:        slo,
 		Sli:        createTestSLI(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -423,11 +425,57 @@ func TestSetupRules_MagicAlerting_CorrectWindowPairs(t *testing.T) {
 	}
 }
 
+func TestSetupRules_MagicAlerting_ExpressionIgnoresWindow(t *testing.T) {
+	slo := createTestSLO("0.999")
+	slo.Annotations = map[string]string{
+		"osko.dev/magicAlerting": "true",
+	}
+
+	mrs := &MonitoringRuleSet{
+		Slo:        slo,
+		Sli:        createTestSLI(),
+		BaseWindow: "5m",
+		Config:     config.Default(),
+	}
+
+	ruleGroups, err := mrs.SetupRules()
+	if err != nil {
+		t.Fatalf("SetupRules() error = %v", err)
+	}
+
+	// The short and long window burn rates only differ in their window label, without ignoring it the
+	// and operator matches no series and the alert never fires
+	burnRate := func(window string) string {
+		return fmt.Sprintf(`osko_error_budget_burn_rate{namespace="default", service="test-service", sli_name="test-sli", slo_name="test-slo", window="%s"}`, window)
+	}
+	want := map[string]string{
+		"test-slo_alert_page_critical": fmt.Sprintf("(%s > 14.4 and ignoring (window) %s > 14.4)", burnRate("5m"), burnRate("1h")),
+		"test-slo_alert_page_high":     fmt.Sprintf("(%s > 6.0 and ignoring (window) %s > 6.0)", burnRate("30m"), burnRate("6h")),
+		"test-slo_alert_ticket_high":   fmt.Sprintf("(%s > 3.0 and ignoring (window) %s > 3.0)", burnRate("2h"), burnRate("24h")),
+		"test-slo_alert_ticket_medium": fmt.Sprintf("(%s > 1.0 and ignoring (window) %s > 1.0)", burnRate("6h"), burnRate("3d")),
+	}
+
+	got := map[string]string{}
+	for _, rg := range ruleGroups {
+		for _, rule := range rg.Rules {
+			if rule.Alert != "" {
+				got[rule.Alert] = rule.Expr.String()
+			}
+		}
+	}
+	for alert, expr := range want {
+		if got[alert] != expr {
+			t.Errorf("%s expression = %s, want %s", alert, got[alert], expr)
+		}
+	}
+}
+
 func TestSetupRules_BadMetric(t *testing.T) {
 	mrs := &MonitoringRuleSet{
 		Slo:        createTestSLO("0.999"),
 		Sli:        createTestSLIWithBad(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -465,6 +513,7 @@ func TestSetupRules_GaugeMetricsUseAvgOverTime(t *testing.T) {
 		Slo:        createTestSLO("0.999"),
 		Sli:        createTestSLIGauge(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -498,6 +547,7 @@ func TestSetupRules_CounterMetricsUseRate(t *testing.T) {
 		Slo:        createTestSLO("0.999"),
 		Sli:        createTestSLI(),
 		BaseWindow: "5m",
+		Config:     config.Default(),
 	}
 
 	ruleGroups, err := mrs.SetupRules()
@@ -525,3 +575,110 @@ func TestSetupRules_CounterMetricsUseRate(t *testing.T) {
 		}
 	}
 }
+
+func TestSetupRules_KeepFiringFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       string
+		wantErr    bool
+	}{
+		{"not configured", "", "", false},
+		{"configured", "10m", "10m", false},
+		{"disabled", "0s", "", false},
+		{"invalid", "a while", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := createTestSLO("0.999")
+			slo.Annotations = map[string]string{"osko.dev/magicAlerting": "true"}
+			if tt.annotation != "" {
+				slo.Annotations["osko.dev/keepFiringFor"] = tt.annotation
+			}
+			mrs := &MonitoringRuleSet{
+				Slo:        slo,
+				Sli:        createTestSLI(),
+				BaseWindow: "5m",
+				Config:     config.Default(),
+			}
+
+			ruleGroups, err := mrs.SetupRules()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetupRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, rg := range ruleGroups {
+				for _, rule := range rg.Rules {
+					if rule.Alert == "" {
+						continue
+					}
+					got := ""
+					if rule.KeepFiringFor != nil {
+						got = string(*rule.KeepFiringFor)
+					}
+					if got != tt.want {
+						t.Errorf("alert %s keep_firing_for = %q, want %q", rule.Alert, got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSetupRules_SeverityMaps(t *testing.T) {
+	cfg := config.Default()
+	cfg.SeverityMaps["incidentio"] = config.AlertToolSeverityMap{
+		config.PageCritical: {Severity: "critical", Labels: map[string]string{"priority": "P0"}, Annotations: map[string]string{"team_channel": "#shop-oncall"}},
+		config.PageHigh:     {Severity: "major"},
+		config.TicketHigh:   {Severity: "minor"},
+		config.TicketMedium: {Severity: "minor"},
+	}
+
+	tests := []struct {
+		name         string
+		tool         string
+		wantSeverity string
+		wantPriority string
+		wantChannel  string
+	}{
+		{name: "configured map", tool: "incidentio", wantSeverity: "critical", wantPriority: "P0", wantChannel: "#shop-oncall"},
+		{name: "built-in map", tool: "pagerduty", wantSeverity: "SEV_1"},
+		{name: "unknown tool", tool: "carrier-pigeon", wantSeverity: "critical"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := createTestSLO("0.999")
+			slo.Annotations = map[string]string{"osko.dev/magicAlerting": "true", "osko.dev/alertingTool": tt.tool}
+			mrs := &MonitoringRuleSet{Slo: slo, Sli: createTestSLI(), BaseWindow: "5m", Config: cfg}
+
+			ruleGroups, err := mrs.SetupRules()
+			if err != nil {
+				t.Fatalf("SetupRules() error = %v", err)
+			}
+			var critical *monitoringv1.Rule
+			for _, rg := range ruleGroups {
+				for i, rule := range rg.Rules {
+					if strings.HasSuffix(rule.Alert, string(config.PageCritical)) {
+						critical = &rg.Rules[i]
+					}
+				}
+			}
+			if critical == nil {
+				t.Fatal("Expected a page_critical alert")
+			}
+			if got := critical.Labels["severity"]; got != tt.wantSeverity {
+				t.Errorf("severity = %q, want %q", got, tt.wantSeverity)
+			}
+			if got := critical.Labels["priority"]; got != tt.wantPriority {
+				t.Errorf("priority = %q, want %q", got, tt.wantPriority)
+			}
+			if got := critical.Annotations["team_channel"]; got != tt.wantChannel {
+				t.Errorf("team_channel = %q, want %q", got, tt.wantChannel)
+			}
+			if critical.Annotations["summary"] == "" {
+				t.Error("Expected the summary annotation to be kept")
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// SLOReportValues are the values of an SLO over the period of a report, nil while there is no sample yet
+type SLOReportValues struct {
+	// Target is the objective of the SLO
+	Target float64
+	// Attainment is the SLI over the period
+	Attainment *float64
+	// ErrorBudgetConsumed is the share of the error budget of the period that was burned
+	ErrorBudgetConsumed *float64
+}
+
+// QuerySLOReport computes the attainment of the SLO over the period ending at ts from the good and total events
+// recorded in its base window, weighting every evaluation by its number of events
+func QuerySLOReport(ctx context.Context, api v1.API, slo *openslov1.SLO, period string, ts time.Time, cfg config.Config) (*SLOReportValues, error) {
+	target, err := parseTarget(slo.Spec.Objectives[0].Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SLO target: %w", err)
+	}
+	if err := validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	window := SLOBaseWindow(slo, cfg)
+	query := fmt.Sprintf("clamp_max(sum(sum_over_time(%s[%s])) / sum(sum_over_time(%s[%s])), 1)",
+		sloSelector(RecordPrefix+"_sli_good", slo, window), period,
+		sloSelector(RecordPrefix+"_sli_total", slo, window), period,
+	)
+
+	values := &SLOReportValues{Target: target}
+	samples, err := querySamples(ctx, api, query, ts)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) > 0 && !math.IsNaN(float64(samples[0].Value)) {
+		attainment := float64(samples[0].Value)
+		consumed := (1 - attainment) / (1 - target)
+		values.Attainment = &attainment
+		values.ErrorBudgetConsumed = &consumed
+	}
+	return values, nil
+}
+
+// NewSLOReportResult turns the values of an SLO over the period of a report into the result stored in the report status
+func NewSLOReportResult(slo *openslov1.SLO, values *SLOReportValues, err error) oskov1alpha1.SLOReportResult {
+	result := oskov1alpha1.SLOReportResult{
+		Namespace: slo.Namespace,
+		Name:      slo.Name,
+		Service:   slo.Spec.Service,
+	}
+	if len(slo.Spec.Objectives) > 0 {
+		result.Target = slo.Spec.Objectives[0].Target
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if values.Attainment == nil {
+		result.Error = "no samples recorded over the report period"
+		return result
+	}
+	result.Attainment = strconv.FormatFloat(*values.Attainment, 'f', 6, 64)
+	result.ErrorBudgetConsumed = strconv.FormatFloat(*values.ErrorBudgetConsumed, 'f', 4, 64)
+	result.Met = *values.Attainment >= values.Target
+	return result
+}
+
+// RenderSLOReportMarkdown renders the results of a report run as a Markdown table
+func RenderSLOReportMarkdown(report *oskov1alpha1.SLOReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# SLO report %s\n\n", report.Name)
+	fmt.Fprintf(&b, "Period of %s ending %s.\n\n", report.Spec.Period, report.Status.LastRunTime.UTC().Format(time.RFC3339))
+	b.WriteString("| Namespace | SLO | Service | Target | Attainment | Error budget consumed | Met |\n")
+	b.WriteString("|-----------|-----|---------|--------|------------|-----------------------|-----|\n")
+	for _, r := range report.Status.Results {
+		met := "yes"
+		if !r.Met {
+			met = "no"
+		}
+		attainment, consumed := r.Attainment, r.ErrorBudgetConsumed
+		if r.Error != "" {
+			attainment, consumed, met = "n/a", "n/a", r.Error
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			r.Namespace, r.Name, r.Service, r.Target, attainment, consumed, strings.ReplaceAll(met, "|", `\|`))
+	}
+	return b.String()
+}
+
+// RenderSLOReportCSV renders the results of a report run as CSV with a header row
+func RenderSLOReportCSV(report *oskov1alpha1.SLOReport) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{"namespace", "slo", "service", "target", "attainment", "error_budget_consumed", "met", "error"}}
+	for _, r := range report.Status.Results {
+		records = append(records, []string{
+			r.Namespace, r.Name, r.Service, r.Target, r.Attainment, r.ErrorBudgetConsumed, strconv.FormatBool(r.Met), r.Error,
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
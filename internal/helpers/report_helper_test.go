@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestQuerySLOReport(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.FormValue("query")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(vectorResponse(`{"metric":{},"value":[1700000000,"0.9995"]}`)))
+	}))
+	defer server.Close()
+
+	ds := &openslov1.Datasource{Spec: openslov1.DatasourceSpec{
+		Type:              "mimir",
+		ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL},
+	}}
+	dsAPI, _, err := NewDatasourceAPI(ds)
+	if err != nil {
+		t.Fatalf("NewDatasourceAPI() error = %v", err)
+	}
+
+	values, err := QuerySLOReport(context.Background(), dsAPI, createTestSLO("0.999"), "30d", time.Now(), config.Default())
+	if err != nil {
+		t.Fatalf("QuerySLOReport() error = %v", err)
+	}
+	if values.Attainment == nil || *values.Attainment != 0.9995 {
+		t.Errorf("Attainment = %v, want 0.9995", values.Attainment)
+	}
+	if values.ErrorBudgetConsumed == nil || math.Abs(*values.ErrorBudgetConsumed-0.5) > 1e-9 {
+		t.Errorf("ErrorBudgetConsumed = %v, want 0.5", values.ErrorBudgetConsumed)
+	}
+	want := `clamp_max(sum(sum_over_time(osko_sli_good{namespace="default", slo_name="test-slo", window="5m"}[30d])) / ` +
+		`sum(sum_over_time(osko_sli_total{namespace="default", slo_name="test-slo", window="5m"}[30d])), 1)`
+	if query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+}
+
+func TestNewSLOReportResult(t *testing.T) {
+	attainment, consumed := 0.998, 2.0
+	tests := []struct {
+		name   string
+		target string
+		values *SLOReportValues
+		err    error
+		want   oskov1alpha1.SLOReportResult
+	}{
+		{
+			name:   "missed objective",
+			values: &SLOReportValues{Target: 0.999, Attainment: &attainment, ErrorBudgetConsumed: &consumed},
+			want: oskov1alpha1.SLOReportResult{
+				Namespace: "default", Name: "test-slo", Service: "test-service", Target: "0.999",
+				Attainment: "0.998000", ErrorBudgetConsumed: "2.0000",
+			},
+		},
+		{
+			name:   "met objective",
+			target: "0.99",
+			values: &SLOReportValues{Target: 0.99, Attainment: &attainment, ErrorBudgetConsumed: &consumed},
+			want: oskov1alpha1.SLOReportResult{
+				Namespace: "default", Name: "test-slo", Service: "test-service", Target: "0.99",
+				Attainment: "0.998000", ErrorBudgetConsumed: "2.0000", Met: true,
+			},
+		},
+		{
+			name:   "no samples",
+			values: &SLOReportValues{Target: 0.999},
+			want: oskov1alpha1.SLOReportResult{
+				Namespace: "default", Name: "test-slo", Service: "test-service", Target: "0.999",
+				Error: "no samples recorded over the report period",
+			},
+		},
+		{
+			name: "query error",
+			err:  errors.New("connection refused"),
+			want: oskov1alpha1.SLOReportResult{
+				Namespace: "default", Name: "test-slo", Service: "test-service", Target: "0.999",
+				Error: "connection refused",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			if target == "" {
+				target = "0.999"
+			}
+			if got := NewSLOReportResult(createTestSLO(target), tt.values, tt.err); got != tt.want {
+				t.Errorf("NewSLOReportResult() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSLOReport(t *testing.T) {
+	report := &oskov1alpha1.SLOReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "monthly"},
+		Spec:       oskov1alpha1.SLOReportSpec{Period: "30d"},
+		Status: oskov1alpha1.SLOReportStatus{
+			LastRunTime: metav1.NewTime(time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)),
+			Results: []oskov1alpha1.SLOReportResult{
+				{Namespace: "default", Name: "checkout", Service: "shop", Target: "0.999", Attainment: "0.999500", ErrorBudgetConsumed: "0.5000", Met: true},
+				{Namespace: "default", Name: "search", Service: "shop", Target: "0.99", Error: "no samples recorded over the report period"},
+			},
+		},
+	}
+
+	markdown := RenderSLOReportMarkdown(report)
+	for _, want := range []string{
+		"Period of 30d ending 2024-05-01T06:00:00Z.",
+		"| default | checkout | shop | 0.999 | 0.999500 | 0.5000 | yes |",
+		"| default | search | shop | 0.99 | n/a | n/a | no samples recorded over the report period |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown report is missing %q:\n%s", want, markdown)
+		}
+	}
+
+	csv, err := RenderSLOReportCSV(report)
+	if err != nil {
+		t.Fatalf("RenderSLOReportCSV() error = %v", err)
+	}
+	want := "namespace,slo,service,target,attainment,error_budget_consumed,met,error\n" +
+		"default,checkout,shop,0.999,0.999500,0.5000,true,\n" +
+		"default,search,shop,0.99,,,false,no samples recorded over the report period\n"
+	if csv != want {
+		t.Errorf("csv report = %q, want %q", csv, want)
+	}
+}
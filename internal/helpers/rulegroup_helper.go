@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oskoperator/osko/internal/config"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	ruleGroupIntervalAnnotation        = "osko.dev/ruleGroupInterval"
+	ruleGroupEvaluationDelayAnnotation = "osko.dev/ruleGroupEvaluationDelay"
+	ruleGroupLimitAnnotation           = "osko.dev/ruleGroupLimit"
+)
+
+// windowClass buckets rule windows that can share an evaluation interval
+type windowClass string
+
+const (
+	shortWindowClass  windowClass = "short"
+	mediumWindowClass windowClass = "medium"
+	longWindowClass   windowClass = "long"
+)
+
+var windowClasses = []windowClass{shortWindowClass, mediumWindowClass, longWindowClass}
+
+// classifyWindow returns the class of a rule window: up to 1h is short, up to 24h is medium and anything longer is long
+func classifyWindow(window string) windowClass {
+	d, err := model.ParseDuration(window)
+	if err != nil {
+		return shortWindowClass
+	}
+	switch {
+	case time.Duration(d) <= time.Hour:
+		return shortWindowClass
+	case time.Duration(d) <= 24*time.Hour:
+		return mediumWindowClass
+	default:
+		return longWindowClass
+	}
+}
+
+// groupWindowClass returns the longest window class among the rules of a group, alerting rules count as short
+func groupWindowClass(group monitoringv1.RuleGroup) windowClass {
+	class := shortWindowClass
+	for _, r := range group.Rules {
+		window, ok := r.Labels["window"]
+		if !ok || r.Alert != "" {
+			continue
+		}
+		switch classifyWindow(window) {
+		case longWindowClass:
+			return longWindowClass
+		case mediumWindowClass:
+			class = mediumWindowClass
+		}
+	}
+	return class
+}
+
+// ruleGroupSettings are the evaluation settings applied to a generated rule group
+type ruleGroupSettings struct {
+	Interval        model.Duration
+	EvaluationDelay model.Duration
+	Limit           int
+}
+
+// ruleGroupSettingsFor resolves the settings of every window class, starting from the configured
+// defaults and applying the osko.dev/ruleGroup* annotations of the SLO on top
+func ruleGroupSettingsFor(annotations map[string]string, cfg config.RuleGroupConfig) (map[windowClass]ruleGroupSettings, error) {
+	defaults := map[windowClass]config.RuleGroupDefaults{
+		shortWindowClass:  cfg.ShortWindow,
+		mediumWindowClass: cfg.MediumWindow,
+		longWindowClass:   cfg.LongWindow,
+	}
+	settings := make(map[windowClass]ruleGroupSettings, len(windowClasses))
+	for _, class := range windowClasses {
+		settings[class] = ruleGroupSettings{
+			Interval:        model.Duration(defaults[class].Interval),
+			EvaluationDelay: model.Duration(defaults[class].EvaluationDelay),
+		}
+	}
+
+	if value, ok := annotations[ruleGroupIntervalAnnotation]; ok {
+		intervals, err := parseWindowClassDurations(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", ruleGroupIntervalAnnotation, err)
+		}
+		for class, interval := range intervals {
+			s := settings[class]
+			s.Interval = interval
+			settings[class] = s
+		}
+	}
+
+	if value, ok := annotations[ruleGroupEvaluationDelayAnnotation]; ok {
+		delays, err := parseWindowClassDurations(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", ruleGroupEvaluationDelayAnnotation, err)
+		}
+		for class, delay := range delays {
+			s := settings[class]
+			s.EvaluationDelay = delay
+			settings[class] = s
+		}
+	}
+
+	if value, ok := annotations[ruleGroupLimitAnnotation]; ok {
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid %s annotation: %q is not a non-negative integer", ruleGroupLimitAnnotation, value)
+		}
+		for class, s := range settings {
+			s.Limit = limit
+			settings[class] = s
+		}
+	}
+
+	return settings, nil
+}
+
+// parseWindowClassDurations accepts either a single duration applied to every window class,
+// or a comma-separated list of class=duration pairs, e.g. "long=10m,medium=2m"
+func parseWindowClassDurations(value string) (map[windowClass]model.Duration, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "=") {
+		d, err := model.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		durations := make(map[windowClass]model.Duration, len(windowClasses))
+		for _, class := range windowClasses {
+			durations[class] = d
+		}
+		return durations, nil
+	}
+
+	durations := map[windowClass]model.Duration{}
+	for _, pair := range strings.Split(value, ",") {
+		class, duration, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("expected class=duration, got %q", pair)
+		}
+		switch windowClass(class) {
+		case shortWindowClass, mediumWindowClass, longWindowClass:
+		default:
+			return nil, fmt.Errorf("unknown window class %q, expected one of short, medium or long", class)
+		}
+		d, err := model.ParseDuration(duration)
+		if err != nil {
+			return nil, err
+		}
+		durations[windowClass(class)] = d
+	}
+	return durations, nil
+}
+
+// newRuleGroup creates a rule group with the evaluation settings of its window class
+func newRuleGroup(name string, rules []monitoringv1.Rule, settings ruleGroupSettings) monitoringv1.RuleGroup {
+	group := monitoringv1.RuleGroup{Name: name, Rules: rules}
+	if settings.Interval != 0 {
+		interval := monitoringv1.Duration(settings.Interval.String())
+		group.Interval = &interval
+	}
+	if settings.Limit != 0 {
+		limit := settings.Limit
+		group.Limit = &limit
+	}
+	return group
+}
+
+// splitByWindowClass splits rules into one group per window class. The short class keeps the plain
+// group name so existing rule groups are updated in place, other classes get the class as infix.
+func splitByWindowClass(sloName, suffix string, rules []monitoringv1.Rule, settings map[windowClass]ruleGroupSettings) []monitoringv1.RuleGroup {
+	rulesByClass := map[windowClass][]monitoringv1.Rule{}
+	for _, r := range rules {
+		class := classifyWindow(r.Labels["window"])
+		rulesByClass[class] = append(rulesByClass[class], r)
+	}
+
+	var groups []monitoringv1.RuleGroup
+	for _, class := range windowClasses {
+		if len(rulesByClass[class]) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s_%s", sloName, suffix)
+		if class != shortWindowClass {
+			name = fmt.Sprintf("%s_%s_%s", sloName, class, suffix)
+		}
+		groups = append(groups, newRuleGroup(name, rulesByClass[class], settings[class]))
+	}
+	return groups
+}
@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/prometheus/common/model"
+)
+
+func TestClassifyWindow(t *testing.T) {
+	tests := []struct {
+		window string
+		want   windowClass
+	}{
+		{"5m", shortWindowClass},
+		{"1h", shortWindowClass},
+		{"2h", mediumWindowClass},
+		{"24h", mediumWindowClass},
+		{"1d", mediumWindowClass},
+		{"3d", longWindowClass},
+		{"28d", longWindowClass},
+		{"", shortWindowClass},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			if got := classifyWindow(tt.window); got != tt.want {
+				t.Errorf("classifyWindow(%q) = %v, want %v", tt.window, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleGroupSettingsFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[windowClass]ruleGroupSettings
+		wantErr     bool
+	}{
+		{
+			name: "defaults",
+			want: map[windowClass]ruleGroupSettings{
+				shortWindowClass:  {},
+				mediumWindowClass: {Interval: model.Duration(2 * time.Minute)},
+				longWindowClass:   {Interval: model.Duration(4 * time.Minute)},
+			},
+		},
+		{
+			name: "single interval and limit",
+			annotations: map[string]string{
+				ruleGroupIntervalAnnotation: "30s",
+				ruleGroupLimitAnnotation:    "100",
+			},
+			want: map[windowClass]ruleGroupSettings{
+				shortWindowClass:  {Interval: model.Duration(30 * time.Second), Limit: 100},
+				mediumWindowClass: {Interval: model.Duration(30 * time.Second), Limit: 100},
+				longWindowClass:   {Interval: model.Duration(30 * time.Second), Limit: 100},
+			},
+		},
+		{
+			name: "per class",
+			annotations: map[string]string{
+				ruleGroupIntervalAnnotation:        "long=3m, short=30s",
+				ruleGroupEvaluationDelayAnnotation: "long=1m",
+			},
+			want: map[windowClass]ruleGroupSettings{
+				shortWindowClass:  {Interval: model.Duration(30 * time.Second)},
+				mediumWindowClass: {Interval: model.Duration(2 * time.Minute)},
+				longWindowClass:   {Interval: model.Duration(3 * time.Minute), EvaluationDelay: model.Duration(time.Minute)},
+			},
+		},
+		{"invalid duration", map[string]string{ruleGroupIntervalAnnotation: "often"}, nil, true},
+		{"unknown class", map[string]string{ruleGroupIntervalAnnotation: "weekly=1h"}, nil, true},
+		{"invalid limit", map[string]string{ruleGroupLimitAnnotation: "-1"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ruleGroupSettingsFor(tt.annotations, config.Default().RuleGroups)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ruleGroupSettingsFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for class, want := range tt.want {
+				if got[class] != want {
+					t.Errorf("ruleGroupSettingsFor()[%s] = %+v, want %+v", class, got[class], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetupRules_GroupsByWindowClass(t *testing.T) {
+	slo := createTestSLO("0.999")
+	slo.Annotations = map[string]string{
+		"osko.dev/magicAlerting":    "true",
+		ruleGroupIntervalAnnotation: "short=30s,long=5m",
+		ruleGroupLimitAnnotation:    "50",
+	}
+	mrs := &MonitoringRuleSet{
+		Slo:        slo,
+		Sli:        createTestSLI(),
+		BaseWindow: "5m",
+		Config:     config.Default(),
+	}
+
+	ruleGroups, err := mrs.SetupRules()
+	if err != nil {
+		t.Fatalf("SetupRules() error = %v", err)
+	}
+
+	wantIntervals := map[string]string{
+		"test-slo_slo_target":       "30s",
+		"test-slo_sli_total":        "30s",
+		"test-slo_medium_sli_total": "2m",
+		"test-slo_long_sli_total":   "5m",
+		"test-slo_long_burn_rate":   "5m",
+		"test-slo_slo_alert":        "30s",
+	}
+	found := map[string]bool{}
+	for _, rg := range ruleGroups {
+		if rg.Limit == nil || *rg.Limit != 50 {
+			t.Errorf("group %s: expected limit 50, got %v", rg.Name, rg.Limit)
+		}
+		for _, r := range rg.Rules {
+			if r.Alert != "" {
+				continue
+			}
+			if got := classifyWindow(r.Labels["window"]); rg.Name != "test-slo_slo_target" && got != groupWindowClass(rg) {
+				t.Errorf("group %s contains rule of window %s", rg.Name, r.Labels["window"])
+			}
+		}
+		want, ok := wantIntervals[rg.Name]
+		if !ok {
+			continue
+		}
+		found[rg.Name] = true
+		if rg.Interval == nil || string(*rg.Interval) != want {
+			t.Errorf("group %s: expected interval %s, got %v", rg.Name, want, rg.Interval)
+		}
+	}
+	for name := range wantIntervals {
+		if !found[name] {
+			t.Errorf("expected rule group %s", name)
+		}
+	}
+}
+
+func TestNewMimirRuleGroups_EvaluationSettings(t *testing.T) {
+	slo := createTestSLO("0.999")
+	slo.Annotations = map[string]string{
+		ruleGroupEvaluationDelayAnnotation: "long=2m",
+		ruleGroupLimitAnnotation:           "10",
+	}
+
+	rule, err := CreatePrometheusRule(slo, createTestSLI(), config.Default())
+	if err != nil {
+		t.Fatalf("CreatePrometheusRule() error = %v", err)
+	}
+
+	groups, err := NewMimirRuleGroups(rule, &oskov1alpha1.ConnectionDetails{}, config.Default())
+	if err != nil {
+		t.Fatalf("NewMimirRuleGroups() error = %v", err)
+	}
+
+	for _, rg := range groups {
+		if rg.Limit != 10 {
+			t.Errorf("group %s: expected limit 10, got %d", rg.Name, rg.Limit)
+		}
+		switch rg.Name {
+		case "test-slo_long_sli_total":
+			if rg.Interval != model.Duration(4*time.Minute) {
+				t.Errorf("group %s: expected the long window default interval, got %s", rg.Name, rg.Interval)
+			}
+			if rg.EvaluationDelay == nil || *rg.EvaluationDelay != model.Duration(2*time.Minute) {
+				t.Errorf("group %s: expected evaluation delay 2m, got %v", rg.Name, rg.EvaluationDelay)
+			}
+		case "test-slo_sli_total":
+			if rg.Interval != 0 {
+				t.Errorf("group %s: expected the ruler default interval, got %s", rg.Name, rg.Interval)
+			}
+			if rg.EvaluationDelay != nil {
+				t.Errorf("group %s: expected no evaluation delay, got %s", rg.Name, rg.EvaluationDelay)
+			}
+		}
+	}
+}
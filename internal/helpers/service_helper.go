@@ -0,0 +1,84 @@
+package helpers
+
+import (
+	"fmt"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ServiceRollupRuleName returns the name of the PrometheusRule holding the rollup rules of a Service
+func ServiceRollupRuleName(service *openslov1.Service) string {
+	return fmt.Sprintf("%s-service-rollup", service.Name)
+}
+
+// CreateServiceRollupRule creates the recording rules of a Service: the error budget left of each of its SLOs
+// over the SLO window, and the lowest of them as the error budget left of the Service
+func CreateServiceRollupRule(service *openslov1.Service, slos []openslov1.SLO, cfg config.Config) (*monitoringv1.PrometheusRule, error) {
+	settings, err := ruleGroupSettingsFor(service.Annotations, cfg.RuleGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []monitoringv1.Rule
+	for i := range slos {
+		slo := &slos[i]
+		if len(slo.Spec.Objectives) == 0 {
+			return nil, fmt.Errorf("SLO %s has no objective", slo.Name)
+		}
+		target, err := parseTarget(slo.Spec.Objectives[0].Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the target of SLO %s: %w", slo.Name, err)
+		}
+		if err := validateTarget(target); err != nil {
+			return nil, fmt.Errorf("SLO %s: %w", slo.Name, err)
+		}
+		window := SLOWindow(slo)
+		rules = append(rules, monitoringv1.Rule{
+			Record: fmt.Sprintf("%s_error_budget_remaining", RecordPrefix),
+			Expr: intstr.FromString(fmt.Sprintf("1 - %s / %.10f",
+				sloSelector(RecordPrefix+"_error_budget_ratio", slo, window), 1-target)),
+			Labels: map[string]string{
+				"namespace": slo.Namespace,
+				"service":   service.Name,
+				"slo_name":  slo.Name,
+				"window":    window,
+			},
+		})
+	}
+	rules = append(rules, monitoringv1.Rule{
+		Record: fmt.Sprintf("%s_service_error_budget_remaining", RecordPrefix),
+		Expr: intstr.FromString(fmt.Sprintf(`min by (namespace, service) (%s_error_budget_remaining{namespace=%q, service=%q})`,
+			RecordPrefix, service.Namespace, service.Name)),
+	})
+
+	prometheusRule := &monitoringv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "PrometheusRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ServiceRollupRuleName(service),
+			Namespace:   service.Namespace,
+			Labels:      service.Labels,
+			Annotations: service.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(service, openslov1.GroupVersion.WithKind("Service")),
+			},
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				// The rollup reads the SLO window, the slowest of the recorded windows
+				newRuleGroup(fmt.Sprintf("%s_service_rollup", service.Name), rules, settings[longWindowClass]),
+			},
+		},
+	}
+
+	if err := ValidatePrometheusRule(prometheusRule); err != nil {
+		return nil, err
+	}
+	return prometheusRule, nil
+}
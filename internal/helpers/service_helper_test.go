@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateServiceRollupRule(t *testing.T) {
+	service := &openslov1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "uid"}}
+	slos := []openslov1.SLO{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "availability", Namespace: "shop"},
+			Spec: openslov1.SLOSpec{
+				Service:    "checkout",
+				Objectives: []openslov1.ObjectivesSpec{{Target: "0.99"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "shop"},
+			Spec: openslov1.SLOSpec{
+				Service:    "checkout",
+				TimeWindow: []openslov1.TimeWindowSpec{{Duration: "7d"}},
+				Objectives: []openslov1.ObjectivesSpec{{Target: "0.95"}},
+			},
+		},
+	}
+
+	rule, err := CreateServiceRollupRule(service, slos, config.Default())
+	if err != nil {
+		t.Fatalf("CreateServiceRollupRule() error = %v", err)
+	}
+	if rule.Name != "checkout-service-rollup" || rule.OwnerReferences[0].Kind != "Service" {
+		t.Errorf("unexpected rule metadata %s, owner %s", rule.Name, rule.OwnerReferences[0].Kind)
+	}
+	if len(rule.Spec.Groups) != 1 {
+		t.Fatalf("expected a single rule group, got %d", len(rule.Spec.Groups))
+	}
+	rules := rule.Spec.Groups[0].Rules
+	if len(rules) != 3 {
+		t.Fatalf("expected a rule per SLO and the rollup, got %d rules", len(rules))
+	}
+
+	want := []string{
+		`1 - osko_error_budget_ratio{namespace="shop", slo_name="availability", window="28d"} / 0.0100000000`,
+		`1 - osko_error_budget_ratio{namespace="shop", slo_name="latency", window="7d"} / 0.0500000000`,
+		`min by (namespace, service) (osko_error_budget_remaining{namespace="shop", service="checkout"})`,
+	}
+	for i, expr := range want {
+		if got := rules[i].Expr.String(); got != expr {
+			t.Errorf("rule %d expr = %q, want %q", i, got, expr)
+		}
+	}
+	if rules[2].Record != "osko_service_error_budget_remaining" {
+		t.Errorf("rollup record = %q", rules[2].Record)
+	}
+}
+
+func TestCreateServiceRollupRuleRejectsInvalidTarget(t *testing.T) {
+	service := &openslov1.Service{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}}
+	slos := []openslov1.SLO{{
+		ObjectMeta: metav1.ObjectMeta{Name: "availability", Namespace: "shop"},
+		Spec:       openslov1.SLOSpec{Objectives: []openslov1.ObjectivesSpec{{Target: "1"}}},
+	}}
+	_, err := CreateServiceRollupRule(service, slos, config.Default())
+	if err == nil || !strings.Contains(err.Error(), "availability") {
+		t.Errorf("CreateServiceRollupRule() error = %v, want an error about the SLO", err)
+	}
+}
@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/utils"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SLIQuery is one of the queries an SLI is computed from
+type SLIQuery struct {
+	// Name is the role of the query in the SLI, one of good, bad, total, raw or threshold
+	Name  string
+	Query string
+}
+
+// SLIQueries returns the non-empty queries of the SLI in a stable order
+func SLIQueries(sli *openslov1.SLI) []SLIQuery {
+	candidates := []SLIQuery{
+		{Name: "good", Query: sli.Spec.RatioMetric.Good.MetricSource.Spec.Query},
+		{Name: "bad", Query: sli.Spec.RatioMetric.Bad.MetricSource.Spec.Query},
+		{Name: "total", Query: sli.Spec.RatioMetric.Total.MetricSource.Spec.Query},
+		{Name: "raw", Query: sli.Spec.RatioMetric.Raw.MetricSource.Spec.Query},
+		{Name: "threshold", Query: sli.Spec.ThresholdMetric.MetricSource.Spec.Query},
+	}
+	queries := make([]SLIQuery, 0, len(candidates))
+	for _, q := range candidates {
+		if q.Query != "" {
+			queries = append(queries, q)
+		}
+	}
+	return queries
+}
+
+// InlineSLIName returns the name of the SLI created for the inline indicator of an SLO
+func InlineSLIName(slo *openslov1.SLO) string {
+	if slo.Spec.Indicator.Metadata.Name != "" {
+		return slo.Spec.Indicator.Metadata.Name
+	}
+	return fmt.Sprintf("%s-sli", slo.Name)
+}
+
+// NewInlineSLI returns the SLI of the inline indicator of an SLO
+func NewInlineSLI(slo *openslov1.SLO) *openslov1.SLI {
+	return &openslov1.SLI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      InlineSLIName(slo),
+			Namespace: slo.Namespace,
+		},
+		Spec: openslov1.SLISpec{
+			Description:     slo.Spec.Indicator.Spec.Description,
+			RatioMetric:     slo.Spec.Indicator.Spec.RatioMetric,
+			ThresholdMetric: slo.Spec.Indicator.Spec.ThresholdMetric,
+		},
+	}
+}
+
+// SLIConsumers returns the SLOs referencing the SLI through their indicatorRef or owning it as their inline indicator.
+// SLOs being deleted are left out, they no longer need the SLI. The reader must have the utils.SLOIndicatorRefIndex.
+func SLIConsumers(ctx context.Context, c client.Reader, sli *openslov1.SLI) ([]openslov1.SLO, error) {
+	referencing := &openslov1.SLOList{}
+	if err := c.List(ctx, referencing, client.InNamespace(sli.Namespace), client.MatchingFields{utils.SLOIndicatorRefIndex: sli.Name}); err != nil {
+		return nil, err
+	}
+
+	consumers := make([]openslov1.SLO, 0, len(referencing.Items))
+	for _, slo := range referencing.Items {
+		if slo.DeletionTimestamp == nil {
+			consumers = append(consumers, slo)
+		}
+	}
+
+	for _, ref := range sli.OwnerReferences {
+		if ref.Kind != "SLO" {
+			continue
+		}
+		owner := &openslov1.SLO{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: sli.Namespace}, owner); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if owner.DeletionTimestamp == nil && owner.Spec.Indicator != nil && InlineSLIName(owner) == sli.Name {
+			consumers = append(consumers, *owner)
+		}
+	}
+	return consumers, nil
+}
+
+// ValidateSLIQueries runs every query of the SLI as an instant query and records how many series each one returns.
+// Query errors are recorded per query, they usually point at a typo in the SLI rather than at the Datasource.
+func ValidateSLIQueries(ctx context.Context, api v1.API, sli *openslov1.SLI, ts time.Time) []openslov1.SLIQueryStatus {
+	queries := SLIQueries(sli)
+	results := make([]openslov1.SLIQueryStatus, 0, len(queries))
+	for _, q := range queries {
+		result := openslov1.SLIQueryStatus{Name: q.Name}
+		series, err := countSeries(ctx, api, q.Query, ts)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Series = series
+			result.ReturnsSeries = series > 0
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func countSeries(ctx context.Context, api v1.API, query string, ts time.Time) (int, error) {
+	result, _, err := api.Query(ctx, query, ts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", query, err)
+	}
+	switch value := result.(type) {
+	case model.Vector:
+		return len(value), nil
+	case model.Matrix:
+		return len(value), nil
+	case *model.Scalar:
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("unexpected result type %s for query %s", result.Type(), query)
+	}
+}
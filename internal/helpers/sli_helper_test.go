@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+)
+
+func TestValidateSLIQueries(t *testing.T) {
+	responses := map[string]string{
+		`http_requests_total{code!~"5.."}`: vectorResponse(sample("", "10"), sample("", "12")),
+		`http_requests_total`:              vectorResponse(sample("", "25")),
+		`http_requests_totl{code=~"5.."}`:  vectorResponse(),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.FormValue("query")
+		response, ok := responses[query]
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	ds := &openslov1.Datasource{Spec: openslov1.DatasourceSpec{
+		Type:              "mimir",
+		ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL},
+	}}
+	dsAPI, _, err := NewDatasourceAPI(ds)
+	if err != nil {
+		t.Fatalf("NewDatasourceAPI() error = %v", err)
+	}
+
+	sli := &openslov1.SLI{Spec: openslov1.SLISpec{RatioMetric: openslov1.RatioMetricSpec{
+		Good:  openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Spec: openslov1.MetricSourceSpec{Query: `http_requests_total{code!~"5.."}`}}},
+		Bad:   openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Spec: openslov1.MetricSourceSpec{Query: `http_requests_totl{code=~"5.."}`}}},
+		Total: openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Spec: openslov1.MetricSourceSpec{Query: `http_requests_total`}}},
+		Raw:   openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Spec: openslov1.MetricSourceSpec{Query: `sum(rate(http_requests_total[5m]`}}},
+	}}}
+
+	results := ValidateSLIQueries(context.Background(), dsAPI, sli, time.Now())
+
+	want := []openslov1.SLIQueryStatus{
+		{Name: "good", ReturnsSeries: true, Series: 2},
+		{Name: "bad", ReturnsSeries: false, Series: 0},
+		{Name: "total", ReturnsSeries: true, Series: 1},
+		{Name: "raw"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("ValidateSLIQueries() = %+v, want %d results", results, len(want))
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Name != w.Name || got.ReturnsSeries != w.ReturnsSeries || got.Series != w.Series {
+			t.Errorf("result %d = %+v, want %+v", i, got, w)
+		}
+		if wantErr := w.Name == "raw"; (got.Error != "") != wantErr {
+			t.Errorf("result %d error = %q, wantErr %v", i, got.Error, wantErr)
+		}
+	}
+}
+
+func TestSLIQueries(t *testing.T) {
+	sli := &openslov1.SLI{Spec: openslov1.SLISpec{
+		ThresholdMetric: openslov1.ThresholdMetricSpec{MetricSource: openslov1.MetricSource{Spec: openslov1.MetricSourceSpec{Query: "latency"}}},
+	}}
+
+	queries := SLIQueries(sli)
+	if len(queries) != 1 || queries[0].Name != "threshold" || queries[0].Query != "latency" {
+		t.Errorf("SLIQueries() = %+v, want only the threshold query", queries)
+	}
+}
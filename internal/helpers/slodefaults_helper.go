@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"context"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetSLODefaults returns the SLODefaults of a namespace, nil when the namespace has none
+func GetSLODefaults(ctx context.Context, c client.Reader, namespace string) (*oskov1alpha1.SLODefaults, error) {
+	defaults := &oskov1alpha1.SLODefaults{}
+	if err := c.Get(ctx, client.ObjectKey{Name: oskov1alpha1.SLODefaultsName, Namespace: namespace}, defaults); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return defaults, nil
+}
+
+// EffectiveSLO returns a copy of the SLO with the SLODefaults of its namespace applied
+func EffectiveSLO(ctx context.Context, c client.Reader, slo *openslov1.SLO) (*openslov1.SLO, error) {
+	defaults, err := GetSLODefaults(ctx, c, slo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return WithSLODefaults(slo, defaults), nil
+}
+
+// WithSLODefaults returns a copy of the SLO with every setting of the SLODefaults the SLO does not set itself.
+// The SLO itself is left untouched, the defaults are never written back to the API server.
+func WithSLODefaults(slo *openslov1.SLO, defaults *oskov1alpha1.SLODefaults) *openslov1.SLO {
+	effective := slo.DeepCopy()
+	if defaults == nil {
+		return effective
+	}
+	spec := defaults.Spec
+
+	annotations := map[string]string{
+		"osko.dev/datasourceRef": spec.DatasourceRef,
+		"osko.dev/alertingTool":  spec.AlertingTool,
+		"osko.dev/baseWindow":    spec.BaseWindow,
+	}
+	if spec.MagicAlerting != nil && *spec.MagicAlerting {
+		annotations["osko.dev/magicAlerting"] = "true"
+	}
+	for key, value := range annotations {
+		if effective.Annotations[key] != "" || value == "" {
+			continue
+		}
+		if effective.Annotations == nil {
+			effective.Annotations = map[string]string{}
+		}
+		effective.Annotations[key] = value
+	}
+
+	for key, value := range spec.Labels {
+		label := "label.osko.dev/" + key
+		if _, ok := effective.Labels[label]; ok {
+			continue
+		}
+		if effective.Labels == nil {
+			effective.Labels = map[string]string{}
+		}
+		effective.Labels[label] = value
+	}
+
+	if effective.Spec.BudgetingMethod == "" {
+		effective.Spec.BudgetingMethod = spec.BudgetingMethod
+	}
+	return effective
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithSLODefaults(t *testing.T) {
+	defaults := &oskov1alpha1.SLODefaults{
+		Spec: oskov1alpha1.SLODefaultsSpec{
+			DatasourceRef:   "mimir-team",
+			MagicAlerting:   ptr.To(true),
+			AlertingTool:    "pagerduty",
+			BaseWindow:      "10m",
+			Labels:          map[string]string{"team": "shop", "tier": "1"},
+			BudgetingMethod: "Occurrences",
+		},
+	}
+
+	t.Run("unset settings", func(t *testing.T) {
+		slo := createTestSLO("0.999")
+		effective := WithSLODefaults(slo, defaults)
+
+		wantAnnotations := map[string]string{
+			"osko.dev/datasourceRef": "mimir-team",
+			"osko.dev/magicAlerting": "true",
+			"osko.dev/alertingTool":  "pagerduty",
+			"osko.dev/baseWindow":    "10m",
+		}
+		for key, want := range wantAnnotations {
+			if got := effective.Annotations[key]; got != want {
+				t.Errorf("annotation %s = %q, want %q", key, got, want)
+			}
+		}
+		if got := effective.Labels["label.osko.dev/team"]; got != "shop" {
+			t.Errorf("label.osko.dev/team = %q, want shop", got)
+		}
+		if effective.Spec.BudgetingMethod != "Occurrences" {
+			t.Errorf("BudgetingMethod = %q, want Occurrences", effective.Spec.BudgetingMethod)
+		}
+		if len(slo.Annotations) != 0 || len(slo.Labels) != 0 {
+			t.Errorf("WithSLODefaults() modified the SLO: %v %v", slo.Annotations, slo.Labels)
+		}
+	})
+
+	t.Run("explicit settings", func(t *testing.T) {
+		slo := createTestSLO("0.999")
+		slo.Annotations = map[string]string{
+			"osko.dev/datasourceRef": "mimir-infra",
+			"osko.dev/magicAlerting": "false",
+		}
+		slo.Labels = map[string]string{"label.osko.dev/team": "checkout"}
+		effective := WithSLODefaults(slo, defaults)
+
+		if got := effective.Annotations["osko.dev/datasourceRef"]; got != "mimir-infra" {
+			t.Errorf("datasourceRef = %q, want mimir-infra", got)
+		}
+		if got := effective.Annotations["osko.dev/magicAlerting"]; got != "false" {
+			t.Errorf("magicAlerting = %q, want false", got)
+		}
+		if got := effective.Labels["label.osko.dev/team"]; got != "checkout" {
+			t.Errorf("label.osko.dev/team = %q, want checkout", got)
+		}
+		if got := effective.Labels["label.osko.dev/tier"]; got != "1" {
+			t.Errorf("label.osko.dev/tier = %q, want 1", got)
+		}
+	})
+
+	t.Run("no defaults", func(t *testing.T) {
+		slo := createTestSLO("0.999")
+		if effective := WithSLODefaults(slo, nil); len(effective.Annotations) != 0 {
+			t.Errorf("annotations = %v, want none", effective.Annotations)
+		}
+	})
+}
+
+func TestEffectiveSLO(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := oskov1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&oskov1alpha1.SLODefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: oskov1alpha1.SLODefaultsName, Namespace: "shop"},
+		Spec:       oskov1alpha1.SLODefaultsSpec{AlertingTool: "pagerduty"},
+	}).Build()
+
+	for namespace, want := range map[string]string{"shop": "pagerduty", "catalog": ""} {
+		slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: namespace}}
+		effective, err := EffectiveSLO(context.Background(), c, slo)
+		if err != nil {
+			t.Fatalf("EffectiveSLO() error = %v", err)
+		}
+		if got := effective.Annotations["osko.dev/alertingTool"]; got != want {
+			t.Errorf("alertingTool in namespace %s = %q, want %q", namespace, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/config"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const defaultSLOWindow = "28d"
+
+// SLOWindow returns the time window the SLO is measured over
+func SLOWindow(slo *openslov1.SLO) string {
+	if len(slo.Spec.TimeWindow) > 0 && slo.Spec.TimeWindow[0].Duration != "" {
+		return string(slo.Spec.TimeWindow[0].Duration)
+	}
+	return defaultSLOWindow
+}
+
+// SLOBaseWindow returns the window of the finest recording rules of the SLO, the other windows are built on top of it
+func SLOBaseWindow(slo *openslov1.SLO, cfg config.Config) string {
+	if baseWindow := slo.ObjectMeta.Annotations["osko.dev/baseWindow"]; baseWindow != "" {
+		return baseWindow
+	}
+	return model.Duration(cfg.DefaultBaseWindow).String()
+}
+
+// BudgetExhaustedThreshold returns the share of the error budget left at or below which the budget of the SLO
+// counts as exhausted, from the osko.dev/budgetExhaustedThreshold annotation or the configured default
+func BudgetExhaustedThreshold(slo *openslov1.SLO, cfg config.Config) (float64, error) {
+	value, ok := slo.ObjectMeta.Annotations["osko.dev/budgetExhaustedThreshold"]
+	if !ok {
+		return cfg.BudgetExhaustedThreshold, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold >= 1 {
+		return 0, fmt.Errorf("invalid osko.dev/budgetExhaustedThreshold annotation %q: must be a number in [0, 1)", value)
+	}
+	return threshold, nil
+}
+
+// SLOStatusValues are the live values of an SLO read back from its recording rules, nil while there is no sample yet
+type SLOStatusValues struct {
+	// SLI is the SLI measured over the SLO window
+	SLI *float64
+	// ErrorBudgetRemaining is the share of the error budget left in the SLO window, negative when it is overspent
+	ErrorBudgetRemaining *float64
+	// FastestBurnRate is the highest error budget burn rate across all recorded windows
+	FastestBurnRate *float64
+	// FastestBurnRateWindow is the window FastestBurnRate was recorded over
+	FastestBurnRateWindow string
+	// EvaluationTime is the time the values were queried at
+	EvaluationTime time.Time
+}
+
+// QuerySLOStatus reads the SLI measurement, error budget ratio and burn rates the generated rules record for the SLO
+func QuerySLOStatus(ctx context.Context, api v1.API, slo *openslov1.SLO) (*SLOStatusValues, error) {
+	target, err := parseTarget(slo.Spec.Objectives[0].Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SLO target: %w", err)
+	}
+	if err := validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	values := &SLOStatusValues{EvaluationTime: time.Now()}
+	window := SLOWindow(slo)
+
+	sli, err := querySamples(ctx, api, sloSelector(RecordPrefix+"_sli_measurement", slo, window), values.EvaluationTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(sli) > 0 && !math.IsNaN(float64(sli[0].Value)) {
+		v := float64(sli[0].Value)
+		values.SLI = &v
+	}
+
+	ratio, err := querySamples(ctx, api, sloSelector(RecordPrefix+"_error_budget_ratio", slo, window), values.EvaluationTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(ratio) > 0 && !math.IsNaN(float64(ratio[0].Value)) {
+		v := 1 - float64(ratio[0].Value)/(1-target)
+		values.ErrorBudgetRemaining = &v
+	}
+
+	burnRates, err := querySamples(ctx, api, sloSelector(RecordPrefix+"_error_budget_burn_rate", slo, ""), values.EvaluationTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range burnRates {
+		v := float64(sample.Value)
+		if math.IsNaN(v) || (values.FastestBurnRate != nil && v <= *values.FastestBurnRate) {
+			continue
+		}
+		values.FastestBurnRate = &v
+		values.FastestBurnRateWindow = string(sample.Metric["window"])
+	}
+
+	return values, nil
+}
+
+func sloSelector(record string, slo *openslov1.SLO, window string) string {
+	selector := fmt.Sprintf(`%s{namespace=%q, slo_name=%q`, record, slo.Namespace, slo.Name)
+	if window != "" {
+		selector += fmt.Sprintf(`, window=%q`, window)
+	}
+	return selector + "}"
+}
+
+func querySamples(ctx context.Context, api v1.API, query string, ts time.Time) (model.Vector, error) {
+	result, _, err := api.Query(ctx, query, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", query, err)
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s for query %s", result.Type(), query)
+	}
+	return vector, nil
+}
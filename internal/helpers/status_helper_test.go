@@ -0,0 +1,134 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+)
+
+func vectorResponse(samples ...string) string {
+	return fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[%s]}}`, strings.Join(samples, ","))
+}
+
+func sample(window, value string) string {
+	return fmt.Sprintf(`{"metric":{"window":%q},"value":[1700000000,%q]}`, window, value)
+}
+
+func TestQuerySLOStatus(t *testing.T) {
+	responses := map[string]string{
+		"osko_sli_measurement":        vectorResponse(sample("28d", "0.9995")),
+		"osko_error_budget_ratio":     vectorResponse(sample("28d", "0.0005")),
+		"osko_error_budget_burn_rate": vectorResponse(sample("5m", "2.5"), sample("1h", "14.4"), sample("3d", "NaN")),
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prometheus/api/v1/query" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+		}
+		if r.Header.Get("X-Scope-OrgID") != "infra" {
+			t.Errorf("expected the tenant header to be set")
+		}
+		query := r.FormValue("query")
+		queries = append(queries, query)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[query[:strings.Index(query, "{")]]))
+	}))
+	defer server.Close()
+
+	ds := &openslov1.Datasource{Spec: openslov1.DatasourceSpec{
+		Type:              "mimir",
+		ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL, TargetTenant: "infra"},
+	}}
+	dsAPI, _, err := NewDatasourceAPI(ds)
+	if err != nil {
+		t.Fatalf("NewDatasourceAPI() error = %v", err)
+	}
+
+	values, err := QuerySLOStatus(context.Background(), dsAPI, createTestSLO("0.999"))
+	if err != nil {
+		t.Fatalf("QuerySLOStatus() error = %v", err)
+	}
+
+	if values.SLI == nil || *values.SLI != 0.9995 {
+		t.Errorf("SLI = %v, want 0.9995", values.SLI)
+	}
+	if values.ErrorBudgetRemaining == nil || math.Abs(*values.ErrorBudgetRemaining-0.5) > 1e-9 {
+		t.Errorf("ErrorBudgetRemaining = %v, want 0.5", values.ErrorBudgetRemaining)
+	}
+	if values.FastestBurnRate == nil || *values.FastestBurnRate != 14.4 || values.FastestBurnRateWindow != "1h" {
+		t.Errorf("FastestBurnRate = %v over %q, want 14.4 over 1h", values.FastestBurnRate, values.FastestBurnRateWindow)
+	}
+	if want := `osko_sli_measurement{namespace="default", slo_name="test-slo", window="28d"}`; len(queries) == 0 || queries[0] != want {
+		t.Errorf("queries = %v, want the first to be %s", queries, want)
+	}
+}
+
+func TestQuerySLOStatus_NoSamples(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(vectorResponse()))
+	}))
+	defer server.Close()
+
+	ds := &openslov1.Datasource{Spec: openslov1.DatasourceSpec{
+		Type:              "mimir",
+		ConnectionDetails: oskov1alpha1.ConnectionDetails{Address: server.URL},
+	}}
+	dsAPI, _, err := NewDatasourceAPI(ds)
+	if err != nil {
+		t.Fatalf("NewDatasourceAPI() error = %v", err)
+	}
+
+	values, err := QuerySLOStatus(context.Background(), dsAPI, createTestSLO("0.999"))
+	if err != nil {
+		t.Fatalf("QuerySLOStatus() error = %v", err)
+	}
+	if values.SLI != nil || values.ErrorBudgetRemaining != nil || values.FastestBurnRate != nil {
+		t.Errorf("expected no values before the rules are evaluated, got %+v", values)
+	}
+}
+
+func TestNewDatasourceAPI_UnsupportedType(t *testing.T) {
+	ds := &openslov1.Datasource{Spec: openslov1.DatasourceSpec{Type: "graphite"}}
+	if _, _, err := NewDatasourceAPI(ds); err == nil {
+		t.Error("NewDatasourceAPI() expected an error for an unsupported datasource type")
+	}
+}
+
+func TestBudgetExhaustedThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       float64
+		wantErr    bool
+	}{
+		{name: "default", want: 0},
+		{name: "annotation", annotation: "0.25", want: 0.25},
+		{name: "not a number", annotation: "10%", wantErr: true},
+		{name: "whole budget", annotation: "1", wantErr: true},
+		{name: "negative", annotation: "-0.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := createTestSLO("0.999")
+			if tt.annotation != "" {
+				slo.Annotations = map[string]string{"osko.dev/budgetExhaustedThreshold": tt.annotation}
+			}
+			got, err := BudgetExhaustedThreshold(slo, config.Default())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BudgetExhaustedThreshold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BudgetExhaustedThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/oskoperator/osko/internal/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleValidationError names a generated rule the ruler would reject
+type RuleValidationError struct {
+	Group string
+	Rule  string
+	Err   error
+}
+
+func (e *RuleValidationError) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("rule group %q is invalid: %v", e.Group, e.Err)
+	}
+	return fmt.Sprintf("rule %q in group %q is invalid: %v", e.Rule, e.Group, e.Err)
+}
+
+func (e *RuleValidationError) Unwrap() []error { return []error{e.Err, errors.ErrInvalidRule} }
+
+// ValidateRuleGroups parses every expression with the PromQL parser and lints the groups with the
+// Prometheus rule file validation, so rules the ruler would reject are caught before they are written
+func ValidateRuleGroups(groups []rulefmt.RuleGroup) error {
+	var errs []error
+	for _, g := range groups {
+		for _, r := range g.Rules {
+			if _, err := parser.ParseExpr(r.Expr.Value); err != nil {
+				errs = append(errs, &RuleValidationError{Group: g.Name, Rule: ruleName(r), Err: err})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return stderrors.Join(errs...)
+	}
+
+	content, err := yaml.Marshal(rulefmt.RuleGroups{Groups: groups})
+	if err != nil {
+		return err
+	}
+	if _, lintErrs := rulefmt.Parse(content); len(lintErrs) > 0 {
+		for _, lintErr := range lintErrs {
+			var ruleErr *rulefmt.Error
+			if stderrors.As(lintErr, &ruleErr) {
+				errs = append(errs, &RuleValidationError{Group: ruleErr.Group, Rule: ruleErr.RuleName, Err: ruleErr.Err.Unwrap()})
+				continue
+			}
+			errs = append(errs, &RuleValidationError{Err: lintErr})
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// ValidatePrometheusRule validates the groups of a PrometheusRule the same way as ValidateRuleGroups
+func ValidatePrometheusRule(rule *monitoringv1.PrometheusRule) error {
+	groups := make([]rulefmt.RuleGroup, 0, len(rule.Spec.Groups))
+	for _, g := range rule.Spec.Groups {
+		group := rulefmt.RuleGroup{Name: g.Name}
+		for _, r := range g.Rules {
+			node := rulefmt.RuleNode{
+				Expr:        yaml.Node{Kind: yaml.ScalarNode, Value: r.Expr.String()},
+				Labels:      r.Labels,
+				Annotations: r.Annotations,
+			}
+			if r.Alert != "" {
+				node.Alert = yaml.Node{Kind: yaml.ScalarNode, Value: r.Alert}
+			}
+			if r.Record != "" {
+				node.Record = yaml.Node{Kind: yaml.ScalarNode, Value: r.Record}
+			}
+			if r.For != nil {
+				d, err := model.ParseDuration(string(*r.For))
+				if err != nil {
+					return &RuleValidationError{Group: g.Name, Rule: ruleName(node), Err: err}
+				}
+				node.For = d
+			}
+			if r.KeepFiringFor != nil {
+				d, err := model.ParseDuration(string(*r.KeepFiringFor))
+				if err != nil {
+					return &RuleValidationError{Group: g.Name, Rule: ruleName(node), Err: err}
+				}
+				node.KeepFiringFor = d
+			}
+			group.Rules = append(group.Rules, node)
+		}
+		groups = append(groups, group)
+	}
+	return ValidateRuleGroups(groups)
+}
+
+func ruleName(r rulefmt.RuleNode) string {
+	if r.Alert.Value != "" {
+		return r.Alert.Value
+	}
+	return r.Record.Value
+}
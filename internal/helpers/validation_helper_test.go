@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func scalar(value string) yaml.Node {
+	return yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+func TestValidateRuleGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   []rulefmt.RuleGroup
+		wantRule string
+	}{
+		{
+			name: "valid",
+			groups: []rulefmt.RuleGroup{{
+				Name: "test-slo_sli_total",
+				Rules: []rulefmt.RuleNode{
+					{Record: scalar("osko_sli_total"), Expr: scalar(`sum(rate(http_requests_total[5m]))`)},
+					{Alert: scalar("test-slo_alert"), Expr: scalar(`osko_sli_total > 1`), Annotations: map[string]string{"summary": "SLO Burn Rate Alert"}},
+				},
+			}},
+		},
+		{
+			name: "unparsable expression",
+			groups: []rulefmt.RuleGroup{{
+				Name:  "test-slo_sli_total",
+				Rules: []rulefmt.RuleNode{{Record: scalar("osko_sli_total"), Expr: scalar(`sum(rate(http_requests_total[5m])`)}},
+			}},
+			wantRule: "osko_sli_total",
+		},
+		{
+			name: "invalid record name",
+			groups: []rulefmt.RuleGroup{{
+				Name:  "test-slo_sli_total",
+				Rules: []rulefmt.RuleNode{{Record: scalar("osko sli total"), Expr: scalar(`vector(1)`)}},
+			}},
+			wantRule: "osko sli total",
+		},
+		{
+			name: "invalid annotation template",
+			groups: []rulefmt.RuleGroup{{
+				Name:  "test-slo_slo_alert",
+				Rules: []rulefmt.RuleNode{{Alert: scalar("test-slo_alert"), Expr: scalar(`vector(1)`), Annotations: map[string]string{"summary": "{{ $value"}}},
+			}},
+			wantRule: "test-slo_alert",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRuleGroups(tt.groups)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("ValidateRuleGroups() unexpected error = %v", err)
+				}
+				return
+			}
+
+			if !stderrors.Is(err, errors.ErrInvalidRule) {
+				t.Fatalf("ValidateRuleGroups() error = %v, want ErrInvalidRule", err)
+			}
+			var ruleErr *RuleValidationError
+			if !stderrors.As(err, &ruleErr) {
+				t.Fatalf("ValidateRuleGroups() error = %v, want a RuleValidationError", err)
+			}
+			if ruleErr.Rule != tt.wantRule || ruleErr.Group != tt.groups[0].Name {
+				t.Errorf("error names rule %q in group %q, want %q in %q", ruleErr.Rule, ruleErr.Group, tt.wantRule, tt.groups[0].Name)
+			}
+		})
+	}
+}
+
+func TestCreatePrometheusRule_RejectsInvalidQuery(t *testing.T) {
+	sli := createTestSLI()
+	sli.Spec.RatioMetric.Good.MetricSource.Spec.Query = `http_requests_total{code=~"2.."`
+
+	_, err := CreatePrometheusRule(createTestSLO("0.999"), sli, config.Default())
+	if !stderrors.Is(err, errors.ErrInvalidRule) {
+		t.Fatalf("CreatePrometheusRule() error = %v, want ErrInvalidRule", err)
+	}
+	if !strings.Contains(err.Error(), `"osko_sli_good"`) {
+		t.Errorf("expected the error to name the offending rule, got %v", err)
+	}
+}
+
+func TestValidatePrometheusRule(t *testing.T) {
+	rule, err := CreatePrometheusRule(createTestSLO("0.999"), createTestSLI(), config.Default())
+	if err != nil {
+		t.Fatalf("CreatePrometheusRule() error = %v", err)
+	}
+	rule.Spec.Groups[0].Rules[0].Expr = intstr.FromString("vector(")
+	if err := ValidatePrometheusRule(rule); err == nil {
+		t.Error("ValidatePrometheusRule() expected an error after breaking an expression")
+	}
+}
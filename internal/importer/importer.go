@@ -0,0 +1,289 @@
+// Package importer converts the SLO definitions of other tools, Sloth PrometheusServiceLevels and
+// Pyrra ServiceLevelObjectives, into OpenSLO SLOs and SLIs the operator generates the same kind of rules for.
+//
+// The Sloth and Pyrra types only hold the fields the importer reads, they are no CRDs of the operator.
+// +kubebuilder:skip
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrUnsupportedKind is returned for documents that are neither Sloth nor Pyrra SLO definitions
+var ErrUnsupportedKind = errors.New("unsupported kind")
+
+// DefaultWindow is the time window of the imported SLOs whose definitions set none, the default of Sloth
+const DefaultWindow = "30d"
+
+// DefaultMetricSourceType is the metric source type of the imported SLIs
+const DefaultMetricSourceType = "Mimir"
+
+// labelPrefix is the prefix of the SLO labels the operator adds to the generated rules
+const labelPrefix = "label.osko.dev/"
+
+// reservedLabels are the labels the operator sets on the generated rules itself
+var reservedLabels = []string{"namespace", "service", "sli_name", "slo_name", "window", "severity", "long_window", "short_window"}
+
+// windowPlaceholder matches the window template variable of Sloth queries
+var windowPlaceholder = regexp.MustCompile(`\{\{\s*\.window\s*\}\}`)
+
+// Options configure the imported objects
+type Options struct {
+	// Namespace of the objects whose definitions set none
+	Namespace string
+	// Datasource is set as the osko.dev/datasourceRef of the SLOs, the namespace default applies when empty
+	Datasource string
+	// Window is the time window of the SLOs whose definitions set none, DefaultWindow when empty
+	Window string
+	// MetricSourceType is the metric source type of the SLIs, DefaultMetricSourceType when empty
+	MetricSourceType string
+}
+
+// Result are the objects converted from a definition, along with the settings of it the operator has no equivalent for
+type Result struct {
+	SLOs     []openslov1.SLO
+	SLIs     []openslov1.SLI
+	Warnings []string
+}
+
+// Convert converts a Sloth PrometheusServiceLevel or a Pyrra ServiceLevelObjective document.
+// SLOs that cannot be converted are reported in the error, the others are converted nonetheless.
+func Convert(content []byte, opts Options) (*Result, error) {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(content, &typeMeta); err != nil {
+		return nil, err
+	}
+
+	switch typeMeta.GroupVersionKind() {
+	case SlothGroupVersion.WithKind("PrometheusServiceLevel"):
+		sl := &SlothServiceLevel{}
+		if err := yaml.Unmarshal(content, sl); err != nil {
+			return nil, err
+		}
+		return FromSloth(sl, opts)
+	case PyrraGroupVersion.WithKind("ServiceLevelObjective"):
+		slo := &PyrraSLO{}
+		if err := yaml.Unmarshal(content, slo); err != nil {
+			return nil, err
+		}
+		return FromPyrra(slo, opts)
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrUnsupportedKind, typeMeta.APIVersion, typeMeta.Kind)
+}
+
+// objective is the tool-independent description of an imported SLO
+type objective struct {
+	name        string
+	namespace   string
+	service     string
+	description string
+	// target in percent
+	target   string
+	window   string
+	alerting bool
+	labels   map[string]string
+	good     string
+	bad      string
+	total    string
+}
+
+// add converts an objective into an SLO and the SLI it references
+func (r *Result) add(o objective, opts Options) error {
+	if errs := validation.IsDNS1123Subdomain(o.name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", o.name, strings.Join(errs, ", "))
+	}
+	target, err := percentToRatio(o.target)
+	if err != nil {
+		return err
+	}
+	if o.window == "" {
+		o.window = opts.Window
+	}
+	if o.window == "" {
+		o.window = DefaultWindow
+	}
+	window, err := convertDuration(o.window)
+	if err != nil {
+		return err
+	}
+	namespace := o.namespace
+	if namespace == "" {
+		namespace = opts.Namespace
+	}
+	sourceType := opts.MetricSourceType
+	if sourceType == "" {
+		sourceType = DefaultMetricSourceType
+	}
+	metricSource := func(query string) openslov1.MetricSpec {
+		if query == "" {
+			return openslov1.MetricSpec{}
+		}
+		return openslov1.MetricSpec{MetricSource: openslov1.MetricSource{Type: sourceType, Spec: openslov1.MetricSourceSpec{Query: query}}}
+	}
+
+	sliName := fmt.Sprintf("%s-sli", o.name)
+	sli := openslov1.SLI{
+		TypeMeta:   metav1.TypeMeta{APIVersion: openslov1.GroupVersion.String(), Kind: "SLI"},
+		ObjectMeta: metav1.ObjectMeta{Name: sliName, Namespace: namespace},
+		Spec: openslov1.SLISpec{
+			Description: openslov1.Description(o.description),
+			RatioMetric: openslov1.RatioMetricSpec{
+				Counter: true,
+				Good:    metricSource(o.good),
+				Bad:     metricSource(o.bad),
+				Total:   metricSource(o.total),
+			},
+		},
+	}
+
+	annotations := map[string]string{"osko.dev/magicAlerting": "false"}
+	if o.alerting {
+		annotations["osko.dev/magicAlerting"] = "true"
+	}
+	if opts.Datasource != "" {
+		annotations["osko.dev/datasourceRef"] = opts.Datasource
+	}
+	var labels map[string]string
+	for key, value := range o.labels {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[labelPrefix+key] = value
+	}
+
+	slo := openslov1.SLO{
+		TypeMeta: metav1.TypeMeta{APIVersion: openslov1.GroupVersion.String(), Kind: "SLO"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        o.name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: openslov1.SLOSpec{
+			Description:     openslov1.Description(o.description),
+			Service:         o.service,
+			IndicatorRef:    &sliName,
+			TimeWindow:      []openslov1.TimeWindowSpec{{Duration: openslov1.Duration(window), IsRolling: true}},
+			BudgetingMethod: "Occurrences",
+			Objectives:      []openslov1.ObjectivesSpec{{Target: target}},
+		},
+	}
+
+	r.SLOs = append(r.SLOs, slo)
+	r.SLIs = append(r.SLIs, sli)
+	return nil
+}
+
+func (r *Result) warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// routingLabels adds the labels to the routing hints of an SLO, skipping the ones the operator sets itself or
+// that are no valid Kubernetes labels
+func (r *Result) routingLabels(into map[string]string, labels map[string]string, object string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := labels[key]
+		if slices.Contains(reservedLabels, key) {
+			r.warn("%s: label %s is set by the operator, skipped", object, key)
+			continue
+		}
+		if errs := validation.IsQualifiedName(labelPrefix + key); len(errs) > 0 {
+			r.warn("%s: label %s is no valid label key, skipped: %s", object, key, strings.Join(errs, ", "))
+			continue
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			r.warn("%s: value %q of label %s is no valid label value, skipped: %s", object, value, key, strings.Join(errs, ", "))
+			continue
+		}
+		into[key] = value
+	}
+}
+
+// seriesSelector returns the series selector a query takes the rate of, the operator aggregates the rate of
+// the SLI queries itself. Queries may be series selectors or the sum of the rate or increase of one.
+func seriesSelector(query string) (string, error) {
+	expr, err := parser.ParseExpr(windowPlaceholder.ReplaceAllString(query, "5m"))
+	if err != nil {
+		return "", fmt.Errorf("query %q: %w", query, err)
+	}
+
+	expr = unwrap(expr)
+	if agg, ok := expr.(*parser.AggregateExpr); ok && agg.Op == parser.SUM {
+		expr = unwrap(agg.Expr)
+	}
+	if call, ok := expr.(*parser.Call); ok && (call.Func.Name == "rate" || call.Func.Name == "increase") {
+		if matrix, ok := call.Args[0].(*parser.MatrixSelector); ok {
+			expr = matrix.VectorSelector
+		}
+	}
+	if selector, ok := expr.(*parser.VectorSelector); ok && selector.OriginalOffset == 0 && selector.Timestamp == nil && selector.StartOrEnd == 0 {
+		return selector.String(), nil
+	}
+	return "", fmt.Errorf("query %q is no series selector or the sum of the rate of one", query)
+}
+
+func unwrap(expr parser.Expr) parser.Expr {
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expr
+	}
+}
+
+// percentToRatio converts a percentage into the exact decimal ratio the objective target takes
+func percentToRatio(percent string) (string, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(percent))
+	if !ok {
+		return "", fmt.Errorf("objective %q is no number", percent)
+	}
+	r.Quo(r, big.NewRat(100, 1))
+	if r.Sign() <= 0 || r.Cmp(big.NewRat(1, 1)) >= 0 {
+		return "", fmt.Errorf("objective %s%% must be greater than 0 and less than 100", percent)
+	}
+	for prec := 1; prec < 20; prec++ {
+		s := r.FloatString(prec)
+		if exact, _ := new(big.Rat).SetString(s); exact.Cmp(r) == 0 {
+			return s, nil
+		}
+	}
+	return r.FloatString(20), nil
+}
+
+// convertDuration converts a Prometheus duration into the largest whole unit the OpenSLO duration pattern allows
+func convertDuration(duration string) (string, error) {
+	d, err := model.ParseDuration(duration)
+	if err != nil {
+		return "", fmt.Errorf("window %q: %w", duration, err)
+	}
+	for _, unit := range []struct {
+		d      time.Duration
+		suffix string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if time.Duration(d) >= unit.d && time.Duration(d)%unit.d == 0 {
+			return fmt.Sprintf("%d%s", time.Duration(d)/unit.d, unit.suffix), nil
+		}
+	}
+	return "", fmt.Errorf("window %q is no whole number of seconds", duration)
+}
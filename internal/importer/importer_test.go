@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testSlothServiceLevel = `apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: myservice
+  namespace: shop
+spec:
+  service: myservice
+  labels:
+    owner: myteam
+  slos:
+    - name: requests_availability
+      objective: 99.9
+      description: Common SLO based on availability for HTTP request responses.
+      labels:
+        tier: "2"
+      sli:
+        events:
+          errorQuery: sum(rate(http_request_duration_seconds_count{job="myservice",code=~"(5..|429)"}[{{.window}}]))
+          totalQuery: sum(rate(http_request_duration_seconds_count{job="myservice"}[{{.window}}]))
+      alerting:
+        labels:
+          category: availability
+          severity: critical
+        pageAlert:
+          disable: true
+        ticketAlert:
+          disable: true
+    - name: raw
+      objective: 99
+      sli:
+        raw:
+          errorRatioQuery: sum(rate(errors[{{.window}}])) / sum(rate(total[{{.window}}]))
+`
+
+const testPyrraSLO = `apiVersion: pyrra.dev/v1alpha1
+kind: ServiceLevelObjective
+metadata:
+  name: api-latency
+  labels:
+    role: alert-rules
+    pyrra.dev/team: operations
+spec:
+  target: "95"
+  window: 4w
+  indicator:
+    latency:
+      success:
+        metric: http_request_duration_seconds_bucket{job="api",le="0.5"}
+      total:
+        metric: http_request_duration_seconds_count{job="api"}
+`
+
+func TestConvertSloth(t *testing.T) {
+	result, err := Convert([]byte(testSlothServiceLevel), Options{Namespace: "default", Datasource: "mimir"})
+	if err == nil || !strings.Contains(err.Error(), "SLO raw: raw SLIs are not supported") {
+		t.Errorf("expected the raw SLI to be reported, got %v", err)
+	}
+	if len(result.SLOs) != 1 || len(result.SLIs) != 1 {
+		t.Fatalf("expected one SLO and SLI, got %+v", result)
+	}
+
+	slo, sli := result.SLOs[0], result.SLIs[0]
+	if slo.Name != "myservice-requests-availability" || slo.Namespace != "shop" || slo.Spec.Service != "myservice" {
+		t.Errorf("unexpected metadata %+v service %s", slo.ObjectMeta, slo.Spec.Service)
+	}
+	if slo.Spec.Objectives[0].Target != "0.999" || slo.Spec.TimeWindow[0].Duration != "30d" {
+		t.Errorf("unexpected objective %+v window %+v", slo.Spec.Objectives, slo.Spec.TimeWindow)
+	}
+	if slo.Annotations["osko.dev/magicAlerting"] != "false" || slo.Annotations["osko.dev/datasourceRef"] != "mimir" {
+		t.Errorf("unexpected annotations %v", slo.Annotations)
+	}
+	wantLabels := map[string]string{"label.osko.dev/owner": "myteam", "label.osko.dev/tier": "2", "label.osko.dev/category": "availability"}
+	if len(slo.Labels) != len(wantLabels) {
+		t.Errorf("labels = %v, want %v", slo.Labels, wantLabels)
+	}
+	for key, value := range wantLabels {
+		if slo.Labels[key] != value {
+			t.Errorf("label %s = %q, want %q", key, slo.Labels[key], value)
+		}
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "label severity is set by the operator") {
+		t.Errorf("unexpected warnings %v", result.Warnings)
+	}
+
+	if *slo.Spec.IndicatorRef != sli.Name {
+		t.Errorf("indicatorRef = %s, want %s", *slo.Spec.IndicatorRef, sli.Name)
+	}
+	ratio := sli.Spec.RatioMetric
+	if ratio.Bad.MetricSource.Spec.Query != `http_request_duration_seconds_count{code=~"(5..|429)",job="myservice"}` ||
+		ratio.Total.MetricSource.Spec.Query != `http_request_duration_seconds_count{job="myservice"}` ||
+		ratio.Total.MetricSource.Type != DefaultMetricSourceType || !ratio.Counter {
+		t.Errorf("unexpected ratio metric %+v", ratio)
+	}
+}
+
+func TestConvertPyrra(t *testing.T) {
+	result, err := Convert([]byte(testPyrraSLO), Options{Namespace: "default", MetricSourceType: "Prometheus"})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	slo, sli := result.SLOs[0], result.SLIs[0]
+	if slo.Name != "api-latency" || slo.Namespace != "default" || slo.Spec.Service != "api-latency" {
+		t.Errorf("unexpected metadata %+v service %s", slo.ObjectMeta, slo.Spec.Service)
+	}
+	if slo.Spec.Objectives[0].Target != "0.95" || slo.Spec.TimeWindow[0].Duration != "28d" {
+		t.Errorf("unexpected objective %+v window %+v", slo.Spec.Objectives, slo.Spec.TimeWindow)
+	}
+	if slo.Annotations["osko.dev/magicAlerting"] != "true" {
+		t.Errorf("unexpected annotations %v", slo.Annotations)
+	}
+	if len(slo.Labels) != 1 || slo.Labels["label.osko.dev/team"] != "operations" {
+		t.Errorf("unexpected labels %v", slo.Labels)
+	}
+	ratio := sli.Spec.RatioMetric
+	if ratio.Good.MetricSource.Spec.Query != `http_request_duration_seconds_bucket{job="api",le="0.5"}` || ratio.Good.MetricSource.Type != "Prometheus" {
+		t.Errorf("unexpected ratio metric %+v", ratio)
+	}
+}
+
+func TestConvertUnsupportedKind(t *testing.T) {
+	_, err := Convert([]byte("apiVersion: openslo.com/v1\nkind: SLO\n"), Options{})
+	if !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("expected ErrUnsupportedKind, got %v", err)
+	}
+}
+
+func TestSeriesSelector(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{query: `http_requests_total{code="500"}`, want: `http_requests_total{code="500"}`},
+		{query: `sum(rate(http_requests_total{code="500"}[{{.window}}]))`, want: `http_requests_total{code="500"}`},
+		{query: `(sum by (job) (increase(http_requests_total[{{ .window }}])))`, want: `http_requests_total`},
+		{query: `sum(rate(a[{{.window}}])) + sum(rate(b[{{.window}}]))`, wantErr: true},
+		{query: `max(rate(a[{{.window}}]))`, wantErr: true},
+		{query: `a offset 5m`, wantErr: true},
+		{query: `sum(rate(a[5m]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := seriesSelector(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("seriesSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("seriesSelector() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentToRatio(t *testing.T) {
+	tests := []struct {
+		percent string
+		want    string
+		wantErr bool
+	}{
+		{percent: "99.9", want: "0.999"},
+		{percent: "95", want: "0.95"},
+		{percent: "99.95", want: "0.9995"},
+		{percent: "100", wantErr: true},
+		{percent: "0", wantErr: true},
+		{percent: "high", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.percent, func(t *testing.T) {
+			got, err := percentToRatio(tt.percent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("percentToRatio() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("percentToRatio() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     string
+		wantErr  bool
+	}{
+		{duration: "2w", want: "14d"},
+		{duration: "30d", want: "30d"},
+		{duration: "36h", want: "36h"},
+		{duration: "1h30m", want: "90m"},
+		{duration: "30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			got, err := convertDuration(tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("convertDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PyrraGroupVersion is the API version of the Pyrra ServiceLevelObjective
+var PyrraGroupVersion = schema.GroupVersion{Group: "pyrra.dev", Version: "v1alpha1"}
+
+// pyrraLabelPrefix is the prefix of the labels Pyrra propagates from the ServiceLevelObjective to its rules
+const pyrraLabelPrefix = "pyrra.dev/"
+
+// PyrraSLO is a Pyrra ServiceLevelObjective, the fields the importer reads
+type PyrraSLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PyrraSLOSpec `json:"spec,omitempty"`
+}
+
+type PyrraSLOSpec struct {
+	Description string         `json:"description,omitempty"`
+	Target      string         `json:"target"`
+	Window      string         `json:"window"`
+	Indicator   PyrraIndicator `json:"indicator"`
+	Alerting    PyrraAlerting  `json:"alerting,omitempty"`
+}
+
+type PyrraIndicator struct {
+	Ratio         *PyrraRatioIndicator         `json:"ratio,omitempty"`
+	Latency       *PyrraLatencyIndicator       `json:"latency,omitempty"`
+	LatencyNative *PyrraNativeLatencyIndicator `json:"latencyNative,omitempty"`
+	BoolGauge     *PyrraBoolGaugeIndicator     `json:"bool_gauge,omitempty"`
+}
+
+type PyrraRatioIndicator struct {
+	Errors   PyrraQuery `json:"errors"`
+	Total    PyrraQuery `json:"total"`
+	Grouping []string   `json:"grouping,omitempty"`
+}
+
+type PyrraLatencyIndicator struct {
+	Success  PyrraQuery `json:"success"`
+	Total    PyrraQuery `json:"total"`
+	Grouping []string   `json:"grouping,omitempty"`
+}
+
+type PyrraNativeLatencyIndicator struct {
+	Latency string     `json:"latency"`
+	Total   PyrraQuery `json:"total"`
+}
+
+type PyrraBoolGaugeIndicator struct {
+	PyrraQuery `json:",inline"`
+	Grouping   []string `json:"grouping,omitempty"`
+}
+
+type PyrraQuery struct {
+	Metric string `json:"metric"`
+}
+
+type PyrraAlerting struct {
+	Disabled  *bool  `json:"disabled,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Burnrates *bool  `json:"burnrates,omitempty"`
+	Absent    *bool  `json:"absent,omitempty"`
+}
+
+// FromPyrra converts a ServiceLevelObjective into an SLO and SLI of the same name, the SLO service is its name as well.
+// Its pyrra.dev/<key> labels become routing labels of the SLO, the burn rate alerts are turned on unless alerting
+// or the burn rate alerts are disabled.
+func FromPyrra(p *PyrraSLO, opts Options) (*Result, error) {
+	result := &Result{}
+	object := fmt.Sprintf("ServiceLevelObjective %s", p.Name)
+	if err := result.fromPyrraSLO(p, object, opts); err != nil {
+		return result, fmt.Errorf("%s: %w", object, err)
+	}
+	return result, nil
+}
+
+func (r *Result) fromPyrraSLO(p *PyrraSLO, object string, opts Options) error {
+	var good, bad, total string
+	var grouping []string
+	indicator := p.Spec.Indicator
+	switch {
+	case indicator.Ratio != nil:
+		bad, total, grouping = indicator.Ratio.Errors.Metric, indicator.Ratio.Total.Metric, indicator.Ratio.Grouping
+	case indicator.Latency != nil:
+		good, total, grouping = indicator.Latency.Success.Metric, indicator.Latency.Total.Metric, indicator.Latency.Grouping
+	case indicator.LatencyNative != nil:
+		return fmt.Errorf("latencyNative indicators are not supported, native histograms cannot be expressed as good and total queries")
+	case indicator.BoolGauge != nil:
+		return fmt.Errorf("bool_gauge indicators are not supported, the operator computes the SLI from counters")
+	default:
+		return fmt.Errorf("the ServiceLevelObjective has no indicator")
+	}
+	for _, query := range []*string{&good, &bad, &total} {
+		if *query == "" {
+			continue
+		}
+		selector, err := seriesSelector(*query)
+		if err != nil {
+			return err
+		}
+		*query = selector
+	}
+	if len(grouping) > 0 {
+		r.warn("%s: the grouping by %s is not kept, the operator records a single SLI per SLO", object, strings.Join(grouping, ", "))
+	}
+
+	alerting := p.Spec.Alerting
+	enabled := (alerting.Disabled == nil || !*alerting.Disabled) && (alerting.Burnrates == nil || *alerting.Burnrates)
+	if alerting.Name != "" {
+		r.warn("%s: the alert name %s is not kept, the operator names the alerts after the SLO", object, alerting.Name)
+	}
+	if alerting.Absent != nil && *alerting.Absent {
+		r.warn("%s: absent alerts are not supported", object)
+	}
+
+	hints := map[string]string{}
+	for key, value := range p.Labels {
+		if strings.HasPrefix(key, pyrraLabelPrefix) {
+			hints[strings.TrimPrefix(key, pyrraLabelPrefix)] = value
+		}
+	}
+	labels := map[string]string{}
+	r.routingLabels(labels, hints, object)
+
+	return r.add(objective{
+		name:        p.Name,
+		namespace:   p.Namespace,
+		service:     p.Name,
+		description: p.Spec.Description,
+		target:      p.Spec.Target,
+		window:      p.Spec.Window,
+		alerting:    enabled,
+		labels:      labels,
+		good:        good,
+		bad:         bad,
+		total:       total,
+	}, opts)
+}
@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SlothGroupVersion is the API version of the Sloth PrometheusServiceLevel
+var SlothGroupVersion = schema.GroupVersion{Group: "sloth.slok.dev", Version: "v1"}
+
+// SlothServiceLevel is a Sloth PrometheusServiceLevel, the fields the importer reads
+type SlothServiceLevel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SlothServiceLevelSpec `json:"spec,omitempty"`
+}
+
+type SlothServiceLevelSpec struct {
+	Service string            `json:"service"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	SLOs    []SlothSLO        `json:"slos,omitempty"`
+}
+
+type SlothSLO struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Objective   json.Number       `json:"objective"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	SLI         SlothSLI          `json:"sli"`
+	Alerting    SlothAlerting     `json:"alerting,omitempty"`
+}
+
+type SlothSLI struct {
+	Raw    *SlothSLIRaw    `json:"raw,omitempty"`
+	Events *SlothSLIEvents `json:"events,omitempty"`
+	Plugin *SlothSLIPlugin `json:"plugin,omitempty"`
+}
+
+type SlothSLIRaw struct {
+	ErrorRatioQuery string `json:"errorRatioQuery"`
+}
+
+type SlothSLIEvents struct {
+	ErrorQuery string `json:"errorQuery"`
+	TotalQuery string `json:"totalQuery"`
+}
+
+type SlothSLIPlugin struct {
+	ID string `json:"id"`
+}
+
+type SlothAlerting struct {
+	Name        string            `json:"name,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	PageAlert   SlothAlert        `json:"pageAlert,omitempty"`
+	TicketAlert SlothAlert        `json:"ticketAlert,omitempty"`
+}
+
+type SlothAlert struct {
+	Disable     bool              `json:"disable,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// FromSloth converts the SLOs of a PrometheusServiceLevel into an SLO and SLI each, named like the SLO IDs of Sloth.
+// The labels of the service level, of its SLOs and of their alerts become routing labels of the SLOs, the burn rate
+// alerts are turned on unless both the page and ticket alerts are disabled.
+func FromSloth(sl *SlothServiceLevel, opts Options) (*Result, error) {
+	result := &Result{}
+	var errs []error
+	for _, s := range sl.Spec.SLOs {
+		object := fmt.Sprintf("PrometheusServiceLevel %s SLO %s", sl.Name, s.Name)
+		if err := result.fromSlothSLO(sl, s, object, opts); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", object, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func (r *Result) fromSlothSLO(sl *SlothServiceLevel, s SlothSLO, object string, opts Options) error {
+	var bad, total string
+	switch {
+	case s.SLI.Events != nil:
+		var err error
+		if bad, err = seriesSelector(s.SLI.Events.ErrorQuery); err != nil {
+			return fmt.Errorf("errorQuery: %w", err)
+		}
+		if total, err = seriesSelector(s.SLI.Events.TotalQuery); err != nil {
+			return fmt.Errorf("totalQuery: %w", err)
+		}
+	case s.SLI.Raw != nil:
+		return fmt.Errorf("raw SLIs are not supported, the operator computes the error ratio from error and total queries")
+	case s.SLI.Plugin != nil:
+		return fmt.Errorf("SLI plugin %s is not supported", s.SLI.Plugin.ID)
+	default:
+		return fmt.Errorf("the SLO has no SLI")
+	}
+
+	alerting := s.Alerting
+	if alerting.PageAlert.Disable != alerting.TicketAlert.Disable {
+		r.warn("%s: the page and ticket alerts can only be turned on or off together, both are on", object)
+	}
+	if alerting.Name != "" {
+		r.warn("%s: the alert name %s is not kept, the operator names the alerts after the SLO", object, alerting.Name)
+	}
+	if len(alerting.Annotations) > 0 || len(alerting.PageAlert.Annotations) > 0 || len(alerting.TicketAlert.Annotations) > 0 {
+		r.warn("%s: alert annotations are not kept", object)
+	}
+	if len(alerting.PageAlert.Labels) > 0 || len(alerting.TicketAlert.Labels) > 0 {
+		r.warn("%s: the labels of the page and ticket alerts are not kept, the alertingTool of the SLO sets their severity", object)
+	}
+
+	labels := map[string]string{}
+	r.routingLabels(labels, sl.Spec.Labels, object)
+	r.routingLabels(labels, s.Labels, object)
+	r.routingLabels(labels, alerting.Labels, object)
+
+	return r.add(objective{
+		name:        strings.ToLower(strings.ReplaceAll(sl.Spec.Service+"-"+s.Name, "_", "-")),
+		namespace:   sl.Namespace,
+		service:     sl.Spec.Service,
+		description: s.Description,
+		target:      s.Objective.String(),
+		alerting:    !alerting.PageAlert.Disable || !alerting.TicketAlert.Disable,
+		labels:      labels,
+		bad:         bad,
+		total:       total,
+	}, opts)
+}
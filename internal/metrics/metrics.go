@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	stderrors "errors"
+
+	"github.com/oskoperator/osko/internal/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ruleTypeRecording = "recording"
+	ruleTypeAlerting  = "alerting"
+)
+
+var (
+	generatedRules = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "osko_slo_generated_rules",
+			Help: "Number of rules generated for an SLO, per rule type",
+		},
+		[]string{"namespace", "slo", "type"},
+	)
+
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osko_reconcile_errors_total",
+			Help: "Number of reconcile errors per controller, classified by error type",
+		},
+		[]string{"controller", "type"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(generatedRules, reconcileErrors)
+}
+
+// SetGeneratedRules records how many recording and alerting rules were generated for an SLO
+func SetGeneratedRules(namespace, slo string, rule *monitoringv1.PrometheusRule) {
+	var recording, alerting int
+	for _, group := range rule.Spec.Groups {
+		for _, r := range group.Rules {
+			if r.Alert != "" {
+				alerting++
+			} else {
+				recording++
+			}
+		}
+	}
+	generatedRules.WithLabelValues(namespace, slo, ruleTypeRecording).Set(float64(recording))
+	generatedRules.WithLabelValues(namespace, slo, ruleTypeAlerting).Set(float64(alerting))
+}
+
+// DeleteGeneratedRules drops the generated rule series of a deleted SLO
+func DeleteGeneratedRules(namespace, slo string) {
+	generatedRules.DeleteLabelValues(namespace, slo, ruleTypeRecording)
+	generatedRules.DeleteLabelValues(namespace, slo, ruleTypeAlerting)
+}
+
+// ErrorType classifies a reconcile error by its internal/errors type
+func ErrorType(err error) string {
+	var reconcileErr *errors.ReconcileError
+	if !stderrors.As(err, &reconcileErr) {
+		return "unclassified"
+	}
+	switch reconcileErr.Type {
+	case errors.ErrTransient:
+		return "transient"
+	case errors.ErrPermanent:
+		return "permanent"
+	case errors.ErrDependencyNotReady:
+		return "dependency_not_ready"
+	default:
+		return "unclassified"
+	}
+}
+
+// RecordReconcileError counts a reconcile error of the given controller
+func RecordReconcileError(controller string, err error) {
+	if err == nil {
+		return
+	}
+	reconcileErrors.WithLabelValues(controller, ErrorType(err)).Inc()
+}
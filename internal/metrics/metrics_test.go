@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"transient", errors.Transient(fmt.Errorf("boom"), time.Second), "transient"},
+		{"permanent", errors.Permanent(fmt.Errorf("boom")), "permanent"},
+		{"terminal permanent", reconcile.TerminalError(errors.Permanent(fmt.Errorf("boom"))), "permanent"},
+		{"dependency not ready", errors.DependencyNotReady(fmt.Errorf("boom")), "dependency_not_ready"},
+		{"plain error", fmt.Errorf("boom"), "unclassified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorType(tt.err); got != tt.want {
+				t.Errorf("ErrorType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetGeneratedRules(t *testing.T) {
+	rule := &monitoringv1.PrometheusRule{
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{Rules: []monitoringv1.Rule{{Record: "osko_sli_total"}, {Record: "osko_sli_good"}}},
+				{Rules: []monitoringv1.Rule{{Alert: "test-slo_alert_page_critical"}}},
+			},
+		},
+	}
+
+	SetGeneratedRules("default", "test-slo", rule)
+	if got := testutil.ToFloat64(generatedRules.WithLabelValues("default", "test-slo", ruleTypeRecording)); got != 2 {
+		t.Errorf("recording rules = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(generatedRules.WithLabelValues("default", "test-slo", ruleTypeAlerting)); got != 1 {
+		t.Errorf("alerting rules = %v, want 1", got)
+	}
+
+	DeleteGeneratedRules("default", "test-slo")
+	if got := testutil.CollectAndCount(generatedRules); got != 0 {
+		t.Errorf("expected no series after deletion, got %d", got)
+	}
+}
+
+func TestSLOCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openslov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	slo := func(namespace, name string, ready metav1.ConditionStatus) *openslov1.SLO {
+		s := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if ready != "" {
+			s.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: ready}}
+		}
+		return s
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		slo("default", "a", metav1.ConditionTrue),
+		slo("default", "b", metav1.ConditionTrue),
+		slo("default", "c", metav1.ConditionFalse),
+		slo("payments", "d", ""),
+	).Build()
+
+	expected := `
+# HELP osko_slos Number of SLOs per namespace by the status of their Ready condition
+# TYPE osko_slos gauge
+osko_slos{namespace="default",ready="False"} 1
+osko_slos{namespace="default",ready="True"} 2
+osko_slos{namespace="payments",ready="Unknown"} 1
+`
+	if err := testutil.CollectAndCompare(NewSLOCollector(reader), strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const sloListTimeout = 5 * time.Second
+
+var slosDesc = prometheus.NewDesc(
+	"osko_slos",
+	"Number of SLOs per namespace by the status of their Ready condition",
+	[]string{"namespace", "ready"},
+	nil,
+)
+
+// SLOCollector counts SLOs by their Ready condition from the manager cache on every scrape
+type SLOCollector struct {
+	Reader client.Reader
+}
+
+// NewSLOCollector creates a collector listing SLOs through the given reader
+func NewSLOCollector(reader client.Reader) *SLOCollector {
+	return &SLOCollector{Reader: reader}
+}
+
+func (c *SLOCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- slosDesc
+}
+
+func (c *SLOCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), sloListTimeout)
+	defer cancel()
+
+	slos := &openslov1.SLOList{}
+	if err := c.Reader.List(ctx, slos); err != nil {
+		ctrllog.Log.WithName("metrics").Error(err, "Failed to list SLOs")
+		ch <- prometheus.NewInvalidMetric(slosDesc, err)
+		return
+	}
+
+	type key struct{ namespace, ready string }
+	counts := map[key]int{}
+	for _, slo := range slos.Items {
+		counts[key{slo.Namespace, readyStatus(slo.Status.Conditions)}]++
+	}
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(slosDesc, prometheus.GaugeValue, float64(count), k.namespace, k.ready)
+	}
+}
+
+func readyStatus(conditions []metav1.Condition) string {
+	if condition := apimeta.FindStatusCondition(conditions, "Ready"); condition != nil {
+		return string(condition.Status)
+	}
+	return string(metav1.ConditionUnknown)
+}
@@ -0,0 +1,56 @@
+package reconciler
+
+import (
+	"context"
+
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ConfigChanges returns a source that triggers the handler whenever the configuration in the store changes, so a
+// controller reconciles its objects again with the new configuration
+func ConfigChanges(store *config.Store, h handler.EventHandler) source.Source {
+	changes := store.Subscribe()
+	events := make(chan event.GenericEvent, 1)
+	go func() {
+		for range changes {
+			select {
+			case events <- event.GenericEvent{Object: &oskov1alpha1.OperatorConfig{}}:
+			default:
+			}
+		}
+	}()
+	return source.Channel(events, h)
+}
+
+// EnqueueAll returns a handler that enqueues every object of the list type in the cluster, whatever the event
+func EnqueueAll(c client.Reader, list client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, objects); err != nil {
+			ctrllog.FromContext(ctx).Error(err, "Failed to list objects to reconcile with the new configuration")
+			return nil
+		}
+		items, err := apimeta.ExtractList(objects)
+		if err != nil {
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}})
+		}
+		return requests
+	})
+}
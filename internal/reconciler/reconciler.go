@@ -0,0 +1,168 @@
+package reconciler
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/metrics"
+	"github.com/oskoperator/osko/internal/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	ReasonTransientError     = "TransientError"
+	ReasonPermanentError     = "PermanentError"
+	ReasonDependencyNotReady = "DependencyNotReady"
+
+	// maxConditionMessageLength keeps long aggregated errors within the condition message limit
+	maxConditionMessageLength = 32768
+)
+
+// Reconciler wraps a controller's reconciler and turns the ReconcileError it returns into the requeue behavior,
+// status conditions and event matching the error type:
+//   - transient and dependency errors are requeued after their RequeueAfter instead of the default backoff
+//   - permanent errors are not requeued until the object changes again
+//
+// Plain errors are passed through to controller-runtime unchanged.
+type Reconciler struct {
+	Name     string
+	Client   client.Client
+	Recorder record.EventRecorder
+	// Object is an empty instance of the reconciled type, used to record the condition and event
+	Object client.Object
+	reconcile.Reconciler
+}
+
+// Wrap creates a Reconciler for the controller with the given name using the client and event recorder of the manager
+func Wrap(mgr ctrl.Manager, name string, object client.Object, r reconcile.Reconciler) *Reconciler {
+	return &Reconciler{
+		Name:       name,
+		Client:     mgr.GetClient(),
+		Recorder:   mgr.GetEventRecorderFor(name + "-controller"),
+		Object:     object,
+		Reconciler: r,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.Reconciler.Reconcile(ctx, req)
+	if err == nil {
+		r.recover(ctx, req)
+		return result, nil
+	}
+	metrics.RecordReconcileError(r.Name, err)
+
+	var reconcileErr *errors.ReconcileError
+	if !stderrors.As(err, &reconcileErr) {
+		return result, err
+	}
+
+	log := ctrllog.FromContext(ctx)
+	reason := Reason(reconcileErr)
+	r.report(ctx, req, reason, err)
+
+	switch reconcileErr.Type {
+	case errors.ErrPermanent:
+		if stderrors.Is(err, reconcile.TerminalError(nil)) {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, reconcile.TerminalError(err)
+	case errors.ErrTransient, errors.ErrDependencyNotReady:
+		if reconcileErr.RequeueAfter <= 0 {
+			return result, err
+		}
+		log.Info("Reconcile failed, requeueing", "reason", reason, "requeueAfter", reconcileErr.RequeueAfter.String(), "error", err.Error())
+		return reconcile.Result{RequeueAfter: reconcileErr.RequeueAfter}, nil
+	default:
+		return result, err
+	}
+}
+
+// report sets the conditions matching the error type on the object and emits a Warning event with the reason of the error.
+// Transient errors mark the object Degraded, dependency errors mark its dependencies unresolved and all of them set Ready to False.
+func (r *Reconciler) report(ctx context.Context, req reconcile.Request, reason string, err error) {
+	if r.Object == nil {
+		return
+	}
+	log := ctrllog.FromContext(ctx)
+
+	obj := r.Object.DeepCopyObject().(client.Object)
+	if getErr := r.Client.Get(ctx, req.NamespacedName, obj); getErr != nil {
+		if !apierrors.IsNotFound(getErr) {
+			log.V(1).Info("Could not get object to report reconcile error", "error", getErr.Error())
+		}
+		return
+	}
+
+	message := err.Error()
+	if len(message) > maxConditionMessageLength {
+		message = message[:maxConditionMessageLength]
+	}
+	// Keep the more specific reason a controller may already have reported for this generation
+	var conditions []metav1.Condition
+	switch reason {
+	case ReasonTransientError:
+		conditions = append(conditions, utils.NewCondition(utils.ConditionDegraded, metav1.ConditionTrue, reason, message))
+	case ReasonDependencyNotReady:
+		if !reportedFalse(obj, utils.ConditionDependenciesResolved) {
+			conditions = append(conditions, utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionFalse, reason, message))
+		}
+	}
+	if !reportedFalse(obj, utils.ConditionReady) {
+		conditions = append(conditions, utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, reason, message))
+	}
+	if utils.SetConditions(obj, conditions...) {
+		if updateErr := r.Client.Status().Update(ctx, obj); updateErr != nil {
+			log.V(1).Info("Could not record reconcile error in status", "error", updateErr.Error())
+		}
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Event(obj, "Warning", reason, message)
+	}
+}
+
+// recover clears the Degraded condition report set for a transient error once a reconcile succeeds again.
+// A Degraded condition with another reason belongs to the controller, which clears it itself.
+func (r *Reconciler) recover(ctx context.Context, req reconcile.Request) {
+	if r.Object == nil {
+		return
+	}
+	obj := r.Object.DeepCopyObject().(client.Object)
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return
+	}
+	degraded := utils.FindCondition(obj, utils.ConditionDegraded)
+	if degraded == nil || degraded.Status != metav1.ConditionTrue || degraded.Reason != ReasonTransientError {
+		return
+	}
+	if utils.SetConditions(obj, utils.NewCondition(utils.ConditionDegraded, metav1.ConditionFalse, utils.ReasonAsExpected, "")) {
+		if err := r.Client.Status().Update(ctx, obj); err != nil {
+			ctrllog.FromContext(ctx).V(1).Info("Could not clear transient error from status", "error", err.Error())
+		}
+	}
+}
+
+func reportedFalse(obj client.Object, conditionType string) bool {
+	condition := utils.FindCondition(obj, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionFalse && condition.ObservedGeneration == obj.GetGeneration()
+}
+
+// Reason returns the condition and event reason for a reconcile error type
+func Reason(err *errors.ReconcileError) string {
+	switch err.Type {
+	case errors.ErrPermanent:
+		return ReasonPermanentError
+	case errors.ErrDependencyNotReady:
+		return ReasonDependencyNotReady
+	default:
+		return ReasonTransientError
+	}
+}
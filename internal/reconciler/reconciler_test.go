@@ -0,0 +1,280 @@
+package reconciler
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/errors"
+	"github.com/oskoperator/osko/internal/utils"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconcilerFunc func(context.Context, reconcile.Request) (reconcile.Result, error)
+
+func (f reconcilerFunc) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return f(ctx, req)
+}
+
+func TestReconciler(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantResult   reconcile.Result
+		wantTerminal bool
+		wantErr      bool
+		wantReason   string
+		// wantCondition is an additional condition the error type sets to True, or to False for dependencies
+		wantCondition string
+	}{
+		{
+			name:          "transient error is requeued after its delay",
+			err:           errors.Transient(fmt.Errorf("connection refused"), 5*time.Second),
+			wantResult:    reconcile.Result{RequeueAfter: 5 * time.Second},
+			wantReason:    ReasonTransientError,
+			wantCondition: utils.ConditionDegraded,
+		},
+		{
+			name:          "dependency error is requeued after its delay",
+			err:           errors.DependencyNotReady(fmt.Errorf("datasource not found")),
+			wantResult:    reconcile.Result{RequeueAfter: 10 * time.Second},
+			wantReason:    ReasonDependencyNotReady,
+			wantCondition: utils.ConditionDependenciesResolved,
+		},
+		{
+			name:         "permanent error is not requeued",
+			err:          errors.Permanent(fmt.Errorf("invalid target")),
+			wantErr:      true,
+			wantTerminal: true,
+			wantReason:   ReasonPermanentError,
+		},
+		{
+			name:    "plain error is passed through",
+			err:     fmt.Errorf("boom"),
+			wantErr: true,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := openslov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "test-slo", Namespace: "default", Generation: 3}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(slo).WithStatusSubresource(slo).Build()
+			recorder := record.NewFakeRecorder(10)
+
+			r := &Reconciler{
+				Name:     "slo",
+				Client:   c,
+				Recorder: recorder,
+				Object:   &openslov1.SLO{},
+				Reconciler: reconcilerFunc(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					return reconcile.Result{}, tt.err
+				}),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-slo", Namespace: "default"}}
+			result, err := r.Reconcile(context.Background(), req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := stderrors.Is(err, reconcile.TerminalError(nil)); got != tt.wantTerminal {
+				t.Errorf("Reconcile() terminal = %v, want %v", got, tt.wantTerminal)
+			}
+			if result != tt.wantResult {
+				t.Errorf("Reconcile() result = %+v, want %+v", result, tt.wantResult)
+			}
+
+			updated := &openslov1.SLO{}
+			if err := c.Get(context.Background(), req.NamespacedName, updated); err != nil {
+				t.Fatal(err)
+			}
+			condition := apimeta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			if tt.wantReason == "" {
+				if condition != nil {
+					t.Errorf("expected no condition for an unclassified error, got %+v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != tt.wantReason {
+				t.Fatalf("Ready condition = %+v, want False with reason %s", condition, tt.wantReason)
+			}
+			if condition.ObservedGeneration != 3 || condition.Message != tt.err.Error() {
+				t.Errorf("Ready condition = %+v, want generation 3 and message %q", condition, tt.err.Error())
+			}
+			if updated.Status.Ready != "False" || updated.Status.ObservedGeneration != 3 {
+				t.Errorf("status = %+v, want ready False at generation 3", updated.Status)
+			}
+			if tt.wantCondition != "" {
+				want := metav1.ConditionTrue
+				if tt.wantCondition == utils.ConditionDependenciesResolved {
+					want = metav1.ConditionFalse
+				}
+				extra := apimeta.FindStatusCondition(updated.Status.Conditions, tt.wantCondition)
+				if extra == nil || extra.Status != want || extra.Reason != tt.wantReason {
+					t.Errorf("%s condition = %+v, want %s with reason %s", tt.wantCondition, extra, want, tt.wantReason)
+				}
+			}
+			select {
+			case event := <-recorder.Events:
+				if want := "Warning " + tt.wantReason + " " + tt.err.Error(); event != want {
+					t.Errorf("event = %q, want %q", event, want)
+				}
+			default:
+				t.Error("expected an event")
+			}
+		})
+	}
+}
+
+func TestReconcilerKeepsSpecificReasons(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openslov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "test-slo", Namespace: "default", Generation: 2}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(slo).WithStatusSubresource(slo).Build()
+
+	r := &Reconciler{
+		Name:     "slo",
+		Client:   c,
+		Recorder: record.NewFakeRecorder(10),
+		Object:   &openslov1.SLO{},
+		Reconciler: reconcilerFunc(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			current := &openslov1.SLO{}
+			if err := c.Get(ctx, req.NamespacedName, current); err != nil {
+				return reconcile.Result{}, err
+			}
+			conditions := []metav1.Condition{
+				utils.NewCondition(utils.ConditionDependenciesResolved, metav1.ConditionFalse, utils.ReasonSLINotFound, "SLI Object not found"),
+				utils.NewCondition(utils.ConditionReady, metav1.ConditionFalse, utils.ReasonSLINotFound, "SLI Object not found"),
+			}
+			if err := utils.UpdateConditions(ctx, current, c, conditions...); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, errors.DependencyNotReady(fmt.Errorf("sli not found"))
+		}),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-slo", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &openslov1.SLO{}
+	if err := c.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatal(err)
+	}
+	for _, conditionType := range []string{utils.ConditionReady, utils.ConditionDependenciesResolved} {
+		condition := apimeta.FindStatusCondition(updated.Status.Conditions, conditionType)
+		if condition == nil || condition.Reason != utils.ReasonSLINotFound {
+			t.Errorf("%s condition = %+v, want the reason set by the controller", conditionType, condition)
+		}
+	}
+}
+
+func TestReconcilerClearsTransientErrorOnSuccess(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openslov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "test-slo", Namespace: "default", Generation: 1}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(slo).WithStatusSubresource(slo).Build()
+
+	fail := true
+	r := &Reconciler{
+		Name:     "slo",
+		Client:   c,
+		Recorder: record.NewFakeRecorder(10),
+		Object:   &openslov1.SLO{},
+		Reconciler: reconcilerFunc(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			if fail {
+				return reconcile.Result{}, errors.Transient(fmt.Errorf("connection refused"), time.Second)
+			}
+			return reconcile.Result{}, nil
+		}),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-slo", Namespace: "default"}}
+	degraded := func() *metav1.Condition {
+		updated := &openslov1.SLO{}
+		if err := c.Get(context.Background(), req.NamespacedName, updated); err != nil {
+			t.Fatal(err)
+		}
+		return apimeta.FindStatusCondition(updated.Status.Conditions, utils.ConditionDegraded)
+	}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if condition := degraded(); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("Degraded condition = %+v, want True after a transient error", condition)
+	}
+
+	fail = false
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if condition := degraded(); condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != utils.ReasonAsExpected {
+		t.Errorf("Degraded condition = %+v, want False once the reconcile succeeds", condition)
+	}
+}
+
+func TestReconcilerPassesSuccessThrough(t *testing.T) {
+	r := &Reconciler{
+		Name: "slo",
+		Reconciler: reconcilerFunc(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}),
+	}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{})
+	if err != nil || result.RequeueAfter != time.Minute {
+		t.Errorf("Reconcile() = %+v, %v, want the wrapped result", result, err)
+	}
+}
+
+func TestEnqueueAll(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openslov1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "login", Namespace: "auth"}},
+	).Build()
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	EnqueueAll(c, &openslov1.SLOList{}).Generic(context.Background(), event.GenericEvent{Object: &openslov1.SLO{}}, queue)
+
+	if queue.Len() != 2 {
+		t.Fatalf("enqueued %d requests, want 2", queue.Len())
+	}
+	got := map[types.NamespacedName]bool{}
+	for queue.Len() > 0 {
+		item, _ := queue.Get()
+		got[item.(reconcile.Request).NamespacedName] = true
+		queue.Done(item)
+	}
+	for _, want := range []types.NamespacedName{{Name: "checkout", Namespace: "shop"}, {Name: "login", Namespace: "auth"}} {
+		if !got[want] {
+			t.Errorf("%s was not enqueued", want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package ruler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	queueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "osko_ruler_queue_depth",
+			Help: "Number of rule groups waiting to be written to the ruler API, per datasource",
+		},
+		[]string{"datasource"},
+	)
+
+	syncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "osko_ruler_sync_duration_seconds",
+			Help:    "Latency of rule group writes to the ruler API, per datasource and tenant",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"datasource", "tenant"},
+	)
+
+	syncFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "osko_ruler_sync_failures_total",
+			Help: "Number of failed rule group writes to the ruler API, including retried ones, per datasource and tenant",
+		},
+		[]string{"datasource", "tenant"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(queueDepth, syncDuration, syncFailures)
+}
+
+// Options configures the queues created by a Dispatcher
+type Options struct {
+	WriteQPS       float64
+	WriteBurst     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	ResyncPeriod   time.Duration
+}
+
+// OptionsFromConfig builds queue options from the operator configuration
+func OptionsFromConfig(cfg config.RulerConfig) Options {
+	return Options{
+		WriteQPS:       cfg.WriteQPS,
+		WriteBurst:     cfg.WriteBurst,
+		RetryBaseDelay: cfg.RetryBaseDelay,
+		RetryMaxDelay:  cfg.RetryMaxDelay,
+		ResyncPeriod:   cfg.ResyncPeriod,
+	}
+}
+
+// ClientFactory builds a ruler client for the given connection details
+type ClientFactory func(connectionDetails *oskov1alpha1.ConnectionDetails) (RuleGroupClient, error)
+
+// Dispatcher owns one Queue per datasource and runs their workers for the lifetime of the manager
+type Dispatcher struct {
+	opts      Options
+	newClient ClientFactory
+	events    chan event.GenericEvent
+
+	mu     sync.Mutex
+	ctx    context.Context
+	queues map[types.NamespacedName]*Queue
+}
+
+// NewDispatcher creates a Dispatcher talking to the Mimir ruler API
+func NewDispatcher(opts Options) *Dispatcher {
+	return NewDispatcherWithClientFactory(opts, func(cd *oskov1alpha1.ConnectionDetails) (RuleGroupClient, error) {
+		mClientConfig := helpers.MimirClientConfig{
+			Address:  cd.Address,
+			TenantId: cd.TargetTenant,
+		}
+		return mClientConfig.NewMimirClient()
+	})
+}
+
+// NewDispatcherWithClientFactory creates a Dispatcher using a custom ruler client factory
+func NewDispatcherWithClientFactory(opts Options, newClient ClientFactory) *Dispatcher {
+	return &Dispatcher{
+		opts:      opts,
+		newClient: newClient,
+		events:    make(chan event.GenericEvent, 64),
+		queues:    map[types.NamespacedName]*Queue{},
+	}
+}
+
+// Queue returns the queue of a datasource, creating it on first use. The ruler client
+// is rebuilt whenever the address or tenant in the connection details change.
+func (d *Dispatcher) Queue(datasource types.NamespacedName, connectionDetails *oskov1alpha1.ConnectionDetails) (*Queue, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	q, ok := d.queues[datasource]
+	if !ok {
+		q = newQueue(datasource, d.opts, d.notify)
+		d.queues[datasource] = q
+		if d.ctx != nil {
+			go q.run(d.ctx)
+		}
+	}
+
+	connection := connectionDetails.Address + "|" + connectionDetails.TargetTenant
+	if !q.connectedTo(connection) {
+		client, err := d.newClient(connectionDetails)
+		if err != nil {
+			return nil, err
+		}
+		q.setClient(connection, connectionDetails.TargetTenant, client)
+	}
+	return q, nil
+}
+
+// Lookup returns the queue of a datasource if one has been created
+func (d *Dispatcher) Lookup(datasource types.NamespacedName) (*Queue, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	q, ok := d.queues[datasource]
+	return q, ok
+}
+
+// Events is a stream of Datasources whose queue became busy, drained or started failing
+func (d *Dispatcher) Events() <-chan event.GenericEvent {
+	return d.events
+}
+
+func (d *Dispatcher) notify(datasource types.NamespacedName) {
+	ds := &openslov1.Datasource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      datasource.Name,
+			Namespace: datasource.Namespace,
+		},
+	}
+	select {
+	case d.events <- event.GenericEvent{Object: ds}:
+	default:
+	}
+}
+
+// Start runs the queue workers until the context is cancelled. It implements manager.Runnable.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	d.ctx = ctx
+	for _, q := range d.queues {
+		go q.run(ctx)
+	}
+	d.mu.Unlock()
+
+	<-ctx.Done()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, q := range d.queues {
+		q.shutdown()
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package ruler
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mimirclient "github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var httpStatusPattern = regexp.MustCompile(`server returned HTTP status: (\d{3})`)
+
+// RuleGroupClient is the subset of the Mimir client used to write rule groups
+type RuleGroupClient interface {
+	CreateRuleGroup(ctx context.Context, namespace string, rg rwrulefmt.RuleGroup) error
+	DeleteRuleGroup(ctx context.Context, namespace, groupName string) error
+}
+
+// Operation is a single rule group write waiting to be sent to the ruler
+type Operation struct {
+	Namespace string
+	Group     rwrulefmt.RuleGroup
+	Delete    bool
+}
+
+func (o Operation) key() string {
+	return o.Namespace + "/" + o.Group.Name
+}
+
+type result struct {
+	op       Operation
+	err      error
+	syncedAt time.Time
+}
+
+// Stats is a point in time summary of a Queue
+type Stats struct {
+	// Depth is the number of rule groups with a write waiting to be sent
+	Depth int
+	// Failing is the number of rule groups whose last write failed permanently
+	Failing int
+	// LastSyncTime is the time of the last successful write
+	LastSyncTime time.Time
+	// LastError is the error of the most recent failed write
+	LastError string
+}
+
+// Queue serializes, rate limits and coalesces rule group writes for a single datasource.
+// Only the latest pending operation for a rule group is kept, so repeated reconciles
+// of the same rule between two writes result in a single ruler API call.
+type Queue struct {
+	datasource types.NamespacedName
+	limiter    *rate.Limiter
+	queue      workqueue.RateLimitingInterface
+	resync     time.Duration
+	onChange   func(types.NamespacedName)
+
+	mu          sync.Mutex
+	client      RuleGroupClient
+	connection  string
+	tenant      string
+	pending     map[string]Operation
+	results     map[string]result
+	lastSync    time.Time
+	lastError   string
+	lastFailing bool
+}
+
+func newQueue(datasource types.NamespacedName, opts Options, onChange func(types.NamespacedName)) *Queue {
+	return &Queue{
+		datasource: datasource,
+		limiter:    rate.NewLimiter(rate.Limit(opts.WriteQPS), opts.WriteBurst),
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.NewItemExponentialFailureRateLimiter(opts.RetryBaseDelay, opts.RetryMaxDelay),
+			workqueue.RateLimitingQueueConfig{Name: "ruler_" + datasource.Namespace + "_" + datasource.Name},
+		),
+		resync:   opts.ResyncPeriod,
+		onChange: onChange,
+		pending:  map[string]Operation{},
+		results:  map[string]result{},
+	}
+}
+
+// setClient replaces the ruler client when the connection target changes. Operations that were submitted
+// before there was a client are dropped from the work queue by processNextItem, they are queued again here.
+func (q *Queue) setClient(connection, tenant string, client RuleGroupClient) {
+	q.mu.Lock()
+	q.connection = connection
+	q.tenant = tenant
+	q.client = client
+	keys := make([]string, 0, len(q.pending))
+	for key := range q.pending {
+		keys = append(keys, key)
+	}
+	q.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+	for _, key := range keys {
+		q.queue.Add(key)
+	}
+}
+
+func (q *Queue) connectedTo(connection string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.client != nil && q.connection == connection
+}
+
+// Submit schedules an operation, replacing any pending operation for the same rule group.
+// Operations identical to the last successful write are skipped until the resync period expires.
+func (q *Queue) Submit(op Operation) {
+	key := op.key()
+
+	q.mu.Lock()
+	if last, ok := q.results[key]; ok && last.err == nil && time.Since(last.syncedAt) < q.resync &&
+		reflect.DeepEqual(last.op, op) {
+		if _, pending := q.pending[key]; !pending {
+			q.mu.Unlock()
+			return
+		}
+	}
+	wasIdle := len(q.pending) == 0
+	_, queued := q.pending[key]
+	q.pending[key] = op
+	depth := len(q.pending)
+	q.mu.Unlock()
+
+	queueDepth.WithLabelValues(q.datasource.String()).Set(float64(depth))
+	// A pending rule group is already in the work queue, possibly waiting out a retry backoff that adding it
+	// again would skip. The worker picks up the replaced operation when it gets to the key.
+	if !queued {
+		q.queue.Add(key)
+	}
+	if wasIdle {
+		q.notify()
+	}
+}
+
+// Deleted reports whether the given rule groups have been removed from the ruler and nothing is pending for them
+func (q *Queue) Deleted(namespace string, names ...string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range names {
+		key := namespace + "/" + name
+		if _, pending := q.pending[key]; pending {
+			return false
+		}
+		last, ok := q.results[key]
+		if !ok || !last.op.Delete || last.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SyncState reports whether writes for the given rule groups are still pending and the error of the
+// last failed write, if any. Rule groups never submitted count as pending.
+func (q *Queue) SyncState(namespace string, names ...string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := false
+	var errs []error
+	for _, name := range names {
+		key := namespace + "/" + name
+		if _, ok := q.pending[key]; ok {
+			pending = true
+			continue
+		}
+		last, ok := q.results[key]
+		if !ok {
+			pending = true
+			continue
+		}
+		if last.err != nil {
+			errs = append(errs, fmt.Errorf("rule group %s: %w", name, last.err))
+		}
+	}
+	return pending, stderrors.Join(errs...)
+}
+
+// Forget drops the recorded results for the given rule groups
+func (q *Queue) Forget(namespace string, names ...string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range names {
+		delete(q.results, namespace+"/"+name)
+	}
+}
+
+// Depth returns the number of rule groups with a pending write
+func (q *Queue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Stats returns a summary of the queue state
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := Stats{
+		Depth:        len(q.pending),
+		LastSyncTime: q.lastSync,
+		LastError:    q.lastError,
+	}
+	for _, r := range q.results {
+		if r.err != nil {
+			stats.Failing++
+		}
+	}
+	return stats
+}
+
+func (q *Queue) run(ctx context.Context) {
+	for q.processNextItem(ctx) {
+	}
+}
+
+func (q *Queue) shutdown() {
+	q.queue.ShutDown()
+}
+
+func (q *Queue) processNextItem(ctx context.Context) bool {
+	item, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(item)
+
+	key := item.(string)
+	op, client, tenant, ok := q.take(key)
+	if !ok {
+		q.queue.Forget(key)
+		return true
+	}
+
+	if err := q.limiter.Wait(ctx); err != nil {
+		q.restore(op)
+		return true
+	}
+
+	start := time.Now()
+	err := apply(ctx, client, op)
+	syncDuration.WithLabelValues(q.datasource.String(), tenant).Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncFailures.WithLabelValues(q.datasource.String(), tenant).Inc()
+	}
+	switch {
+	case err == nil:
+		q.queue.Forget(key)
+		q.record(op, nil)
+	case IsRetryable(err):
+		ctrllog.FromContext(ctx).V(1).Info("Retrying ruler write", "datasource", q.datasource.String(), "group", key, "error", err.Error())
+		q.restore(op)
+		q.queue.AddRateLimited(key)
+	default:
+		ctrllog.FromContext(ctx).Error(err, "Failed to write rule group", "datasource", q.datasource.String(), "group", key)
+		q.queue.Forget(key)
+		q.record(op, err)
+	}
+
+	queueDepth.WithLabelValues(q.datasource.String()).Set(float64(q.Depth()))
+	return true
+}
+
+func (q *Queue) take(key string) (Operation, RuleGroupClient, string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	op, ok := q.pending[key]
+	if !ok || q.client == nil {
+		return Operation{}, nil, "", false
+	}
+	delete(q.pending, key)
+	return op, q.client, q.tenant, true
+}
+
+// restore puts a failed operation back unless a newer one was submitted in the meantime
+func (q *Queue) restore(op Operation) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, newer := q.pending[op.key()]; !newer {
+		q.pending[op.key()] = op
+	}
+}
+
+func (q *Queue) record(op Operation, err error) {
+	q.mu.Lock()
+	now := time.Now()
+	q.results[op.key()] = result{op: op, err: err, syncedAt: now}
+	failing := false
+	for _, r := range q.results {
+		if r.err != nil {
+			failing = true
+			break
+		}
+	}
+	if err != nil {
+		q.lastError = err.Error()
+	} else {
+		q.lastSync = now
+	}
+	changed := failing != q.lastFailing || len(q.pending) == 0
+	q.lastFailing = failing
+	q.mu.Unlock()
+
+	if changed {
+		q.notify()
+	}
+}
+
+func (q *Queue) notify() {
+	if q.onChange != nil {
+		q.onChange(q.datasource)
+	}
+}
+
+func apply(ctx context.Context, client RuleGroupClient, op Operation) error {
+	if op.Delete {
+		err := client.DeleteRuleGroup(ctx, op.Namespace, op.Group.Name)
+		if stderrors.Is(err, mimirclient.ErrResourceNotFound) {
+			return nil
+		}
+		return err
+	}
+	return client.CreateRuleGroup(ctx, op.Namespace, op.Group)
+}
+
+// IsRetryable reports whether a ruler API error is worth retrying: rate limiting,
+// server side errors and failures to reach the ruler at all
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var urlErr *url.Error
+	if stderrors.As(err, &urlErr) {
+		return true
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "too many requests") {
+		return true
+	}
+	if m := httpStatusPattern.FindStringSubmatch(msg); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code >= 500
+	}
+	return false
+}
@@ -0,0 +1,283 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	mimirclient "github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeRulerClient struct {
+	mu      sync.Mutex
+	errs    []error
+	creates []rwrulefmt.RuleGroup
+	deletes []string
+}
+
+func (f *fakeRulerClient) nextErr() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeRulerClient) CreateRuleGroup(_ context.Context, _ string, rg rwrulefmt.RuleGroup) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creates = append(f.creates, rg)
+	return f.nextErr()
+}
+
+func (f *fakeRulerClient) DeleteRuleGroup(_ context.Context, _, groupName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deletes = append(f.deletes, groupName)
+	return f.nextErr()
+}
+
+func (f *fakeRulerClient) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.creates) + len(f.deletes)
+}
+
+var testDatasource = types.NamespacedName{Name: "mimir", Namespace: "default"}
+
+func testOptions() Options {
+	return Options{
+		WriteQPS:       100,
+		WriteBurst:     10,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  10 * time.Millisecond,
+		ResyncPeriod:   time.Hour,
+	}
+}
+
+func newTestQueue(t *testing.T, fake *fakeRulerClient) (*Dispatcher, *Queue) {
+	d := NewDispatcherWithClientFactory(testOptions(), func(*oskov1alpha1.ConnectionDetails) (RuleGroupClient, error) {
+		return fake, nil
+	})
+	q, err := d.Queue(testDatasource, &oskov1alpha1.ConnectionDetails{Address: "http://mimir", TargetTenant: "tenant"})
+	require.NoError(t, err)
+	return d, q
+}
+
+func start(t *testing.T, d *Dispatcher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = d.Start(ctx) }()
+}
+
+func yamlNode(value string) yaml.Node {
+	return yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+func writeOp(group, expr string) Operation {
+	return Operation{
+		Namespace: "osko",
+		Group: rwrulefmt.RuleGroup{RuleGroup: rulefmt.RuleGroup{
+			Name:  group,
+			Rules: []rulefmt.RuleNode{{Expr: yamlNode(expr)}},
+		}},
+	}
+}
+
+func TestQueue_CoalescesPendingWrites(t *testing.T) {
+	fake := &fakeRulerClient{}
+	d, q := newTestQueue(t, fake)
+
+	q.Submit(writeOp("slo_burn_rate", "first"))
+	q.Submit(writeOp("slo_burn_rate", "second"))
+	q.Submit(writeOp("slo_sli_total", "total"))
+	assert.Equal(t, 2, q.Depth())
+
+	start(t, d)
+	assert.Eventually(t, func() bool { return q.Depth() == 0 && fake.calls() == 2 }, time.Second, time.Millisecond)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, rg := range fake.creates {
+		if rg.Name == "slo_burn_rate" {
+			assert.Equal(t, "second", rg.Rules[0].Expr.Value)
+		}
+	}
+}
+
+func TestQueue_RetriesRetryableErrors(t *testing.T) {
+	fake := &fakeRulerClient{errs: []error{
+		errors.New("too many requests"),
+		errors.New("server returned HTTP status: 503 Service Unavailable"),
+	}}
+	d, q := newTestQueue(t, fake)
+	start(t, d)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	assert.Eventually(t, func() bool { return fake.calls() == 3 && q.Depth() == 0 }, time.Second, time.Millisecond)
+
+	stats := q.Stats()
+	assert.Zero(t, stats.Failing)
+	assert.False(t, stats.LastSyncTime.IsZero())
+}
+
+func TestQueue_KeepsBackoffOfResubmittedGroups(t *testing.T) {
+	fake := &fakeRulerClient{errs: []error{errors.New("server returned HTTP status: 503 Service Unavailable")}}
+	opts := testOptions()
+	opts.RetryBaseDelay = 200 * time.Millisecond
+	opts.RetryMaxDelay = time.Second
+	d := NewDispatcherWithClientFactory(opts, func(*oskov1alpha1.ConnectionDetails) (RuleGroupClient, error) {
+		return fake, nil
+	})
+	q, err := d.Queue(testDatasource, &oskov1alpha1.ConnectionDetails{Address: "http://mimir", TargetTenant: "tenant"})
+	require.NoError(t, err)
+	start(t, d)
+
+	q.Submit(writeOp("slo_burn_rate", "first"))
+	assert.Eventually(t, func() bool { return fake.calls() == 1 }, time.Second, time.Millisecond)
+
+	q.Submit(writeOp("slo_burn_rate", "second"))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, fake.calls(), "a resubmitted group waits out its backoff")
+
+	assert.Eventually(t, func() bool { return fake.calls() == 2 && q.Depth() == 0 }, time.Second, time.Millisecond)
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, "second", fake.creates[1].Rules[0].Expr.Value)
+}
+
+func TestQueue_WritesPendingGroupsOnceConnected(t *testing.T) {
+	q := newQueue(testDatasource, testOptions(), nil)
+	t.Cleanup(q.shutdown)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	require.True(t, q.processNextItem(context.Background()))
+	assert.Equal(t, 1, q.Depth(), "the write waits for a client")
+
+	fake := &fakeRulerClient{}
+	q.setClient("http://mimir", "tenant", fake)
+	go q.run(context.Background())
+	assert.Eventually(t, func() bool { return fake.calls() == 1 && q.Depth() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestQueue_DoesNotRetryRejectedWrites(t *testing.T) {
+	fake := &fakeRulerClient{errs: []error{
+		errors.New(`server returned HTTP status: 400 Bad Request, body: "invalid rule"`),
+	}}
+	d, q := newTestQueue(t, fake)
+	start(t, d)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	assert.Eventually(t, func() bool { return q.Stats().Failing == 1 }, time.Second, time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, fake.calls())
+	assert.Contains(t, q.Stats().LastError, "400 Bad Request")
+}
+
+func TestQueue_SkipsUnchangedGroups(t *testing.T) {
+	fake := &fakeRulerClient{}
+	d, q := newTestQueue(t, fake)
+	start(t, d)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	assert.Eventually(t, func() bool { return fake.calls() == 1 && q.Depth() == 0 }, time.Second, time.Millisecond)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	assert.Zero(t, q.Depth())
+
+	q.Submit(writeOp("slo_burn_rate", "changed"))
+	assert.Eventually(t, func() bool { return fake.calls() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestQueue_Deleted(t *testing.T) {
+	fake := &fakeRulerClient{errs: []error{nil, mimirclient.ErrResourceNotFound}}
+	d, q := newTestQueue(t, fake)
+
+	assert.False(t, q.Deleted("osko", "slo_burn_rate", "slo_sli_total"))
+
+	for _, name := range []string{"slo_burn_rate", "slo_sli_total"} {
+		op := writeOp(name, "")
+		op.Delete = true
+		q.Submit(op)
+	}
+	assert.False(t, q.Deleted("osko", "slo_burn_rate", "slo_sli_total"))
+
+	start(t, d)
+	assert.Eventually(t, func() bool { return q.Deleted("osko", "slo_burn_rate", "slo_sli_total") }, time.Second, time.Millisecond)
+
+	q.Forget("osko", "slo_burn_rate", "slo_sli_total")
+	assert.False(t, q.Deleted("osko", "slo_burn_rate"))
+}
+
+func TestQueue_SyncState(t *testing.T) {
+	fake := &fakeRulerClient{errs: []error{nil, errors.New(`server returned HTTP status: 400 Bad Request, body: "invalid rule"`)}}
+	d, q := newTestQueue(t, fake)
+
+	pending, err := q.SyncState("osko", "slo_sli_total")
+	assert.True(t, pending)
+	assert.NoError(t, err)
+
+	q.Submit(writeOp("slo_sli_total", "total"))
+	start(t, d)
+	assert.Eventually(t, func() bool {
+		pending, err := q.SyncState("osko", "slo_sli_total")
+		return !pending && err == nil
+	}, time.Second, time.Millisecond)
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+	assert.Eventually(t, func() bool {
+		pending, err := q.SyncState("osko", "slo_sli_total", "slo_burn_rate")
+		return !pending && err != nil
+	}, time.Second, time.Millisecond)
+	_, err = q.SyncState("osko", "slo_sli_total", "slo_burn_rate")
+	assert.ErrorContains(t, err, "slo_burn_rate")
+}
+
+func TestDispatcher_NotifiesDatasource(t *testing.T) {
+	d, q := newTestQueue(t, &fakeRulerClient{})
+
+	q.Submit(writeOp("slo_burn_rate", "expr"))
+
+	select {
+	case e := <-d.Events():
+		assert.Equal(t, testDatasource.Name, e.Object.GetName())
+		assert.Equal(t, testDatasource.Namespace, e.Object.GetNamespace())
+	case <-time.After(time.Second):
+		t.Fatal("expected an event for the datasource")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", errors.New("too many requests"), true},
+		{"server error", errors.New("server returned HTTP status: 500 Internal Server Error"), true},
+		{"bad gateway", fmt.Errorf("wrapped: %w", errors.New("server returned HTTP status: 502 Bad Gateway")), true},
+		{"bad request", errors.New("server returned HTTP status: 400 Bad Request"), false},
+		{"unreachable", &url.Error{Op: "Post", URL: "http://mimir", Err: errors.New("connection refused")}, true},
+		{"other", errors.New("invalid rule group"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
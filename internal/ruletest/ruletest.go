@@ -0,0 +1,378 @@
+// Package ruletest evaluates generated rule groups against synthetic series with the Prometheus rules engine,
+// in the spirit of promtool test rules, and checks the alerts that fire and the values the rules record.
+package ruletest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// DefaultEvaluationInterval is the interval rules are evaluated and input series are sampled at when tests set none
+const DefaultEvaluationInterval = model.Duration(time.Minute)
+
+// valueTolerance is the relative difference up to which recorded values match the expected ones
+const valueTolerance = 1e-6
+
+// TestFile is a file of rule tests, the rules under test are generated from the SLOs of its manifests
+type TestFile struct {
+	// Manifests are the OpenSLO manifests to generate the rules from, relative to the test file
+	Manifests          []string       `yaml:"manifests"`
+	EvaluationInterval model.Duration `yaml:"evaluation_interval"`
+	Tests              []TestGroup    `yaml:"tests"`
+}
+
+// TestGroup is a set of input series and the alerts and values expected from the rules evaluated on them
+type TestGroup struct {
+	Name string `yaml:"name"`
+	// Interval is the time between two values of the input series
+	Interval        model.Duration  `yaml:"interval"`
+	InputSeries     []Series        `yaml:"input_series"`
+	AlertRuleTests  []AlertTestCase `yaml:"alert_rule_test"`
+	PromqlExprTests []ExprTestCase  `yaml:"promql_expr_test"`
+}
+
+// Series is an input series in the expanding notation of promtool, such as 0+10x100
+type Series struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+// AlertTestCase lists the alerts of a name firing at a time, no alerts means the alert must not fire
+type AlertTestCase struct {
+	EvalTime  model.Duration `yaml:"eval_time"`
+	Alertname string         `yaml:"alertname"`
+	ExpAlerts []Alert        `yaml:"exp_alerts"`
+}
+
+// Alert is a firing alert, its alertname label is implied
+type Alert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+// ExprTestCase is a PromQL expression and the samples it returns at a time
+type ExprTestCase struct {
+	Expr       string         `yaml:"expr"`
+	EvalTime   model.Duration `yaml:"eval_time"`
+	ExpSamples []Sample       `yaml:"exp_samples"`
+}
+
+// Sample is an expected sample, labels in the series notation such as metric{label="value"}
+type Sample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+// Result is the outcome of a test group, a test group without errors passed
+type Result struct {
+	Name   string
+	Errors []error
+}
+
+// Run evaluates the rule groups on the input series of every test group from time zero on, at the given interval
+func Run(ctx context.Context, groups []rulefmt.RuleGroup, tests []TestGroup, evalInterval model.Duration) []Result {
+	if evalInterval == 0 {
+		evalInterval = DefaultEvaluationInterval
+	}
+	results := make([]Result, 0, len(tests))
+	for i, tg := range tests {
+		name := tg.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", i+1)
+		}
+		results = append(results, Result{Name: name, Errors: runGroup(ctx, groups, tg, time.Duration(evalInterval))})
+	}
+	return results
+}
+
+type inputSample struct {
+	labels labels.Labels
+	t      int64
+	v      float64
+}
+
+func runGroup(ctx context.Context, ruleGroups []rulefmt.RuleGroup, tg TestGroup, evalInterval time.Duration) []error {
+	interval := time.Duration(tg.Interval)
+	if interval == 0 {
+		interval = evalInterval
+	}
+	samples, err := expandSeries(tg.InputSeries, interval)
+	if err != nil {
+		return []error{err}
+	}
+
+	dir, err := os.MkdirTemp("", "osko-ruletest")
+	if err != nil {
+		return []error{err}
+	}
+	defer os.RemoveAll(dir)
+	opts := tsdb.DefaultOptions()
+	opts.RetentionDuration = 0
+	db, err := tsdb.Open(dir, nil, nil, opts, nil)
+	if err != nil {
+		return []error{err}
+	}
+	defer db.Close()
+
+	engine := promql.NewEngine(promql.EngineOpts{
+		MaxSamples:           50000000,
+		Timeout:              time.Minute,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+	managerOpts := &rules.ManagerOptions{
+		QueryFunc:  rules.EngineQueryFunc(engine, db),
+		NotifyFunc: func(context.Context, string, ...*rules.Alert) {},
+		Context:    ctx,
+		Appendable: db,
+		Queryable:  db,
+		Logger:     kitlog.NewNopLogger(),
+	}
+	groups, err := newGroups(ruleGroups, evalInterval, managerOpts)
+	if err != nil {
+		return []error{err}
+	}
+
+	alertTests := map[model.Duration][]AlertTestCase{}
+	var alertTimes []model.Duration
+	maxEvalTime := model.Duration(0)
+	for _, at := range tg.AlertRuleTests {
+		if at.Alertname == "" {
+			return []error{fmt.Errorf("an alert_rule_test at eval_time %s has no alertname", at.EvalTime)}
+		}
+		if _, ok := alertTests[at.EvalTime]; !ok {
+			alertTimes = append(alertTimes, at.EvalTime)
+		}
+		alertTests[at.EvalTime] = append(alertTests[at.EvalTime], at)
+		maxEvalTime = max(maxEvalTime, at.EvalTime)
+	}
+	for _, et := range tg.PromqlExprTests {
+		maxEvalTime = max(maxEvalTime, et.EvalTime)
+	}
+	sort.Slice(alertTimes, func(i, j int) bool { return alertTimes[i] < alertTimes[j] })
+
+	var errs []error
+	next, loaded := 0, 0
+	for ts := time.Duration(0); ts <= time.Duration(maxEvalTime); ts += evalInterval {
+		// Input series are appended up to the evaluation time only, the head block rejects samples far older than its newest one
+		if loaded, err = appendSamples(db, samples, loaded, ts.Milliseconds()); err != nil {
+			return append(errs, err)
+		}
+		for _, g := range groups {
+			g.Eval(ctx, time.UnixMilli(ts.Milliseconds()).UTC())
+			for _, r := range g.Rules() {
+				if r.LastError() != nil {
+					return append(errs, fmt.Errorf("rule %s failed at %s: %w", r.Name(), model.Duration(ts), r.LastError()))
+				}
+			}
+		}
+
+		// Alerts expected at a time between two evaluations are compared with the alerts of the earlier one
+		for ; next < len(alertTimes) && time.Duration(alertTimes[next]) < ts+evalInterval; next++ {
+			for _, at := range alertTests[alertTimes[next]] {
+				if err := checkAlerts(groups, at); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	for _, et := range tg.PromqlExprTests {
+		if err := checkExpr(ctx, engine, db, et); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// expandSeries expands the input series into their samples, sorted by time
+func expandSeries(series []Series, interval time.Duration) ([]inputSample, error) {
+	var samples []inputSample
+	for _, s := range series {
+		metric, values, err := parser.ParseSeriesDesc(s.Series + " " + s.Values)
+		if err != nil {
+			return nil, fmt.Errorf("input series %s: %w", s.Series, err)
+		}
+		for i, v := range values {
+			if v.Omitted {
+				continue
+			}
+			samples = append(samples, inputSample{labels: metric, t: (time.Duration(i) * interval).Milliseconds(), v: v.Value})
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].t < samples[j].t })
+	return samples, nil
+}
+
+// appendSamples appends the samples from the given offset on up to the timestamp and returns the next offset
+func appendSamples(db *tsdb.DB, samples []inputSample, from int, until int64) (int, error) {
+	app := db.Appender(context.Background())
+	i := from
+	for ; i < len(samples) && samples[i].t <= until; i++ {
+		if _, err := app.Append(0, samples[i].labels, samples[i].t, samples[i].v); err != nil {
+			_ = app.Rollback()
+			return i, fmt.Errorf("appending input series %s: %w", samples[i].labels, err)
+		}
+	}
+	return i, app.Commit()
+}
+
+// newGroups builds the rules engine groups of rule file groups, every group is evaluated at the test interval
+func newGroups(ruleGroups []rulefmt.RuleGroup, interval time.Duration, opts *rules.ManagerOptions) ([]*rules.Group, error) {
+	var groups []*rules.Group
+	for _, rg := range ruleGroups {
+		var groupRules []rules.Rule
+		for _, r := range rg.Rules {
+			expr, err := parser.ParseExpr(r.Expr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("rule group %s: %w", rg.Name, err)
+			}
+			if r.Record.Value != "" {
+				groupRules = append(groupRules, rules.NewRecordingRule(r.Record.Value, expr, labels.FromMap(r.Labels)))
+				continue
+			}
+			alert := rules.NewAlertingRule(r.Alert.Value, expr, time.Duration(r.For), time.Duration(r.KeepFiringFor),
+				labels.FromMap(r.Labels), labels.FromMap(r.Annotations), labels.EmptyLabels(), "", true, opts.Logger)
+			groupRules = append(groupRules, alert)
+		}
+
+		var delay *time.Duration
+		if rg.EvaluationDelay != nil {
+			d := time.Duration(*rg.EvaluationDelay)
+			delay = &d
+		}
+		groups = append(groups, rules.NewGroup(rules.GroupOptions{
+			Name:            rg.Name,
+			Interval:        interval,
+			Rules:           groupRules,
+			Opts:            opts,
+			EvaluationDelay: delay,
+		}))
+	}
+	return groups, nil
+}
+
+// checkAlerts compares the firing alerts of a name with the expected ones
+func checkAlerts(groups []*rules.Group, at AlertTestCase) error {
+	var got []string
+	for _, g := range groups {
+		for _, r := range g.AlertingRules() {
+			if r.Name() != at.Alertname {
+				continue
+			}
+			for _, a := range r.ActiveAlerts() {
+				if a.State == rules.StateFiring {
+					got = append(got, alertString(a.Labels, a.Annotations))
+				}
+			}
+		}
+	}
+
+	var want []string
+	for _, a := range at.ExpAlerts {
+		lbls := labels.NewBuilder(labels.FromMap(a.ExpLabels)).Set(labels.AlertName, at.Alertname).Labels()
+		want = append(want, alertString(lbls, labels.FromMap(a.ExpAnnotations)))
+	}
+
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		return fmt.Errorf("alertname: %s, time: %s\n  exp: %s\n  got: %s", at.Alertname, at.EvalTime, listString(want), listString(got))
+	}
+	return nil
+}
+
+// checkExpr compares the samples an expression returns with the expected ones
+func checkExpr(ctx context.Context, engine *promql.Engine, db *tsdb.DB, et ExprTestCase) error {
+	ts := time.UnixMilli(time.Duration(et.EvalTime).Milliseconds()).UTC()
+	q, err := engine.NewInstantQuery(ctx, db, nil, et.Expr, ts)
+	if err != nil {
+		return fmt.Errorf("expr: %q, time: %s: %w", et.Expr, et.EvalTime, err)
+	}
+	defer q.Close()
+	res := q.Exec(ctx)
+	if res.Err != nil {
+		return fmt.Errorf("expr: %q, time: %s: %w", et.Expr, et.EvalTime, res.Err)
+	}
+
+	var got promql.Vector
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		got = v
+	case promql.Scalar:
+		got = promql.Vector{{T: v.T, F: v.V, Metric: labels.EmptyLabels()}}
+	default:
+		return fmt.Errorf("expr: %q, time: %s: unexpected result type %s", et.Expr, et.EvalTime, res.Value.Type())
+	}
+
+	want := make([]promql.Sample, 0, len(et.ExpSamples))
+	for _, s := range et.ExpSamples {
+		lbls, err := parser.ParseMetric(s.Labels)
+		if err != nil {
+			return fmt.Errorf("expr: %q, time: %s: labels %q: %w", et.Expr, et.EvalTime, s.Labels, err)
+		}
+		want = append(want, promql.Sample{Metric: lbls, F: s.Value})
+	}
+
+	sort.Slice(got, func(i, j int) bool { return labels.Compare(got[i].Metric, got[j].Metric) < 0 })
+	sort.Slice(want, func(i, j int) bool { return labels.Compare(want[i].Metric, want[j].Metric) < 0 })
+	if !samplesMatch(want, got) {
+		return fmt.Errorf("expr: %q, time: %s\n  exp: %s\n  got: %s", et.Expr, et.EvalTime, samplesString(want), samplesString(got))
+	}
+	return nil
+}
+
+func samplesMatch(want, got []promql.Sample) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if !labels.Equal(want[i].Metric, got[i].Metric) || !almostEqual(want[i].F, got[i].F) {
+			return false
+		}
+	}
+	return true
+}
+
+func almostEqual(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= valueTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func alertString(lbls, annotations labels.Labels) string {
+	return fmt.Sprintf("labels: %s annotations: %s", lbls, annotations)
+}
+
+func samplesString(samples []promql.Sample) string {
+	s := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		s = append(s, fmt.Sprintf("%s %g", sample.Metric, sample.F))
+	}
+	return listString(s)
+}
+
+func listString(items []string) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	return "[\n    " + strings.Join(items, "\n    ") + "\n  ]"
+}
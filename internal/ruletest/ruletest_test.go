@@ -0,0 +1,165 @@
+package ruletest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const pageCritical = "availability_alert_page_critical"
+
+// burnRateRuleGroups generates the rules of an availability SLO with the multiwindow, multi-burn-rate alerts
+func burnRateRuleGroups(t *testing.T) []rulefmt.RuleGroup {
+	t.Helper()
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "availability",
+			Namespace:   "shop",
+			Annotations: map[string]string{"osko.dev/magicAlerting": "true", "osko.dev/baseWindow": "5m"},
+		},
+		Spec: openslov1.SLOSpec{
+			Service:    "checkout",
+			Objectives: []openslov1.ObjectivesSpec{{Target: "0.99"}},
+			TimeWindow: []openslov1.TimeWindowSpec{{Duration: "28d", IsRolling: true}},
+		},
+	}
+	sli := &openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: "availability-sli", Namespace: "shop"}}
+	sli.Spec.RatioMetric.Counter = true
+	sli.Spec.RatioMetric.Good.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_requests_total{code="200"}`}}
+	sli.Spec.RatioMetric.Total.MetricSource = openslov1.MetricSource{Type: "Mimir", Spec: openslov1.MetricSourceSpec{Query: `http_requests_total`}}
+
+	cfg := config.Default()
+	rule, err := helpers.CreatePrometheusRule(slo, sli, cfg)
+	if err != nil {
+		t.Fatalf("CreatePrometheusRule() error = %v", err)
+	}
+	mimirGroups, err := helpers.NewMimirRuleGroups(rule, &oskov1alpha1.ConnectionDetails{}, cfg)
+	if err != nil {
+		t.Fatalf("NewMimirRuleGroups() error = %v", err)
+	}
+	groups := make([]rulefmt.RuleGroup, 0, len(mimirGroups))
+	for i := range mimirGroups {
+		group, err := helpers.NewRuleFileGroup(&mimirGroups[i])
+		if err != nil {
+			t.Fatalf("NewRuleFileGroup() error = %v", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func minutes(m int) model.Duration {
+	return model.Duration(time.Duration(m) * time.Minute)
+}
+
+var pageCriticalAlert = Alert{
+	ExpLabels: map[string]string{
+		"long_window":  "1h",
+		"namespace":    "shop",
+		"service":      "checkout",
+		"severity":     "P1",
+		"short_window": "5m",
+		"sli_name":     "availability-sli",
+		"slo_name":     "availability",
+		"window":       "5m",
+	},
+	ExpAnnotations: map[string]string{
+		"description": "The burn rate of SLO availability is consuming error budget faster than acceptable. Short window: 5m, Long window: 1h",
+		"summary":     "SLO Burn Rate Alert",
+	},
+}
+
+func TestBurnRateAlerts(t *testing.T) {
+	groups := burnRateRuleGroups(t)
+
+	tests := []struct {
+		name    string
+		test    TestGroup
+		wantErr string
+	}{
+		{
+			// Every request fails from the first hour on: the 1h burn rate crosses 14.4 after 9 minutes, the alert fires 5 minutes later
+			name: "outage pages",
+			test: TestGroup{
+				InputSeries: []Series{
+					{Series: `http_requests_total{code="200"}`, Values: "0+60x60 3600x60"},
+					{Series: `http_requests_total{code="500"}`, Values: "0x60 60+60x60"},
+				},
+				AlertRuleTests: []AlertTestCase{
+					{EvalTime: minutes(59), Alertname: pageCritical},
+					{EvalTime: minutes(72), Alertname: pageCritical},
+					{EvalTime: minutes(75), Alertname: pageCritical, ExpAlerts: []Alert{pageCriticalAlert}},
+				},
+				PromqlExprTests: []ExprTestCase{
+					{
+						Expr:       `osko_error_budget_ratio{window="5m"}`,
+						EvalTime:   minutes(50),
+						ExpSamples: []Sample{{Labels: `osko_error_budget_ratio{namespace="shop", service="checkout", sli_name="availability-sli", slo_name="availability", window="5m"}`, Value: 0}},
+					},
+					{
+						Expr:       `osko_error_budget_burn_rate{window="1h"}`,
+						EvalTime:   minutes(90),
+						ExpSamples: []Sample{{Labels: `osko_error_budget_burn_rate{namespace="shop", service="checkout", sli_name="availability-sli", slo_name="availability", window="1h"}`, Value: 50}},
+					},
+				},
+			},
+		},
+		{
+			// Half of the error budget rate never reaches the page thresholds
+			name: "slow burn does not page",
+			test: TestGroup{
+				InputSeries: []Series{
+					{Series: `http_requests_total{code="200"}`, Values: "0+995x120"},
+					{Series: `http_requests_total{code="500"}`, Values: "0+5x120"},
+				},
+				AlertRuleTests: []AlertTestCase{
+					{EvalTime: minutes(120), Alertname: pageCritical},
+				},
+				PromqlExprTests: []ExprTestCase{
+					{Expr: `max(osko_error_budget_burn_rate)`, EvalTime: minutes(120), ExpSamples: []Sample{{Labels: `{}`, Value: 0.5}}},
+				},
+			},
+		},
+		{
+			name: "unexpected firing is reported",
+			test: TestGroup{
+				InputSeries: []Series{
+					{Series: `http_requests_total{code="200"}`, Values: "0x30"},
+					{Series: `http_requests_total{code="500"}`, Values: "0+60x30"},
+				},
+				AlertRuleTests: []AlertTestCase{
+					{EvalTime: minutes(30), Alertname: pageCritical},
+				},
+			},
+			wantErr: "alertname: " + pageCritical + ", time: 30m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Run(context.Background(), groups, []TestGroup{tt.test}, 0)
+			if len(results) != 1 {
+				t.Fatalf("expected a result per test group, got %d", len(results))
+			}
+			errs := results[0].Errors
+			if tt.wantErr == "" {
+				for _, err := range errs {
+					t.Error(err)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
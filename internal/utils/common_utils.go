@@ -6,12 +6,9 @@ import (
 	"github.com/go-logr/logr"
 	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"reflect"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
-	"time"
 )
 
 type MetricLabel struct {
@@ -57,68 +54,6 @@ const (
 	ExprFmt         = "sum(increase(%s[%s]))"
 )
 
-// UpdateCondition checks if the condition of the given type is already in the slice
-// if the condition already exists and has the same status, return the unmodified conditions
-// if the condition exists and has a different status, remove it and add the new one
-// if the condition does not exist, add it
-func updateCondition(conditions []metav1.Condition, newCondition metav1.Condition) []metav1.Condition {
-	var existingCondition metav1.Condition
-	exists := false
-
-	for _, condition := range conditions {
-		if condition.Type == newCondition.Type {
-			existingCondition = condition
-			exists = true
-			break
-		}
-	}
-
-	if exists && existingCondition.Status == newCondition.Status {
-		return conditions
-	}
-
-	// Filter the existing condition (if it exists)
-	var updatedConditions []metav1.Condition
-	for _, condition := range conditions {
-		if condition.Type != newCondition.Type {
-			updatedConditions = append(updatedConditions, condition)
-		}
-	}
-
-	// Append the new condition
-	newCondition.LastTransitionTime = metav1.NewTime(time.Now())
-	updatedConditions = append(updatedConditions, newCondition)
-
-	return updatedConditions
-}
-
-func UpdateStatus(ctx context.Context, obj client.Object, r client.Client, conditionType string, status metav1.ConditionStatus, message string) error {
-	// Update the conditions based on provided arguments
-	condition := metav1.Condition{
-		Type:               conditionType,
-		Status:             status,
-		Reason:             string(status),
-		Message:            message,
-		LastTransitionTime: metav1.NewTime(time.Now()),
-	}
-
-	statusField := reflect.ValueOf(obj).Elem().FieldByName("Status")
-
-	conditionsField := statusField.FieldByName("Conditions")
-	if conditionsField.IsValid() && conditionsField.CanSet() {
-		conditions := conditionsField.Interface().([]metav1.Condition)
-		updatedConditions := updateCondition(conditions, condition)
-		conditionsField.Set(reflect.ValueOf(updatedConditions))
-	}
-
-	readyField := statusField.FieldByName("Ready")
-	if readyField.IsValid() && readyField.CanSet() {
-		readyField.SetString(string(status))
-	}
-
-	return r.Status().Update(ctx, obj)
-}
-
 func (m MetricLabel) NewMetricLabelCompiler(rule *monitoringv1.Rule, window string) string {
 	labelString := ""
 	emptyRule := monitoringv1.Rule{}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Condition types shared by all osko and OpenSLO resources
+const (
+	// ConditionReady is True once everything the resource describes is in place
+	ConditionReady = "Ready"
+	// ConditionRulesGenerated is True once the recording and alerting rules of an SLO were generated
+	ConditionRulesGenerated = "RulesGenerated"
+	// ConditionRulesSynced is True once the generated rule groups were written to the ruler
+	ConditionRulesSynced = "RulesSynced"
+	// ConditionDependenciesResolved is True once all resources referenced by the resource exist
+	ConditionDependenciesResolved = "DependenciesResolved"
+	// ConditionDegraded is True while the resource works but is not fully healthy
+	ConditionDegraded = "Degraded"
+	// ConditionBudgetExhausted is True while the error budget left of an SLO is at or below its threshold
+	ConditionBudgetExhausted = "BudgetExhausted"
+)
+
+// Condition reasons shared by all osko and OpenSLO resources
+const (
+	ReasonReconciled           = "Reconciled"
+	ReasonAccepted             = "Accepted"
+	ReasonRulesGenerated       = "RulesGenerated"
+	ReasonRuleGenerationFailed = "RuleGenerationFailed"
+	ReasonInvalidRule          = "InvalidRule"
+	ReasonRulesSynced          = "RulesSynced"
+	ReasonSyncPending          = "SyncPending"
+	ReasonSyncFailed           = "SyncFailed"
+	ReasonDependenciesResolved = "DependenciesResolved"
+	ReasonDatasourceNotFound   = "DatasourceNotFound"
+	ReasonSLINotFound          = "SLINotFound"
+	ReasonInlineSLIFailed      = "InlineSLIFailed"
+	ReasonInvalidTenants       = "InvalidTenants"
+	ReasonFederationDisabled   = "FederationDisabled"
+	ReasonSecretNotFound       = "SecretNotFound"
+	ReasonKeyNotFound          = "KeyNotFound"
+	ReasonConnectionFailed     = "ConnectionFailed"
+	ReasonStatusQueryFailed    = "StatusQueryFailed"
+	ReasonQueryFailed          = "QueryFailed"
+	ReasonInUse                = "InUse"
+	ReasonInvalidSpec          = "InvalidSpec"
+	ReasonBudgetExhausted      = "BudgetExhausted"
+	ReasonBudgetAvailable      = "BudgetAvailable"
+	ReasonAsExpected           = "AsExpected"
+	ReasonSLOsNotReady         = "SLOsNotReady"
+)
+
+// NewCondition builds a condition, the observed generation is filled in when it is set on an object
+func NewCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// ReadyConditions are the Ready and Degraded conditions of a resource that was reconciled without problems
+func ReadyConditions(reason, message string) []metav1.Condition {
+	return []metav1.Condition{
+		NewCondition(ConditionReady, metav1.ConditionTrue, reason, message),
+		NewCondition(ConditionDegraded, metav1.ConditionFalse, ReasonAsExpected, ""),
+	}
+}
+
+// FindCondition returns the condition of the given type from the status of the object, nil when it is not set
+func FindCondition(obj client.Object, conditionType string) *metav1.Condition {
+	statusField := reflect.ValueOf(obj).Elem().FieldByName("Status")
+	if !statusField.IsValid() {
+		return nil
+	}
+	conditionsField := statusField.FieldByName("Conditions")
+	if !conditionsField.IsValid() || conditionsField.Type() != reflect.TypeOf([]metav1.Condition{}) {
+		return nil
+	}
+	return meta.FindStatusCondition(conditionsField.Interface().([]metav1.Condition), conditionType)
+}
+
+// SetCondition sets a condition on the status of any object with a Status.Conditions []metav1.Condition field.
+// It records the generation of the object as observed and keeps the Status.Ready field in sync with the
+// Ready condition. It reports whether the status changed.
+func SetCondition(obj client.Object, condition metav1.Condition) bool {
+	statusField := reflect.ValueOf(obj).Elem().FieldByName("Status")
+	if !statusField.IsValid() {
+		return false
+	}
+
+	conditionsField := statusField.FieldByName("Conditions")
+	if !conditionsField.IsValid() || !conditionsField.CanSet() || conditionsField.Type() != reflect.TypeOf([]metav1.Condition{}) {
+		return false
+	}
+	if condition.ObservedGeneration == 0 {
+		condition.ObservedGeneration = obj.GetGeneration()
+	}
+	conditions := conditionsField.Interface().([]metav1.Condition)
+	changed := meta.SetStatusCondition(&conditions, condition)
+	conditionsField.Set(reflect.ValueOf(conditions))
+
+	observedGenerationField := statusField.FieldByName("ObservedGeneration")
+	if observedGenerationField.IsValid() && observedGenerationField.CanSet() && observedGenerationField.Kind() == reflect.Int64 &&
+		observedGenerationField.Int() != obj.GetGeneration() {
+		observedGenerationField.SetInt(obj.GetGeneration())
+		changed = true
+	}
+
+	readyField := statusField.FieldByName("Ready")
+	if condition.Type == ConditionReady && readyField.IsValid() && readyField.CanSet() && readyField.Kind() == reflect.String &&
+		readyField.String() != string(condition.Status) {
+		readyField.SetString(string(condition.Status))
+		changed = true
+	}
+	return changed
+}
+
+// SetConditions sets all given conditions on the object and reports whether the status changed
+func SetConditions(obj client.Object, conditions ...metav1.Condition) bool {
+	changed := false
+	for _, condition := range conditions {
+		if SetCondition(obj, condition) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// UpdateConditions sets the given conditions and writes the status of the object when it changed
+func UpdateConditions(ctx context.Context, obj client.Object, r client.Client, conditions ...metav1.Condition) error {
+	if !SetConditions(obj, conditions...) {
+		return nil
+	}
+	return r.Status().Update(ctx, obj)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCondition(t *testing.T) {
+	slo := &openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "test-slo", Generation: 4}}
+
+	if !SetCondition(slo, NewCondition(ConditionReady, metav1.ConditionTrue, ReasonReconciled, "SLO reconciled")) {
+		t.Fatal("expected the first condition to change the status")
+	}
+	ready := FindCondition(slo, ConditionReady)
+	if ready == nil || ready.ObservedGeneration != 4 || ready.LastTransitionTime.IsZero() {
+		t.Fatalf("Ready condition = %+v, want generation 4 and a transition time", ready)
+	}
+	if slo.Status.Ready != "True" || slo.Status.ObservedGeneration != 4 {
+		t.Errorf("status = %+v, want ready True at generation 4", slo.Status)
+	}
+
+	if SetCondition(slo, NewCondition(ConditionReady, metav1.ConditionTrue, ReasonReconciled, "SLO reconciled")) {
+		t.Error("setting the same condition again should not change the status")
+	}
+
+	slo.Generation = 5
+	if !SetCondition(slo, NewCondition(ConditionDegraded, metav1.ConditionFalse, ReasonAsExpected, "")) {
+		t.Fatal("expected a new condition to change the status")
+	}
+	if slo.Status.Ready != "True" || slo.Status.ObservedGeneration != 5 {
+		t.Errorf("status = %+v, want ready True at generation 5", slo.Status)
+	}
+
+	if !SetCondition(slo, NewCondition(ConditionReady, metav1.ConditionFalse, ReasonSLINotFound, "SLI Object not found")) {
+		t.Fatal("expected a status change to change the status")
+	}
+	if slo.Status.Ready != "False" {
+		t.Errorf("status.ready = %q, want False", slo.Status.Ready)
+	}
+	if len(slo.Status.Conditions) != 2 {
+		t.Errorf("conditions = %+v, want Ready and Degraded", slo.Status.Conditions)
+	}
+}
+
+func TestSetConditionWithoutConditions(t *testing.T) {
+	configMap := &corev1.ConfigMap{}
+	if SetCondition(configMap, NewCondition(ConditionReady, metav1.ConditionTrue, ReasonReconciled, "")) {
+		t.Error("expected no change on an object without a conditions field")
+	}
+	if FindCondition(configMap, ConditionReady) != nil {
+		t.Error("expected no condition on an object without a conditions field")
+	}
+}
+
+func TestSetConditions(t *testing.T) {
+	mimirRule := &oskov1alpha1.MimirRule{ObjectMeta: metav1.ObjectMeta{Name: "test-rule", Generation: 1}}
+
+	if !SetConditions(mimirRule, ReadyConditions(ReasonReconciled, "MimirRule reconciled")...) {
+		t.Fatal("expected the conditions to change the status")
+	}
+	if degraded := FindCondition(mimirRule, ConditionDegraded); degraded == nil || degraded.Status != metav1.ConditionFalse {
+		t.Errorf("Degraded condition = %+v, want False", degraded)
+	}
+	if mimirRule.Status.Ready != "True" || mimirRule.Status.ObservedGeneration != 1 {
+		t.Errorf("status = %+v, want ready True at generation 1", mimirRule.Status)
+	}
+	if SetConditions(mimirRule, ReadyConditions(ReasonReconciled, "MimirRule reconciled")...) {
+		t.Error("setting the same conditions again should not change the status")
+	}
+}
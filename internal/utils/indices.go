@@ -0,0 +1,33 @@
+package utils
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SLOServiceIndex is the field index of SLOs by their spec.service. The manager registers it once with SLOService,
+// the Service controller and the deployment freeze webhook both list the SLOs of a service through it.
+const SLOServiceIndex = "spec.service"
+
+// SLOService returns the service of an SLO for SLOServiceIndex, SLOs without a service are not indexed
+func SLOService(object client.Object) []string {
+	slo := object.(*openslov1.SLO)
+	if slo.Spec.Service == "" {
+		return nil
+	}
+	return []string{slo.Spec.Service}
+}
+
+// SLOIndicatorRefIndex is the field index of SLOs by their spec.indicatorRef. The manager registers it once with
+// SLOIndicatorRef for the SLO and SLI controllers and the SLI webhook.
+const SLOIndicatorRefIndex = "spec.indicatorRef"
+
+// SLOIndicatorRef returns the SLI an SLO references for SLOIndicatorRefIndex, SLOs with an inline indicator are not
+// indexed
+func SLOIndicatorRef(object client.Object) []string {
+	slo := object.(*openslov1.SLO)
+	if slo.Spec.IndicatorRef == nil {
+		return nil
+	}
+	return []string{*slo.Spec.IndicatorRef}
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ConvertibleTypes are the OpenSLO kinds served in v1 and v2alpha, v1 is the storage version they convert through
+var ConvertibleTypes = []runtime.Object{
+	&openslov1.SLO{},
+	&openslov1.SLI{},
+	&openslov1.Datasource{},
+	&openslov1.AlertPolicy{},
+}
+
+// SetupConversionWebhookWithManager registers the /convert webhook the API server calls to convert between the
+// OpenSLO versions. The scheme of the manager needs both versions registered.
+func SetupConversionWebhookWithManager(mgr ctrl.Manager) error {
+	for _, obj := range ConvertibleTypes {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(obj).Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	openslov2alpha "github.com/oskoperator/osko/api/openslo/v2alpha"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apix "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+func newConversionScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	require.NoError(t, openslov2alpha.AddToScheme(scheme))
+	return scheme
+}
+
+func TestConvertibleTypes(t *testing.T) {
+	scheme := newConversionScheme(t)
+	for _, obj := range ConvertibleTypes {
+		ok, err := conversion.IsConvertible(scheme, obj)
+		require.NoError(t, err)
+		assert.True(t, ok, "%T should be convertible", obj)
+	}
+}
+
+func convert(t *testing.T, handler http.Handler, desiredAPIVersion string, object string) map[string]any {
+	review := apix.ConversionReview{
+		Request: &apix.ConversionRequest{
+			UID:               "uid",
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           []runtime.RawExtension{{Raw: []byte(object)}},
+		},
+	}
+	review.APIVersion = "apiextensions.k8s.io/v1"
+	review.Kind = "ConversionReview"
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	response := apix.ConversionReview{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.NotNil(t, response.Response)
+	require.Equal(t, "Success", response.Response.Result.Status, response.Response.Result.Message)
+	require.Len(t, response.Response.ConvertedObjects, 1)
+	converted := map[string]any{}
+	require.NoError(t, json.Unmarshal(response.Response.ConvertedObjects[0].Raw, &converted))
+	return converted
+}
+
+func TestConversionWebhookRoundTrip(t *testing.T) {
+	handler := conversion.NewWebhookHandler(newConversionScheme(t))
+	v2alpha := `{
+		"apiVersion": "openslo.com/v2alpha",
+		"kind": "SLO",
+		"metadata": {"name": "checkout", "namespace": "shop"},
+		"spec": {
+			"service": "checkout",
+			"sli": {
+				"metadata": {"name": "checkout-sli"},
+				"spec": {"thresholdMetric": {"dataSourceRef": "mimir", "type": "Mimir", "spec": {"query": "up"}}}
+			},
+			"objectives": [{"target": "0.99", "sliRef": "latency-sli", "compositeWeight": "1"}]
+		}
+	}`
+
+	v1 := convert(t, handler, "openslo.com/v1", v2alpha)
+	assert.Equal(t, "openslo.com/v1", v1["apiVersion"])
+	spec := v1["spec"].(map[string]any)
+	metricSource := spec["indicator"].(map[string]any)["spec"].(map[string]any)["thresholdMetric"].(map[string]any)["metricSource"].(map[string]any)
+	assert.Equal(t, "mimir", metricSource["metricSourceRef"])
+	assert.Equal(t, "latency-sli", spec["objectives"].([]any)[0].(map[string]any)["indicatorRef"])
+
+	raw, err := json.Marshal(v1)
+	require.NoError(t, err)
+	back, err := json.Marshal(convert(t, handler, "openslo.com/v2alpha", string(raw)))
+	require.NoError(t, err)
+	expected, got := &openslov2alpha.SLO{}, &openslov2alpha.SLO{}
+	require.NoError(t, json.Unmarshal([]byte(v2alpha), expected))
+	require.NoError(t, json.Unmarshal(back, got))
+	assert.Equal(t, expected.Spec, got.Spec)
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/helpers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DatasourceValidator rejects Datasources OSKO can not connect to
+type DatasourceValidator struct{}
+
+//+kubebuilder:webhook:path=/validate-openslo-com-v1-datasource,mutating=false,failurePolicy=fail,sideEffects=None,groups=openslo.com,resources=datasources,verbs=create;update,versions=v1,name=vdatasource.osko.dev,admissionReviewVersions=v1
+
+// SetupDatasourceWebhookWithManager registers the Datasource webhook
+func SetupDatasourceWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&openslov1.Datasource{}).
+		WithValidator(&DatasourceValidator{}).
+		Complete()
+}
+
+var _ admission.CustomValidator = &DatasourceValidator{}
+
+func (v *DatasourceValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateDatasource(obj)
+}
+
+func (v *DatasourceValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateDatasource(newObj)
+}
+
+func (v *DatasourceValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateDatasource(obj runtime.Object) error {
+	ds, ok := obj.(*openslov1.Datasource)
+	if !ok {
+		return fmt.Errorf("expected a Datasource but got %T", obj)
+	}
+	if errs := helpers.ValidateDatasource(ds); len(errs) > 0 {
+		return apierrors.NewInvalid(openslov1.GroupVersion.WithKind("Datasource").GroupKind(), ds.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Modes of the deployment freeze webhook
+const (
+	FreezeModeDisabled = "disabled"
+	FreezeModeWarn     = "warn"
+	FreezeModeEnforce  = "enforce"
+)
+
+const (
+	// ServiceLabel links a Deployment or StatefulSet to the spec.service of its SLOs
+	ServiceLabel = "osko.dev/service"
+	// FreezeOverrideAnnotation lets a rollout through a freeze, its value is the reason recorded in the event
+	FreezeOverrideAnnotation = "osko.dev/freezeOverride"
+)
+
+// DeploymentFreezeValidator gates rollouts of Deployments and StatefulSets labeled with a service while an SLO of the
+// service in the same namespace has exhausted its error budget
+type DeploymentFreezeValidator struct {
+	Client   client.Reader
+	Recorder record.EventRecorder
+	// Mode is FreezeModeEnforce to reject frozen rollouts, FreezeModeWarn to only warn about them
+	Mode string
+}
+
+//+kubebuilder:webhook:path=/validate-apps-v1-deployment,mutating=false,failurePolicy=ignore,sideEffects=NoneOnDryRun,groups=apps,resources=deployments,verbs=update,versions=v1,name=vdeploymentfreeze.osko.dev,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-apps-v1-statefulset,mutating=false,failurePolicy=ignore,sideEffects=NoneOnDryRun,groups=apps,resources=statefulsets,verbs=update,versions=v1,name=vstatefulsetfreeze.osko.dev,admissionReviewVersions=v1
+
+// SetupDeploymentFreezeWebhookWithManager registers the deployment freeze webhook for Deployments and StatefulSets
+func SetupDeploymentFreezeWebhookWithManager(mgr ctrl.Manager, mode string) error {
+	if mode != FreezeModeWarn && mode != FreezeModeEnforce {
+		return fmt.Errorf("unsupported deployment freeze mode %q, must be %s or %s", mode, FreezeModeWarn, FreezeModeEnforce)
+	}
+	validator := &DeploymentFreezeValidator{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("deployment-freeze-webhook"),
+		Mode:     mode,
+	}
+	for _, obj := range []runtime.Object{&appsv1.Deployment{}, &appsv1.StatefulSet{}} {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(obj).WithValidator(validator).Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ admission.CustomValidator = &DeploymentFreezeValidator{}
+
+// ValidateCreate allows every new workload, there is nothing running yet to protect
+func (v *DeploymentFreezeValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate gates changes of the pod template, which roll out new pods. Scaling is always allowed.
+func (v *DeploymentFreezeValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	log := ctrllog.FromContext(ctx)
+
+	workload, ok := newObj.(client.Object)
+	if !ok {
+		return nil, nil
+	}
+	service := workload.GetLabels()[ServiceLabel]
+	if service == "" {
+		return nil, nil
+	}
+	oldTemplate, newTemplate := podTemplate(oldObj), podTemplate(newObj)
+	if oldTemplate == nil || newTemplate == nil || equality.Semantic.DeepEqual(oldTemplate, newTemplate) {
+		return nil, nil
+	}
+
+	exhausted, err := v.exhaustedSLOs(ctx, workload.GetNamespace(), service)
+	if err != nil {
+		// Budget state is unknown, fail open like the failure policy of the webhook
+		log.Error(err, "Failed to list the SLOs of the service", "service", service)
+		return nil, nil
+	}
+	if len(exhausted) == 0 {
+		v.record(ctx, workload, corev1.EventTypeNormal, "DeployAllowed",
+			fmt.Sprintf("Error budget of service %s is available", service))
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("error budget of service %s is exhausted by SLOs %s", service, strings.Join(exhausted, ", "))
+	if reason := workload.GetAnnotations()[FreezeOverrideAnnotation]; reason != "" {
+		v.record(ctx, workload, corev1.EventTypeNormal, "DeployFreezeOverridden",
+			fmt.Sprintf("Deploy freeze overridden (%s): %s", reason, message))
+		return admission.Warnings{"deploy freeze overridden: " + message}, nil
+	}
+	if v.Mode == FreezeModeWarn {
+		v.record(ctx, workload, corev1.EventTypeWarning, "DeployFreezeWarning", "Deploying while "+message)
+		return admission.Warnings{"deploy freeze: " + message}, nil
+	}
+	v.record(ctx, workload, corev1.EventTypeWarning, "DeployFrozen", "Deploy rejected, "+message)
+	return nil, fmt.Errorf("deploy freeze: %s, set the %s annotation to deploy anyway", message, FreezeOverrideAnnotation)
+}
+
+// ValidateDelete allows every deletion
+func (v *DeploymentFreezeValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// exhaustedSLOs returns the names of the SLOs of the service whose BudgetExhausted condition is True
+func (v *DeploymentFreezeValidator) exhaustedSLOs(ctx context.Context, namespace, service string) ([]string, error) {
+	slos := &openslov1.SLOList{}
+	if err := v.Client.List(ctx, slos, client.InNamespace(namespace), client.MatchingFields{utils.SLOServiceIndex: service}); err != nil {
+		return nil, err
+	}
+	var exhausted []string
+	for _, slo := range slos.Items {
+		if apimeta.IsStatusConditionTrue(slo.Status.Conditions, utils.ConditionBudgetExhausted) {
+			exhausted = append(exhausted, slo.Name)
+		}
+	}
+	sort.Strings(exhausted)
+	return exhausted, nil
+}
+
+// record emits an event for the decision on the workload, unless the request is a dry run
+func (v *DeploymentFreezeValidator) record(ctx context.Context, workload client.Object, eventType, reason, message string) {
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.DryRun != nil && *req.DryRun {
+		return
+	}
+	if v.Recorder != nil {
+		v.Recorder.Event(workload, eventType, reason, message)
+	}
+}
+
+func podTemplate(obj runtime.Object) *corev1.PodTemplateSpec {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template
+	}
+	return nil
+}
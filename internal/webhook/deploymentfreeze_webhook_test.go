@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newFreezeTestSLO(name, service string, exhausted bool) *openslov1.SLO {
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       openslov1.SLOSpec{Service: service},
+	}
+	status := metav1.ConditionFalse
+	if exhausted {
+		status = metav1.ConditionTrue
+	}
+	utils.SetCondition(slo, utils.NewCondition(utils.ConditionBudgetExhausted, status, "", ""))
+	return slo
+}
+
+func newFreezeTestDeployment(image string, labels, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", Labels: labels, Annotations: annotations},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}},
+		},
+	}
+}
+
+func TestDeploymentFreezeValidator(t *testing.T) {
+	shop := map[string]string{ServiceLabel: "shop"}
+	override := map[string]string{FreezeOverrideAnnotation: "INC-123 hotfix"}
+	scaled := newFreezeTestDeployment("app:v1", shop, nil)
+	scaled.Spec.Replicas = ptr.To[int32](5)
+
+	tests := []struct {
+		name      string
+		mode      string
+		slos      []client.Object
+		newObj    *appsv1.Deployment
+		wantErr   bool
+		wantWarn  bool
+		wantEvent string
+	}{
+		{
+			name:   "unlabeled workload",
+			mode:   FreezeModeEnforce,
+			slos:   []client.Object{newFreezeTestSLO("availability", "shop", true)},
+			newObj: newFreezeTestDeployment("app:v2", nil, nil),
+		},
+		{
+			name:   "scaling only",
+			mode:   FreezeModeEnforce,
+			slos:   []client.Object{newFreezeTestSLO("availability", "shop", true)},
+			newObj: scaled,
+		},
+		{
+			name:      "budget available",
+			mode:      FreezeModeEnforce,
+			slos:      []client.Object{newFreezeTestSLO("availability", "shop", false), newFreezeTestSLO("latency", "search", true)},
+			newObj:    newFreezeTestDeployment("app:v2", shop, nil),
+			wantEvent: "Normal DeployAllowed Error budget of service shop is available",
+		},
+		{
+			name:      "budget exhausted",
+			mode:      FreezeModeEnforce,
+			slos:      []client.Object{newFreezeTestSLO("latency", "shop", true), newFreezeTestSLO("availability", "shop", true)},
+			newObj:    newFreezeTestDeployment("app:v2", shop, nil),
+			wantErr:   true,
+			wantEvent: "Warning DeployFrozen Deploy rejected, error budget of service shop is exhausted by SLOs availability, latency",
+		},
+		{
+			name:      "budget exhausted in warn mode",
+			mode:      FreezeModeWarn,
+			slos:      []client.Object{newFreezeTestSLO("availability", "shop", true)},
+			newObj:    newFreezeTestDeployment("app:v2", shop, nil),
+			wantWarn:  true,
+			wantEvent: "Warning DeployFreezeWarning Deploying while error budget of service shop is exhausted by SLOs availability",
+		},
+		{
+			name:      "override",
+			mode:      FreezeModeEnforce,
+			slos:      []client.Object{newFreezeTestSLO("availability", "shop", true)},
+			newObj:    newFreezeTestDeployment("app:v2", shop, override),
+			wantWarn:  true,
+			wantEvent: "Normal DeployFreezeOverridden Deploy freeze overridden (INC-123 hotfix)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, openslov1.AddToScheme(scheme))
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.slos...).
+				WithIndex(&openslov1.SLO{}, utils.SLOServiceIndex, utils.SLOService).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			v := &DeploymentFreezeValidator{Client: c, Recorder: recorder, Mode: tt.mode}
+
+			oldObj := newFreezeTestDeployment("app:v1", tt.newObj.Labels, nil)
+			warnings, err := v.ValidateUpdate(context.Background(), oldObj, tt.newObj)
+			assert.Equal(t, tt.wantErr, err != nil, "error = %v", err)
+			assert.Equal(t, tt.wantWarn, len(warnings) > 0, "warnings = %v", warnings)
+			if tt.wantEvent == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, tt.wantEvent)
+		})
+	}
+}
+
+func TestDeploymentFreezeValidatorDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newFreezeTestSLO("availability", "shop", true)).
+		WithIndex(&openslov1.SLO{}, utils.SLOServiceIndex, utils.SLOService).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	v := &DeploymentFreezeValidator{Client: c, Recorder: recorder, Mode: FreezeModeEnforce}
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)},
+	})
+	labels := map[string]string{ServiceLabel: "shop"}
+	_, err := v.ValidateUpdate(ctx, newFreezeTestDeployment("app:v1", labels, nil), newFreezeTestDeployment("app:v2", labels, nil))
+	assert.Error(t, err)
+	assert.Empty(t, recorder.Events)
+}
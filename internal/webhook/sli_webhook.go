@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/helpers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SLIValidator rejects SLIs with queries that do not parse as PromQL, and the deletion of SLIs that SLOs still use
+type SLIValidator struct {
+	// Client lists the SLOs using an SLI, it must have the utils.SLOIndicatorRefIndex
+	Client client.Reader
+}
+
+//+kubebuilder:webhook:path=/validate-openslo-com-v1-sli,mutating=false,failurePolicy=fail,sideEffects=None,groups=openslo.com,resources=slis,verbs=create;update;delete,versions=v1,name=vsli.osko.dev,admissionReviewVersions=v1
+
+// SetupSLIWebhookWithManager registers the SLI webhook
+func SetupSLIWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&openslov1.SLI{}).
+		WithValidator(&SLIValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+var _ admission.CustomValidator = &SLIValidator{}
+
+func (v *SLIValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateSLI(obj)
+}
+
+func (v *SLIValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateSLI(newObj)
+}
+
+// ValidateDelete rejects the deletion of an SLI that SLOs still reference, they would lose their indicator
+func (v *SLIValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	sli, ok := obj.(*openslov1.SLI)
+	if !ok {
+		return nil, fmt.Errorf("expected an SLI but got %T", obj)
+	}
+	consumers, err := helpers.SLIConsumers(ctx, v.Client, sli)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if len(consumers) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(consumers))
+	for _, slo := range consumers {
+		names = append(names, slo.Name)
+	}
+	sort.Strings(names)
+	return nil, apierrors.NewForbidden(openslov1.GroupVersion.WithResource("slis").GroupResource(), sli.Name,
+		fmt.Errorf("the SLI is still used by SLOs %s, change or delete them first", strings.Join(names, ", ")))
+}
+
+func validateSLI(obj runtime.Object) error {
+	sli, ok := obj.(*openslov1.SLI)
+	if !ok {
+		return fmt.Errorf("expected an SLI but got %T", obj)
+	}
+	if errs := helpers.ValidateSLI(sli); len(errs) > 0 {
+		return apierrors.NewInvalid(openslov1.GroupVersion.WithKind("SLI").GroupKind(), sli.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	"github.com/oskoperator/osko/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSLIValidatorRejectsDeletionInUse(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, openslov1.AddToScheme(scheme))
+	now := metav1.Now()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}, Spec: openslov1.SLOSpec{IndicatorRef: ptr.To("requests")}},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: openslov1.SLOSpec{IndicatorRef: ptr.To("requests")}},
+		&openslov1.SLO{
+			ObjectMeta: metav1.ObjectMeta{Name: "leaving", Namespace: "shop", DeletionTimestamp: &now, Finalizers: []string{"finalizer.slo.osko.dev"}},
+			Spec:       openslov1.SLOSpec{IndicatorRef: ptr.To("latency")},
+		},
+		&openslov1.SLO{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "other"}, Spec: openslov1.SLOSpec{IndicatorRef: ptr.To("unused")}},
+	).WithIndex(&openslov1.SLO{}, utils.SLOIndicatorRefIndex, utils.SLOIndicatorRef).Build()
+	v := &SLIValidator{Client: c}
+
+	tests := []struct {
+		name    string
+		sli     string
+		wantErr string
+	}{
+		{name: "in use", sli: "requests", wantErr: "the SLI is still used by SLOs api, checkout"},
+		{name: "only used by an SLO being deleted", sli: "latency"},
+		{name: "used in another namespace", sli: "unused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sli := &openslov1.SLI{ObjectMeta: metav1.ObjectMeta{Name: tt.sli, Namespace: "shop"}}
+			_, err := v.ValidateDelete(context.Background(), sli)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, apierrors.IsForbidden(err), "expected a forbidden error, got %v", err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/oskoperator/osko/internal/config"
+	"github.com/oskoperator/osko/internal/helpers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SLODefaulter materializes the settings an SLO runs with into the SLO, so the stored object shows them
+type SLODefaulter struct {
+	// Client reads the Namespace and the SLODefaults of the SLO for its default Datasource
+	Client client.Reader
+	// Config holds the operator settings, the default base window among them
+	Config *config.Store
+}
+
+// SLOValidator rejects SLOs the SLO controller could not reconcile
+type SLOValidator struct{}
+
+//+kubebuilder:webhook:path=/mutate-openslo-com-v1-slo,mutating=true,failurePolicy=fail,sideEffects=None,groups=openslo.com,resources=slos,verbs=create;update,versions=v1,name=mslo.osko.dev,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-openslo-com-v1-slo,mutating=false,failurePolicy=fail,sideEffects=None,groups=openslo.com,resources=slos,verbs=create;update,versions=v1,name=vslo.osko.dev,admissionReviewVersions=v1
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups=osko.dev,resources=slodefaults,verbs=get
+
+// SetupSLOWebhookWithManager registers the SLO webhooks
+func SetupSLOWebhookWithManager(mgr ctrl.Manager, cfg *config.Store) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&openslov1.SLO{}).
+		WithDefaulter(&SLODefaulter{Client: mgr.GetAPIReader(), Config: cfg}).
+		WithValidator(&SLOValidator{}).
+		Complete()
+}
+
+var _ admission.CustomDefaulter = &SLODefaulter{}
+
+// Default fills in the time window, target and targetPercent, the base window and the Datasource from the
+// osko.dev/datasourceRef annotation of the Namespace, and normalizes durations. The base window and Datasource
+// are left unset when the SLODefaults of the namespace supply them.
+func (d *SLODefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	slo, ok := obj.(*openslov1.SLO)
+	if !ok {
+		return fmt.Errorf("expected an SLO but got %T", obj)
+	}
+	var defaults *oskov1alpha1.SLODefaults
+	if d.Client != nil {
+		var err error
+		if defaults, err = helpers.GetSLODefaults(ctx, d.Client, slo.Namespace); err != nil {
+			return err
+		}
+	}
+	// Settings of the SLODefaults are left to the controllers, so the SLO follows later changes of them
+	inheritsBaseWindow := slo.Annotations["osko.dev/baseWindow"] == "" && defaults != nil && defaults.Spec.BaseWindow != ""
+	helpers.SetSLODefaults(slo, d.Config.Get())
+	if inheritsBaseWindow {
+		delete(slo.Annotations, "osko.dev/baseWindow")
+	}
+
+	if slo.Annotations["osko.dev/datasourceRef"] != "" || d.Client == nil {
+		return nil
+	}
+	if defaults != nil && defaults.Spec.DatasourceRef != "" {
+		return nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: slo.Namespace}, namespace); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ref := namespace.Annotations["osko.dev/datasourceRef"]; ref != "" {
+		slo.Annotations["osko.dev/datasourceRef"] = ref
+	}
+	return nil
+}
+
+var _ admission.CustomValidator = &SLOValidator{}
+
+func (v *SLOValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateSLO(obj)
+}
+
+func (v *SLOValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateSLO(newObj)
+}
+
+func (v *SLOValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateSLO(obj runtime.Object) error {
+	slo, ok := obj.(*openslov1.SLO)
+	if !ok {
+		return fmt.Errorf("expected an SLO but got %T", obj)
+	}
+	if errs := helpers.ValidateSLO(slo); len(errs) > 0 {
+		return apierrors.NewInvalid(openslov1.GroupVersion.WithKind("SLO").GroupKind(), slo.Name, errs)
+	}
+	return nil
+}
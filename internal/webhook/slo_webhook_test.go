@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	openslov1 "github.com/oskoperator/osko/api/openslo/v1"
+	oskov1alpha1 "github.com/oskoperator/osko/api/osko/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSLODefaulter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{"osko.dev/datasourceRef": "mimir-shop"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+	).Build()
+	d := &SLODefaulter{Client: c}
+
+	tests := []struct {
+		name        string
+		namespace   string
+		annotations map[string]string
+		want        string
+	}{
+		{name: "namespace default", namespace: "shop", want: "mimir-shop"},
+		{name: "explicit datasource", namespace: "shop", annotations: map[string]string{"osko.dev/datasourceRef": "mimir-infra"}, want: "mimir-infra"},
+		{name: "no namespace default", namespace: "plain"},
+		{name: "namespace not found", namespace: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &openslov1.SLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: tt.namespace, Annotations: tt.annotations},
+				Spec:       openslov1.SLOSpec{Objectives: []openslov1.ObjectivesSpec{{TargetPercent: "99.9"}}},
+			}
+			require.NoError(t, d.Default(context.Background(), slo))
+			assert.Equal(t, tt.want, slo.Annotations["osko.dev/datasourceRef"])
+			assert.Equal(t, "0.999", slo.Spec.Objectives[0].Target)
+			assert.Equal(t, openslov1.Duration("28d"), slo.Spec.TimeWindow[0].Duration)
+		})
+	}
+}
+
+func TestSLODefaulterLeavesSLODefaultsSettings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, oskov1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{"osko.dev/datasourceRef": "mimir-shop"}}},
+		&oskov1alpha1.SLODefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: oskov1alpha1.SLODefaultsName, Namespace: "shop"},
+			Spec:       oskov1alpha1.SLODefaultsSpec{DatasourceRef: "mimir-team", BaseWindow: "10m"},
+		},
+	).Build()
+	d := &SLODefaulter{Client: c}
+
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec:       openslov1.SLOSpec{Objectives: []openslov1.ObjectivesSpec{{TargetPercent: "99.9"}}},
+	}
+	require.NoError(t, d.Default(context.Background(), slo))
+	assert.NotContains(t, slo.Annotations, "osko.dev/datasourceRef")
+	assert.NotContains(t, slo.Annotations, "osko.dev/baseWindow")
+
+	explicit := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", Annotations: map[string]string{"osko.dev/baseWindow": "60m"}},
+		Spec:       openslov1.SLOSpec{Objectives: []openslov1.ObjectivesSpec{{TargetPercent: "99.9"}}},
+	}
+	require.NoError(t, d.Default(context.Background(), explicit))
+	assert.Equal(t, "1h", explicit.Annotations["osko.dev/baseWindow"])
+}
+
+func TestSLOValidator(t *testing.T) {
+	slo := &openslov1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+		Spec: openslov1.SLOSpec{
+			IndicatorRef: ptr.To("checkout-sli"),
+			Objectives:   []openslov1.ObjectivesSpec{{Target: "0.999"}},
+		},
+	}
+	v := &SLOValidator{}
+
+	_, err := v.ValidateCreate(context.Background(), slo)
+	require.NoError(t, err)
+
+	invalid := slo.DeepCopy()
+	invalid.Spec.Objectives[0].Target = "1"
+	invalid.Spec.IndicatorRef = nil
+	_, err = v.ValidateUpdate(context.Background(), slo, invalid)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsInvalid(err))
+
+	var fields []string
+	for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	assert.Equal(t, []string{"spec.indicatorRef", "spec.objectives[0].target"}, fields)
+}
+
+func TestDatasourceValidator(t *testing.T) {
+	ds := &openslov1.Datasource{ObjectMeta: metav1.ObjectMeta{Name: "graphite"}, Spec: openslov1.DatasourceSpec{Type: "graphite"}}
+	_, err := (&DatasourceValidator{}).ValidateCreate(context.Background(), ds)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `spec.type: Unsupported value: "graphite": supported values: "mimir"`)
+}